@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
@@ -29,10 +30,10 @@ type BotAPI struct {
 	Self   User       `json:"-"`
 	Client HTTPClient `json:"-"`
 
-	apiEndpoint     string
-	fileEndpoint    string
-	logger          any
-	loggingDisabled bool
+	// botAPIConfig holds the settings of the options the bot was created
+	// with. Its client, debug and buffer are replaced by Client, Debug and
+	// Buffer.
+	botAPIConfig
 
 	stoppers []context.CancelFunc
 	mu       sync.RWMutex
@@ -69,7 +70,11 @@ func NewBotAPIWithClient(token, apiEndpoint string, client HTTPClient) (*BotAPI,
 //
 // It requires a token, provided by @BotFather on Telegram.
 func NewBotAPIWithOptions(token string, options ...BotAPIOption) (*BotAPI, error) {
-	config := defaultBotAPIConfig()
+	return newBotAPI(token, defaultBotAPIConfig(), options)
+}
+
+// newBotAPI creates a new BotAPI instance with the options applied to config.
+func newBotAPI(token string, config botAPIConfig, options []BotAPIOption) (*BotAPI, error) {
 	for _, option := range options {
 		if err := option(&config); err != nil {
 			return nil, err
@@ -77,14 +82,11 @@ func NewBotAPIWithOptions(token string, options ...BotAPIOption) (*BotAPI, error
 	}
 
 	bot := &BotAPI{
-		Token:           token,
-		Debug:           config.debug,
-		Buffer:          config.buffer,
-		Client:          config.client,
-		apiEndpoint:     config.apiEndpoint,
-		fileEndpoint:    config.fileEndpoint,
-		logger:          config.logger,
-		loggingDisabled: config.loggingDisabled,
+		Token:        token,
+		Debug:        config.debug,
+		Buffer:       config.buffer,
+		Client:       config.client,
+		botAPIConfig: config,
 	}
 
 	self, err := bot.GetMe()
@@ -132,6 +134,10 @@ func (bot *BotAPI) MakeRequest(endpoint string, params Params) (*APIResponse, er
 }
 
 func (bot *BotAPI) MakeRequestWithContext(ctx context.Context, endpoint string, params Params) (*APIResponse, error) {
+	if bot.dryRun != nil && !isReadOnlyMethod(endpoint) {
+		return bot.dryRun.request(ctx, bot.Self, endpoint, params, nil)
+	}
+
 	return bot.executeRequest(ctx, endpoint, buildFormPayload(params), requestDebug{params: params})
 }
 
@@ -149,6 +155,9 @@ func (bot *BotAPI) executeRequest(ctx context.Context, endpoint string, payload
 	if payload.contentType != "" {
 		req.Header.Set("Content-Type", payload.contentType)
 	}
+	if payload.contentLength > 0 {
+		req.ContentLength = payload.contentLength
+	}
 
 	resp, err := bot.Client.Do(req)
 	if err != nil {
@@ -211,15 +220,38 @@ func (bot *BotAPI) UploadFiles(endpoint string, params Params, files []RequestFi
 }
 
 func (bot *BotAPI) UploadFilesWithContext(ctx context.Context, endpoint string, params Params, files []RequestFile) (*APIResponse, error) {
-	payload, err := buildMultipartPayload(params, files)
+	if bot.dryRun != nil && !isReadOnlyMethod(endpoint) {
+		return bot.dryRun.request(ctx, bot.Self, endpoint, params, files)
+	}
+
+	if bot.localMode {
+		var err error
+		params, files, err = localFileReferences(params, files)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return bot.MakeRequestWithContext(ctx, endpoint, params)
+		}
+	}
+
+	ctx, tracker, stop := newUploadTracker(ctx, files)
+	defer stop()
+
+	payload, err := buildMultipartPayload(params, files, tracker)
 	if err != nil {
 		return nil, err
 	}
 
-	return bot.executeRequest(ctx, endpoint, payload, requestDebug{
+	resp, err := bot.executeRequest(ctx, endpoint, payload, requestDebug{
 		params:    params,
 		fileCount: len(files),
 	})
+	if err != nil && errors.Is(context.Cause(ctx), ErrUploadStalled) {
+		return resp, fmt.Errorf("%w: no bytes sent for %s", ErrUploadStalled, tracker.timeout)
+	}
+
+	return resp, err
 }
 
 // GetFileDirectURL returns direct URL to file
@@ -235,7 +267,13 @@ func (bot *BotAPI) GetFileDirectURL(fileID string) (string, error) {
 }
 
 // FileURL returns a full path to the download URL for a File using this bot's file endpoint.
+//
+// In local mode, files are stored on the server's disk and a file:// URL is returned.
 func (bot *BotAPI) FileURL(file File) string {
+	if bot.localMode && filepath.IsAbs(file.FilePath) {
+		return "file://" + filepath.ToSlash(file.FilePath)
+	}
+
 	return fmt.Sprintf(bot.fileEndpoint, bot.Token, file.FilePath)
 }
 
@@ -273,6 +311,12 @@ func (bot *BotAPI) Request(c Chattable) (*APIResponse, error) {
 }
 
 func (bot *BotAPI) RequestWithContext(ctx context.Context, c Chattable) (*APIResponse, error) {
+	if v, ok := c.(Validator); ok && bot.validate {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	params, err := c.params()
 	if err != nil {
 		return nil, err
@@ -293,7 +337,11 @@ func (bot *BotAPI) RequestWithContext(ctx context.Context, c Chattable) (*APIRes
 // Send will send a Chattable item to Telegram and provides the
 // returned Message.
 func (bot *BotAPI) Send(c Chattable) (Message, error) {
-	resp, err := bot.Request(c)
+	return bot.SendWithContext(context.Background(), c)
+}
+
+func (bot *BotAPI) SendWithContext(ctx context.Context, c Chattable) (Message, error) {
+	resp, err := bot.RequestWithContext(ctx, c)
 	if err != nil {
 		return Message{}, err
 	}
@@ -304,83 +352,67 @@ func (bot *BotAPI) Send(c Chattable) (Message, error) {
 	return message, err
 }
 
-func (bot *BotAPI) requestBool(c Chattable) (bool, error) {
-	resp, err := bot.Request(c)
-	if err != nil {
-		return false, err
-	}
-
-	var ok bool
-	err = json.Unmarshal(resp.Result, &ok)
-
-	return ok, err
-}
-
 // SendLivePhoto sends a live photo and returns the resulting message.
 func (bot *BotAPI) SendLivePhoto(config SendLivePhotoConfig) (Message, error) {
-	return bot.Send(config)
+	return bot.SendLivePhotoWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) SendLivePhotoWithContext(ctx context.Context, config SendLivePhotoConfig) (Message, error) {
+	return bot.SendWithContext(ctx, config)
 }
 
 // SendRichMessage sends a rich message and returns the resulting message.
 func (bot *BotAPI) SendRichMessage(config SendRichMessageConfig) (Message, error) {
-	return bot.Send(config)
+	return bot.SendRichMessageWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) SendRichMessageWithContext(ctx context.Context, config SendRichMessageConfig) (Message, error) {
+	return bot.SendWithContext(ctx, config)
 }
 
 // SendRichMessageDraft streams a partial rich message draft.
 func (bot *BotAPI) SendRichMessageDraft(config SendRichMessageDraftConfig) (bool, error) {
-	return bot.requestBool(config)
+	return bot.SendRichMessageDraftWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) SendRichMessageDraftWithContext(ctx context.Context, config SendRichMessageDraftConfig) (bool, error) {
+	return Call(ctx, bot, config)
 }
 
 // SendMediaGroup sends a media group and returns the resulting messages.
 func (bot *BotAPI) SendMediaGroup(config MediaGroupConfig) ([]Message, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return nil, err
-	}
-
-	var messages []Message
-	err = json.Unmarshal(resp.Result, &messages)
+	return bot.SendMediaGroupWithContext(context.Background(), config)
+}
 
-	return messages, err
+func (bot *BotAPI) SendMediaGroupWithContext(ctx context.Context, config MediaGroupConfig) ([]Message, error) {
+	return Call(ctx, bot, config)
 }
 
 // PostStory posts a story on behalf of a managed business account.
 func (bot *BotAPI) PostStory(config PostStoryConfig) (Story, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return Story{}, err
-	}
-
-	var story Story
-	err = json.Unmarshal(resp.Result, &story)
+	return bot.PostStoryWithContext(context.Background(), config)
+}
 
-	return story, err
+func (bot *BotAPI) PostStoryWithContext(ctx context.Context, config PostStoryConfig) (Story, error) {
+	return Call(ctx, bot, config)
 }
 
 // EditStory edits a story posted by a managed business account.
 func (bot *BotAPI) EditStory(config EditStoryConfig) (Story, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return Story{}, err
-	}
-
-	var story Story
-	err = json.Unmarshal(resp.Result, &story)
+	return bot.EditStoryWithContext(context.Background(), config)
+}
 
-	return story, err
+func (bot *BotAPI) EditStoryWithContext(ctx context.Context, config EditStoryConfig) (Story, error) {
+	return Call(ctx, bot, config)
 }
 
 // RepostStory reposts a story to a managed business account.
 func (bot *BotAPI) RepostStory(config RepostStoryConfig) (Story, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return Story{}, err
-	}
-
-	var story Story
-	err = json.Unmarshal(resp.Result, &story)
+	return bot.RepostStoryWithContext(context.Background(), config)
+}
 
-	return story, err
+func (bot *BotAPI) RepostStoryWithContext(ctx context.Context, config RepostStoryConfig) (Story, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetUserProfilePhotos gets a user's profile photos.
@@ -388,56 +420,40 @@ func (bot *BotAPI) RepostStory(config RepostStoryConfig) (Story, error) {
 // It requires UserID.
 // Offset and Limit are optional.
 func (bot *BotAPI) GetUserProfilePhotos(config UserProfilePhotosConfig) (UserProfilePhotos, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return UserProfilePhotos{}, err
-	}
-
-	var profilePhotos UserProfilePhotos
-	err = json.Unmarshal(resp.Result, &profilePhotos)
+	return bot.GetUserProfilePhotosWithContext(context.Background(), config)
+}
 
-	return profilePhotos, err
+func (bot *BotAPI) GetUserProfilePhotosWithContext(ctx context.Context, config UserProfilePhotosConfig) (UserProfilePhotos, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetUserProfileAudios gets a user's profile audios.
 func (bot *BotAPI) GetUserProfileAudios(config UserProfileAudiosConfig) (UserProfileAudios, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return UserProfileAudios{}, err
-	}
-
-	var profileAudios UserProfileAudios
-	err = json.Unmarshal(resp.Result, &profileAudios)
+	return bot.GetUserProfileAudiosWithContext(context.Background(), config)
+}
 
-	return profileAudios, err
+func (bot *BotAPI) GetUserProfileAudiosWithContext(ctx context.Context, config UserProfileAudiosConfig) (UserProfileAudios, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetUserPersonalChatMessages gets recent messages from the channel pinned to a user's profile.
 func (bot *BotAPI) GetUserPersonalChatMessages(config UserPersonalChatMessagesConfig) ([]Message, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return nil, err
-	}
-
-	var messages []Message
-	err = json.Unmarshal(resp.Result, &messages)
+	return bot.GetUserPersonalChatMessagesWithContext(context.Background(), config)
+}
 
-	return messages, err
+func (bot *BotAPI) GetUserPersonalChatMessagesWithContext(ctx context.Context, config UserPersonalChatMessagesConfig) ([]Message, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetFile returns a File which can download a file from Telegram.
 //
 // Requires FileID.
 func (bot *BotAPI) GetFile(config FileConfig) (File, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return File{}, err
-	}
-
-	var file File
-	err = json.Unmarshal(resp.Result, &file)
+	return bot.GetFileWithContext(context.Background(), config)
+}
 
-	return file, err
+func (bot *BotAPI) GetFileWithContext(ctx context.Context, config FileConfig) (File, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetUpdates fetches updates.
@@ -452,14 +468,11 @@ func (bot *BotAPI) GetUpdates(config UpdateConfig) ([]Update, error) {
 }
 
 func (bot *BotAPI) GetUpdatesWithContext(ctx context.Context, config UpdateConfig) ([]Update, error) {
-	resp, err := bot.RequestWithContext(ctx, config)
-	if err != nil {
+	updates, err := Call(ctx, bot, config)
+	if err != nil && updates == nil {
 		return []Update{}, err
 	}
 
-	var updates []Update
-	err = json.Unmarshal(resp.Result, &updates)
-
 	return updates, err
 }
 
@@ -619,15 +632,11 @@ func WriteToHTTPResponse(w http.ResponseWriter, c Chattable) error {
 
 // GetChat gets information about a chat.
 func (bot *BotAPI) GetChat(config ChatInfoConfig) (ChatFullInfo, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return ChatFullInfo{}, err
-	}
-
-	var chat ChatFullInfo
-	err = json.Unmarshal(resp.Result, &chat)
+	return bot.GetChatWithContext(context.Background(), config)
+}
 
-	return chat, err
+func (bot *BotAPI) GetChatWithContext(ctx context.Context, config ChatInfoConfig) (ChatFullInfo, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetChatAdministrators gets a list of administrators in the chat.
@@ -635,28 +644,30 @@ func (bot *BotAPI) GetChat(config ChatInfoConfig) (ChatFullInfo, error) {
 // If none have been appointed, only the creator will be returned.
 // Bots are not shown, even if they are an administrator.
 func (bot *BotAPI) GetChatAdministrators(config ChatAdministratorsConfig) ([]ChatMember, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
+	return bot.GetChatAdministratorsWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) GetChatAdministratorsWithContext(ctx context.Context, config ChatAdministratorsConfig) ([]ChatMember, error) {
+	members, err := Call(ctx, bot, config)
+	if err != nil && members == nil {
 		return []ChatMember{}, err
 	}
 
-	var members []ChatMember
-	err = json.Unmarshal(resp.Result, &members)
-
 	return members, err
 }
 
 // GetChatMemberCount gets the number of users in a chat.
 func (bot *BotAPI) GetChatMemberCount(config ChatMemberCountConfig) (int, error) {
-	resp, err := bot.Request(config)
+	return bot.GetChatMemberCountWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) GetChatMemberCountWithContext(ctx context.Context, config ChatMemberCountConfig) (int, error) {
+	count, err := Call(ctx, bot, config)
 	if err != nil {
 		return -1, err
 	}
 
-	var count int
-	err = json.Unmarshal(resp.Result, &count)
-
-	return count, err
+	return count, nil
 }
 
 // GetChatMembersCount gets the number of users in a chat.
@@ -666,216 +677,175 @@ func (bot *BotAPI) GetChatMembersCount(config ChatMemberCountConfig) (int, error
 
 // GetChatMember gets a specific chat member.
 func (bot *BotAPI) GetChatMember(config GetChatMemberConfig) (ChatMember, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return ChatMember{}, err
-	}
-
-	var member ChatMember
-	err = json.Unmarshal(resp.Result, &member)
+	return bot.GetChatMemberWithContext(context.Background(), config)
+}
 
-	return member, err
+func (bot *BotAPI) GetChatMemberWithContext(ctx context.Context, config GetChatMemberConfig) (ChatMember, error) {
+	return Call(ctx, bot, config)
 }
 
 // DeleteMessageReaction removes a reaction from a message.
 func (bot *BotAPI) DeleteMessageReaction(config DeleteMessageReactionConfig) (bool, error) {
-	return bot.requestBool(config)
+	return bot.DeleteMessageReactionWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) DeleteMessageReactionWithContext(ctx context.Context, config DeleteMessageReactionConfig) (bool, error) {
+	return Call(ctx, bot, config)
 }
 
 // DeleteAllMessageReactions removes all recent reactions from a user or actor chat.
 func (bot *BotAPI) DeleteAllMessageReactions(config DeleteAllMessageReactionsConfig) (bool, error) {
-	return bot.requestBool(config)
+	return bot.DeleteAllMessageReactionsWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) DeleteAllMessageReactionsWithContext(ctx context.Context, config DeleteAllMessageReactionsConfig) (bool, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetGameHighScores allows you to get the high scores for a game.
 func (bot *BotAPI) GetGameHighScores(config GetGameHighScoresConfig) ([]GameHighScore, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
+	return bot.GetGameHighScoresWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) GetGameHighScoresWithContext(ctx context.Context, config GetGameHighScoresConfig) ([]GameHighScore, error) {
+	highScores, err := Call(ctx, bot, config)
+	if err != nil && highScores == nil {
 		return []GameHighScore{}, err
 	}
 
-	var highScores []GameHighScore
-	err = json.Unmarshal(resp.Result, &highScores)
-
 	return highScores, err
 }
 
 // GetInviteLink get InviteLink for a chat
 func (bot *BotAPI) GetInviteLink(config ChatInviteLinkConfig) (string, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return "", err
-	}
-
-	var inviteLink string
-	err = json.Unmarshal(resp.Result, &inviteLink)
+	return bot.GetInviteLinkWithContext(context.Background(), config)
+}
 
-	return inviteLink, err
+func (bot *BotAPI) GetInviteLinkWithContext(ctx context.Context, config ChatInviteLinkConfig) (string, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetManagedBotToken gets the token of a managed bot.
 func (bot *BotAPI) GetManagedBotToken(config GetManagedBotTokenConfig) (string, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return "", err
-	}
-
-	var token string
-	err = json.Unmarshal(resp.Result, &token)
+	return bot.GetManagedBotTokenWithContext(context.Background(), config)
+}
 
-	return token, err
+func (bot *BotAPI) GetManagedBotTokenWithContext(ctx context.Context, config GetManagedBotTokenConfig) (string, error) {
+	return Call(ctx, bot, config)
 }
 
 // ReplaceManagedBotToken revokes the current token of a managed bot and returns a new one.
 func (bot *BotAPI) ReplaceManagedBotToken(config ReplaceManagedBotTokenConfig) (string, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return "", err
-	}
-
-	var token string
-	err = json.Unmarshal(resp.Result, &token)
+	return bot.ReplaceManagedBotTokenWithContext(context.Background(), config)
+}
 
-	return token, err
+func (bot *BotAPI) ReplaceManagedBotTokenWithContext(ctx context.Context, config ReplaceManagedBotTokenConfig) (string, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetManagedBotAccessSettings gets granular access settings for a managed bot.
 func (bot *BotAPI) GetManagedBotAccessSettings(config GetManagedBotAccessSettingsConfig) (BotAccessSettings, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return BotAccessSettings{}, err
-	}
-
-	var settings BotAccessSettings
-	err = json.Unmarshal(resp.Result, &settings)
+	return bot.GetManagedBotAccessSettingsWithContext(context.Background(), config)
+}
 
-	return settings, err
+func (bot *BotAPI) GetManagedBotAccessSettingsWithContext(ctx context.Context, config GetManagedBotAccessSettingsConfig) (BotAccessSettings, error) {
+	return Call(ctx, bot, config)
 }
 
 // SetManagedBotAccessSettings changes granular access settings for a managed bot.
 func (bot *BotAPI) SetManagedBotAccessSettings(config SetManagedBotAccessSettingsConfig) (bool, error) {
-	return bot.requestBool(config)
+	return bot.SetManagedBotAccessSettingsWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) SetManagedBotAccessSettingsWithContext(ctx context.Context, config SetManagedBotAccessSettingsConfig) (bool, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetMyStarBalance gets the current Telegram Stars balance of the bot.
 func (bot *BotAPI) GetMyStarBalance(config GetMyStarBalanceConfig) (StarAmount, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return StarAmount{}, err
-	}
-
-	var balance StarAmount
-	err = json.Unmarshal(resp.Result, &balance)
+	return bot.GetMyStarBalanceWithContext(context.Background(), config)
+}
 
-	return balance, err
+func (bot *BotAPI) GetMyStarBalanceWithContext(ctx context.Context, config GetMyStarBalanceConfig) (StarAmount, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetBusinessAccountStarBalance gets the Telegram Stars balance of a business account.
 func (bot *BotAPI) GetBusinessAccountStarBalance(config GetBusinessAccountStarBalanceConfig) (StarAmount, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return StarAmount{}, err
-	}
-
-	var balance StarAmount
-	err = json.Unmarshal(resp.Result, &balance)
+	return bot.GetBusinessAccountStarBalanceWithContext(context.Background(), config)
+}
 
-	return balance, err
+func (bot *BotAPI) GetBusinessAccountStarBalanceWithContext(ctx context.Context, config GetBusinessAccountStarBalanceConfig) (StarAmount, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetBusinessAccountGifts gets gifts owned by a business account.
 func (bot *BotAPI) GetBusinessAccountGifts(config GetBusinessAccountGiftsConfig) (OwnedGifts, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return OwnedGifts{}, err
-	}
-
-	var gifts OwnedGifts
-	err = json.Unmarshal(resp.Result, &gifts)
+	return bot.GetBusinessAccountGiftsWithContext(context.Background(), config)
+}
 
-	return gifts, err
+func (bot *BotAPI) GetBusinessAccountGiftsWithContext(ctx context.Context, config GetBusinessAccountGiftsConfig) (OwnedGifts, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetUserGifts gets gifts owned by a user.
 func (bot *BotAPI) GetUserGifts(config GetUserGiftsConfig) (OwnedGifts, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return OwnedGifts{}, err
-	}
-
-	var gifts OwnedGifts
-	err = json.Unmarshal(resp.Result, &gifts)
+	return bot.GetUserGiftsWithContext(context.Background(), config)
+}
 
-	return gifts, err
+func (bot *BotAPI) GetUserGiftsWithContext(ctx context.Context, config GetUserGiftsConfig) (OwnedGifts, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetChatGifts gets gifts owned by a chat.
 func (bot *BotAPI) GetChatGifts(config GetChatGiftsConfig) (OwnedGifts, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return OwnedGifts{}, err
-	}
-
-	var gifts OwnedGifts
-	err = json.Unmarshal(resp.Result, &gifts)
+	return bot.GetChatGiftsWithContext(context.Background(), config)
+}
 
-	return gifts, err
+func (bot *BotAPI) GetChatGiftsWithContext(ctx context.Context, config GetChatGiftsConfig) (OwnedGifts, error) {
+	return Call(ctx, bot, config)
 }
 
 // CreateInvoiceLink Use this method to create a link for an invoice. Returns the created invoice link as
 // String on success.
-func (bot *BotAPI) CreateInvoiceLink(config InvoiceLinkConfig) (inviteLink string, err error) {
-	var resp *APIResponse
-
-	if resp, err = bot.Request(config); err != nil {
-		return
-	}
-	if !resp.Ok {
-		err = fmt.Errorf("returns error code: %d", resp.ErrorCode)
-		return
-	}
-	err = json.Unmarshal(resp.Result, &inviteLink)
+func (bot *BotAPI) CreateInvoiceLink(config InvoiceLinkConfig) (string, error) {
+	return bot.CreateInvoiceLinkWithContext(context.Background(), config)
+}
 
-	return
+func (bot *BotAPI) CreateInvoiceLinkWithContext(ctx context.Context, config InvoiceLinkConfig) (string, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetStickerSet returns a StickerSet.
 func (bot *BotAPI) GetStickerSet(config GetStickerSetConfig) (StickerSet, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return StickerSet{}, err
-	}
-
-	var stickerSet StickerSet
-	err = json.Unmarshal(resp.Result, &stickerSet)
+	return bot.GetStickerSetWithContext(context.Background(), config)
+}
 
-	return stickerSet, err
+func (bot *BotAPI) GetStickerSetWithContext(ctx context.Context, config GetStickerSetConfig) (StickerSet, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetCustomEmojiStickers returns a slice of Sticker objects.
 func (bot *BotAPI) GetCustomEmojiStickers(config GetCustomEmojiStickersConfig) ([]Sticker, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
+	return bot.GetCustomEmojiStickersWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) GetCustomEmojiStickersWithContext(ctx context.Context, config GetCustomEmojiStickersConfig) ([]Sticker, error) {
+	stickers, err := Call(ctx, bot, config)
+	if err != nil && stickers == nil {
 		return []Sticker{}, err
 	}
 
-	var stickers []Sticker
-	err = json.Unmarshal(resp.Result, &stickers)
-
 	return stickers, err
 }
 
 // StopPoll stops a poll and returns the result.
 func (bot *BotAPI) StopPoll(config StopPollConfig) (Poll, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return Poll{}, err
-	}
-
-	var poll Poll
-	err = json.Unmarshal(resp.Result, &poll)
+	return bot.StopPollWithContext(context.Background(), config)
+}
 
-	return poll, err
+func (bot *BotAPI) StopPollWithContext(ctx context.Context, config StopPollConfig) (Poll, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetMyCommands gets the currently registered commands.
@@ -885,119 +855,91 @@ func (bot *BotAPI) GetMyCommands() ([]BotCommand, error) {
 
 // GetMyCommandsWithConfig gets the currently registered commands with a config.
 func (bot *BotAPI) GetMyCommandsWithConfig(config GetMyCommandsConfig) ([]BotCommand, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return nil, err
-	}
-
-	var commands []BotCommand
-	err = json.Unmarshal(resp.Result, &commands)
+	return bot.GetMyCommandsWithContext(context.Background(), config)
+}
 
-	return commands, err
+func (bot *BotAPI) GetMyCommandsWithContext(ctx context.Context, config GetMyCommandsConfig) ([]BotCommand, error) {
+	return Call(ctx, bot, config)
 }
 
 // CopyMessage copy messages of any kind. The method is analogous to the method
 // forwardMessage, but the copied message doesn't have a link to the original
 // message. Returns the MessageID of the sent message on success.
 func (bot *BotAPI) CopyMessage(config CopyMessageConfig) (MessageID, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return MessageID{}, err
-	}
-
-	var messageID MessageID
-	err = json.Unmarshal(resp.Result, &messageID)
+	return bot.CopyMessageWithContext(context.Background(), config)
+}
 
-	return messageID, err
+func (bot *BotAPI) CopyMessageWithContext(ctx context.Context, config CopyMessageConfig) (MessageID, error) {
+	return Call(ctx, bot, config)
 }
 
 // AnswerWebAppQuery sets the result of an interaction with a Web App and send a
 // corresponding message on behalf of the user to the chat from which the query originated.
 func (bot *BotAPI) AnswerWebAppQuery(config AnswerWebAppQueryConfig) (SentWebAppMessage, error) {
-	var sentWebAppMessage SentWebAppMessage
-
-	resp, err := bot.Request(config)
-	if err != nil {
-		return sentWebAppMessage, err
-	}
+	return bot.AnswerWebAppQueryWithContext(context.Background(), config)
+}
 
-	err = json.Unmarshal(resp.Result, &sentWebAppMessage)
-	return sentWebAppMessage, err
+func (bot *BotAPI) AnswerWebAppQueryWithContext(ctx context.Context, config AnswerWebAppQueryConfig) (SentWebAppMessage, error) {
+	return Call(ctx, bot, config)
 }
 
 // AnswerGuestQuery replies to a received guest message.
 func (bot *BotAPI) AnswerGuestQuery(config AnswerGuestQueryConfig) (SentGuestMessage, error) {
-	var sentGuestMessage SentGuestMessage
-
-	resp, err := bot.Request(config)
-	if err != nil {
-		return sentGuestMessage, err
-	}
+	return bot.AnswerGuestQueryWithContext(context.Background(), config)
+}
 
-	err = json.Unmarshal(resp.Result, &sentGuestMessage)
-	return sentGuestMessage, err
+func (bot *BotAPI) AnswerGuestQueryWithContext(ctx context.Context, config AnswerGuestQueryConfig) (SentGuestMessage, error) {
+	return Call(ctx, bot, config)
 }
 
 // AnswerChatJoinRequestQuery processes a received chat join request query.
 func (bot *BotAPI) AnswerChatJoinRequestQuery(config AnswerChatJoinRequestQueryConfig) (bool, error) {
-	return bot.requestBool(config)
+	return bot.AnswerChatJoinRequestQueryWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) AnswerChatJoinRequestQueryWithContext(ctx context.Context, config AnswerChatJoinRequestQueryConfig) (bool, error) {
+	return Call(ctx, bot, config)
 }
 
 // SendChatJoinRequestWebApp processes a chat join request query by showing a Mini App.
 func (bot *BotAPI) SendChatJoinRequestWebApp(config SendChatJoinRequestWebAppConfig) (bool, error) {
-	return bot.requestBool(config)
+	return bot.SendChatJoinRequestWebAppWithContext(context.Background(), config)
+}
+
+func (bot *BotAPI) SendChatJoinRequestWebAppWithContext(ctx context.Context, config SendChatJoinRequestWebAppConfig) (bool, error) {
+	return Call(ctx, bot, config)
 }
 
 // GetMyDefaultAdministratorRights gets the current default administrator rights of the bot.
 func (bot *BotAPI) GetMyDefaultAdministratorRights(config GetMyDefaultAdministratorRightsConfig) (ChatAdministratorRights, error) {
-	var rights ChatAdministratorRights
-
-	resp, err := bot.Request(config)
-	if err != nil {
-		return rights, err
-	}
+	return bot.GetMyDefaultAdministratorRightsWithContext(context.Background(), config)
+}
 
-	err = json.Unmarshal(resp.Result, &rights)
-	return rights, err
+func (bot *BotAPI) GetMyDefaultAdministratorRightsWithContext(ctx context.Context, config GetMyDefaultAdministratorRightsConfig) (ChatAdministratorRights, error) {
+	return Call(ctx, bot, config)
 }
 
 // CreateForumTopic creates a topic in a forum supergroup chat.
 func (bot *BotAPI) CreateForumTopic(config CreateForumTopicConfig) (ForumTopic, error) {
-	var topic ForumTopic
-
-	resp, err := bot.Request(config)
-	if err != nil {
-		return topic, err
-	}
+	return bot.CreateForumTopicWithContext(context.Background(), config)
+}
 
-	err = json.Unmarshal(resp.Result, &topic)
-	return topic, err
+func (bot *BotAPI) CreateForumTopicWithContext(ctx context.Context, config CreateForumTopicConfig) (ForumTopic, error) {
+	return Call(ctx, bot, config)
 }
 
 // SavePreparedInlineMessage Stores a message that can be sent by a user of a Mini App. Returns a PreparedInlineMessage object.
 func SavePreparedInlineMessage[T InlineQueryResults](bot *BotAPI, config SavePreparedInlineMessageConfig[T]) (PreparedInlineMessage, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return PreparedInlineMessage{}, err
-	}
-
-	var preparedInlineMessage PreparedInlineMessage
-	err = json.Unmarshal(resp.Result, &preparedInlineMessage)
-
-	return preparedInlineMessage, err
+	return Call(context.Background(), bot, config)
 }
 
 // SavePreparedKeyboardButton stores a keyboard button that can be used by a user of a Mini App.
 func (bot *BotAPI) SavePreparedKeyboardButton(config SavePreparedKeyboardButtonConfig) (PreparedKeyboardButton, error) {
-	resp, err := bot.Request(config)
-	if err != nil {
-		return PreparedKeyboardButton{}, err
-	}
-
-	var preparedKeyboardButton PreparedKeyboardButton
-	err = json.Unmarshal(resp.Result, &preparedKeyboardButton)
+	return bot.SavePreparedKeyboardButtonWithContext(context.Background(), config)
+}
 
-	return preparedKeyboardButton, err
+func (bot *BotAPI) SavePreparedKeyboardButtonWithContext(ctx context.Context, config SavePreparedKeyboardButtonConfig) (PreparedKeyboardButton, error) {
+	return Call(ctx, bot, config)
 }
 
 // EscapeText takes an input text and escape Telegram markup symbols.
@@ -0,0 +1,118 @@
+package tgbotapi
+
+import "context"
+
+//go:generate go run ./internal/mockgen -out tgbotapitest/mock_bot.go
+
+// Bot is the interface of the methods of BotAPI making requests to the Bot
+// API. Handlers taking a Bot instead of a *BotAPI can be tested with a
+// tgbotapitest.MockBot.
+type Bot interface {
+	GetMe() (User, error)
+	GetMeWithContext(ctx context.Context) (User, error)
+
+	Request(c Chattable) (*APIResponse, error)
+	RequestWithContext(ctx context.Context, c Chattable) (*APIResponse, error)
+	Send(c Chattable) (Message, error)
+	SendWithContext(ctx context.Context, c Chattable) (Message, error)
+
+	SendLivePhoto(config SendLivePhotoConfig) (Message, error)
+	SendLivePhotoWithContext(ctx context.Context, config SendLivePhotoConfig) (Message, error)
+	SendRichMessage(config SendRichMessageConfig) (Message, error)
+	SendRichMessageWithContext(ctx context.Context, config SendRichMessageConfig) (Message, error)
+	SendRichMessageDraft(config SendRichMessageDraftConfig) (bool, error)
+	SendRichMessageDraftWithContext(ctx context.Context, config SendRichMessageDraftConfig) (bool, error)
+	SendMediaGroup(config MediaGroupConfig) ([]Message, error)
+	SendMediaGroupWithContext(ctx context.Context, config MediaGroupConfig) ([]Message, error)
+	CopyMessage(config CopyMessageConfig) (MessageID, error)
+	CopyMessageWithContext(ctx context.Context, config CopyMessageConfig) (MessageID, error)
+	StopPoll(config StopPollConfig) (Poll, error)
+	StopPollWithContext(ctx context.Context, config StopPollConfig) (Poll, error)
+
+	PostStory(config PostStoryConfig) (Story, error)
+	PostStoryWithContext(ctx context.Context, config PostStoryConfig) (Story, error)
+	EditStory(config EditStoryConfig) (Story, error)
+	EditStoryWithContext(ctx context.Context, config EditStoryConfig) (Story, error)
+	RepostStory(config RepostStoryConfig) (Story, error)
+	RepostStoryWithContext(ctx context.Context, config RepostStoryConfig) (Story, error)
+
+	GetUpdates(config UpdateConfig) ([]Update, error)
+	GetUpdatesWithContext(ctx context.Context, config UpdateConfig) ([]Update, error)
+	GetWebhookInfo() (WebhookInfo, error)
+	GetWebhookInfoWithContext(ctx context.Context) (WebhookInfo, error)
+
+	GetFile(config FileConfig) (File, error)
+	GetFileWithContext(ctx context.Context, config FileConfig) (File, error)
+	GetUserProfilePhotos(config UserProfilePhotosConfig) (UserProfilePhotos, error)
+	GetUserProfilePhotosWithContext(ctx context.Context, config UserProfilePhotosConfig) (UserProfilePhotos, error)
+	GetUserProfileAudios(config UserProfileAudiosConfig) (UserProfileAudios, error)
+	GetUserProfileAudiosWithContext(ctx context.Context, config UserProfileAudiosConfig) (UserProfileAudios, error)
+	GetUserPersonalChatMessages(config UserPersonalChatMessagesConfig) ([]Message, error)
+	GetUserPersonalChatMessagesWithContext(ctx context.Context, config UserPersonalChatMessagesConfig) ([]Message, error)
+
+	GetChat(config ChatInfoConfig) (ChatFullInfo, error)
+	GetChatWithContext(ctx context.Context, config ChatInfoConfig) (ChatFullInfo, error)
+	GetChatAdministrators(config ChatAdministratorsConfig) ([]ChatMember, error)
+	GetChatAdministratorsWithContext(ctx context.Context, config ChatAdministratorsConfig) ([]ChatMember, error)
+	GetChatMemberCount(config ChatMemberCountConfig) (int, error)
+	GetChatMemberCountWithContext(ctx context.Context, config ChatMemberCountConfig) (int, error)
+	GetChatMember(config GetChatMemberConfig) (ChatMember, error)
+	GetChatMemberWithContext(ctx context.Context, config GetChatMemberConfig) (ChatMember, error)
+	GetInviteLink(config ChatInviteLinkConfig) (string, error)
+	GetInviteLinkWithContext(ctx context.Context, config ChatInviteLinkConfig) (string, error)
+	CreateForumTopic(config CreateForumTopicConfig) (ForumTopic, error)
+	CreateForumTopicWithContext(ctx context.Context, config CreateForumTopicConfig) (ForumTopic, error)
+
+	DeleteMessageReaction(config DeleteMessageReactionConfig) (bool, error)
+	DeleteMessageReactionWithContext(ctx context.Context, config DeleteMessageReactionConfig) (bool, error)
+	DeleteAllMessageReactions(config DeleteAllMessageReactionsConfig) (bool, error)
+	DeleteAllMessageReactionsWithContext(ctx context.Context, config DeleteAllMessageReactionsConfig) (bool, error)
+
+	GetGameHighScores(config GetGameHighScoresConfig) ([]GameHighScore, error)
+	GetGameHighScoresWithContext(ctx context.Context, config GetGameHighScoresConfig) ([]GameHighScore, error)
+	GetStickerSet(config GetStickerSetConfig) (StickerSet, error)
+	GetStickerSetWithContext(ctx context.Context, config GetStickerSetConfig) (StickerSet, error)
+	GetCustomEmojiStickers(config GetCustomEmojiStickersConfig) ([]Sticker, error)
+	GetCustomEmojiStickersWithContext(ctx context.Context, config GetCustomEmojiStickersConfig) ([]Sticker, error)
+
+	GetMyCommands() ([]BotCommand, error)
+	GetMyCommandsWithConfig(config GetMyCommandsConfig) ([]BotCommand, error)
+	GetMyCommandsWithContext(ctx context.Context, config GetMyCommandsConfig) ([]BotCommand, error)
+	GetMyDefaultAdministratorRights(config GetMyDefaultAdministratorRightsConfig) (ChatAdministratorRights, error)
+	GetMyDefaultAdministratorRightsWithContext(ctx context.Context, config GetMyDefaultAdministratorRightsConfig) (ChatAdministratorRights, error)
+
+	GetManagedBotToken(config GetManagedBotTokenConfig) (string, error)
+	GetManagedBotTokenWithContext(ctx context.Context, config GetManagedBotTokenConfig) (string, error)
+	ReplaceManagedBotToken(config ReplaceManagedBotTokenConfig) (string, error)
+	ReplaceManagedBotTokenWithContext(ctx context.Context, config ReplaceManagedBotTokenConfig) (string, error)
+	GetManagedBotAccessSettings(config GetManagedBotAccessSettingsConfig) (BotAccessSettings, error)
+	GetManagedBotAccessSettingsWithContext(ctx context.Context, config GetManagedBotAccessSettingsConfig) (BotAccessSettings, error)
+	SetManagedBotAccessSettings(config SetManagedBotAccessSettingsConfig) (bool, error)
+	SetManagedBotAccessSettingsWithContext(ctx context.Context, config SetManagedBotAccessSettingsConfig) (bool, error)
+
+	GetMyStarBalance(config GetMyStarBalanceConfig) (StarAmount, error)
+	GetMyStarBalanceWithContext(ctx context.Context, config GetMyStarBalanceConfig) (StarAmount, error)
+	GetBusinessAccountStarBalance(config GetBusinessAccountStarBalanceConfig) (StarAmount, error)
+	GetBusinessAccountStarBalanceWithContext(ctx context.Context, config GetBusinessAccountStarBalanceConfig) (StarAmount, error)
+	GetBusinessAccountGifts(config GetBusinessAccountGiftsConfig) (OwnedGifts, error)
+	GetBusinessAccountGiftsWithContext(ctx context.Context, config GetBusinessAccountGiftsConfig) (OwnedGifts, error)
+	GetUserGifts(config GetUserGiftsConfig) (OwnedGifts, error)
+	GetUserGiftsWithContext(ctx context.Context, config GetUserGiftsConfig) (OwnedGifts, error)
+	GetChatGifts(config GetChatGiftsConfig) (OwnedGifts, error)
+	GetChatGiftsWithContext(ctx context.Context, config GetChatGiftsConfig) (OwnedGifts, error)
+	CreateInvoiceLink(config InvoiceLinkConfig) (string, error)
+	CreateInvoiceLinkWithContext(ctx context.Context, config InvoiceLinkConfig) (string, error)
+
+	AnswerWebAppQuery(config AnswerWebAppQueryConfig) (SentWebAppMessage, error)
+	AnswerWebAppQueryWithContext(ctx context.Context, config AnswerWebAppQueryConfig) (SentWebAppMessage, error)
+	AnswerGuestQuery(config AnswerGuestQueryConfig) (SentGuestMessage, error)
+	AnswerGuestQueryWithContext(ctx context.Context, config AnswerGuestQueryConfig) (SentGuestMessage, error)
+	AnswerChatJoinRequestQuery(config AnswerChatJoinRequestQueryConfig) (bool, error)
+	AnswerChatJoinRequestQueryWithContext(ctx context.Context, config AnswerChatJoinRequestQueryConfig) (bool, error)
+	SendChatJoinRequestWebApp(config SendChatJoinRequestWebAppConfig) (bool, error)
+	SendChatJoinRequestWebAppWithContext(ctx context.Context, config SendChatJoinRequestWebAppConfig) (bool, error)
+	SavePreparedKeyboardButton(config SavePreparedKeyboardButtonConfig) (PreparedKeyboardButton, error)
+	SavePreparedKeyboardButtonWithContext(ctx context.Context, config SavePreparedKeyboardButtonConfig) (PreparedKeyboardButton, error)
+}
+
+var _ Bot = (*BotAPI)(nil)
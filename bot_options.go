@@ -14,6 +14,10 @@ type botAPIConfig struct {
 	buffer          int
 	logger          any
 	loggingDisabled bool
+	maxDownloadSize int64
+	localMode       bool
+	validate        bool
+	dryRun          *dryRun
 }
 
 // BotAPIOption configures a BotAPI instance created by NewBotAPIWithOptions.
@@ -94,3 +98,25 @@ func WithLoggingDisabled() BotAPIOption {
 		return nil
 	}
 }
+
+// WithMaxDownloadSize configures the maximum size of files downloaded with
+// DownloadFile and DownloadToPath, see BotAPI.MaxDownloadSize.
+func WithMaxDownloadSize(size int64) BotAPIOption {
+	return func(config *botAPIConfig) error {
+		if size <= 0 {
+			return fmt.Errorf("invalid max download size %d", size)
+		}
+		config.maxDownloadSize = size
+		return nil
+	}
+}
+
+// WithRequestValidation makes Request and Send check configs implementing
+// Validator before sending them, returning the validation error instead of
+// making a request.
+func WithRequestValidation() BotAPIOption {
+	return func(config *botAPIConfig) error {
+		config.validate = true
+		return nil
+	}
+}
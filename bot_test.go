@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -89,9 +90,11 @@ func (h recordingSlogHandler) WithGroup(string) slog.Handler {
 
 func newFakeBot(client HTTPClient) *BotAPI {
 	return &BotAPI{
-		Token:       "token",
-		Client:      client,
-		apiEndpoint: "https://example.com/bot%s/%s",
+		Token:  "token",
+		Client: client,
+		botAPIConfig: botAPIConfig{
+			apiEndpoint: "https://example.com/bot%s/%s",
+		},
 	}
 }
 
@@ -284,6 +287,36 @@ func TestRequestWithContextCarriesContextForUploads(t *testing.T) {
 	}
 }
 
+func TestTypedMethodsWithContextCarryContext(t *testing.T) {
+	type contextKey struct{}
+
+	ctx := context.WithValue(context.Background(), contextKey{}, "typed-request")
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			if got := req.Context().Value(contextKey{}); got != "typed-request" {
+				t.Fatalf("%s lost context value: %#v", req.URL.Path, got)
+			}
+			return resultResponse(`{}`), nil
+		},
+	})
+
+	if _, err := bot.GetChatWithContext(ctx, ChatInfoConfig{ChatConfig: ChatConfig{ChatID: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.SendLivePhotoWithContext(ctx, SendLivePhotoConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	bot.Client = fakeHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+		return nil, req.Context().Err()
+	}}
+	if _, err := bot.GetFileWithContext(canceled, FileConfig{FileID: "file"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected a canceled request, got %v", err)
+	}
+}
+
 func TestUploadFilesPreservesAPIErrorCode(t *testing.T) {
 	bot := newFakeBot(fakeHTTPClient{
 		do: func(req *http.Request) (*http.Response, error) {
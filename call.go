@@ -0,0 +1,58 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Method is a config of a Bot API method with a result of type T. Every
+// config of the library is a Method of the result of its method.
+type Method[T any] interface {
+	Chattable
+	result() T
+}
+
+// Call sends a config and decodes the result of its method, such as a
+// ChatFullInfo for a ChatInfoConfig:
+//
+//	chat, err := tgbotapi.Call(ctx, bot, tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: chatID}})
+//
+// Methods returning either a Message or True, such as edits of inline
+// messages, result in the zero Message when True is returned.
+func Call[T any](ctx context.Context, bot Bot, c Method[T]) (T, error) {
+	var result T
+
+	resp, err := bot.RequestWithContext(ctx, c)
+	if err != nil {
+		return result, err
+	}
+	if len(resp.Result) == 0 {
+		return result, nil
+	}
+
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		if _, ok := c.(orTrue); ok && string(resp.Result) == "true" {
+			return result, nil
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+// orTrue is implemented by configs of methods returning either their result
+// or True.
+type orTrue interface {
+	orTrue()
+}
+
+// RawMethod is a RawRequest with a result of type T, so methods the library
+// has no config for yet can be called with Call:
+//
+//	gifts, err := tgbotapi.Call(ctx, bot, tgbotapi.RawMethod[tgbotapi.Gifts]{
+//		RawRequest: tgbotapi.NewRawRequest("getAvailableGifts", nil),
+//	})
+type RawMethod[T any] struct {
+	RawRequest
+}
+
+func (RawMethod[T]) result() (_ T) { return }
@@ -0,0 +1,120 @@
+package tgbotapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func resultResponse(result string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":` + result + `}`)),
+	}
+}
+
+func TestCall(t *testing.T) {
+	result := `{"id":7,"type":"private","first_name":"Bob"}`
+	bot := newFakeBot(fakeHTTPClient{do: func(*http.Request) (*http.Response, error) {
+		return resultResponse(result), nil
+	}})
+	ctx := context.Background()
+
+	chat, err := Call(ctx, bot, ChatInfoConfig{ChatConfig: ChatConfig{ChatID: 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.ID != 7 || chat.FirstName != "Bob" {
+		t.Fatalf("unexpected chat %+v", chat)
+	}
+
+	result = `[{"message_id":1},{"message_id":2}]`
+	ids, err := Call(ctx, bot, CopyMessagesConfig{
+		BaseChat:   BaseChat{ChatConfig: ChatConfig{ChatID: 1}},
+		FromChat:   ChatConfig{ChatID: 2},
+		MessageIDs: []int{3, 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[1].MessageID != 2 {
+		t.Fatalf("unexpected message IDs %+v", ids)
+	}
+
+	result = `true`
+	ok, err := Call(ctx, bot, NewDeleteMessage(1, 2))
+	if err != nil || !ok {
+		t.Fatalf("expected true, got %v, %v", ok, err)
+	}
+
+	edit := NewEditMessageText(0, 0, "text")
+	edit.InlineMessageID = "inline"
+	message, err := Call(ctx, bot, edit)
+	if err != nil {
+		t.Fatalf("expected an inline edit to succeed, got %v", err)
+	}
+	if message.MessageID != 0 {
+		t.Fatalf("expected the zero message, got %+v", message)
+	}
+
+	result = `true`
+	if _, err := Call(ctx, bot, ChatInfoConfig{ChatConfig: ChatConfig{ChatID: 7}}); err == nil {
+		t.Fatal("expected True to be rejected for a method returning a chat")
+	}
+
+	result = `{"gifts":[{"id":"gift","star_count":10}]}`
+	gifts, err := Call(ctx, bot, RawMethod[Gifts]{RawRequest: NewRawRequest("getAvailableGifts", nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gifts.Gifts) != 1 || gifts.Gifts[0].StarCount != 10 {
+		t.Fatalf("unexpected gifts %+v", gifts)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	bot.Client = fakeHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+		return nil, req.Context().Err()
+	}}
+	if _, err := Call(ctx, bot, ChatInfoConfig{ChatConfig: ChatConfig{ChatID: 7}}); err == nil {
+		t.Fatal("expected an error with a canceled context")
+	}
+}
+
+func TestTypedMethodsKeepEmptySlicesOnError(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{do: func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader(`{"ok":false,"error_code":400,"description":"Bad Request"}`)),
+		}, nil
+	}})
+
+	if members, err := bot.GetChatAdministrators(ChatAdministratorsConfig{}); err == nil || members == nil {
+		t.Fatalf("expected an empty slice and an error, got %#v, %v", members, err)
+	}
+	if stickers, err := bot.GetCustomEmojiStickers(GetCustomEmojiStickersConfig{}); err == nil || stickers == nil {
+		t.Fatalf("expected an empty slice and an error, got %#v, %v", stickers, err)
+	}
+	if scores, err := bot.GetGameHighScores(GetGameHighScoresConfig{}); err == nil || scores == nil {
+		t.Fatalf("expected an empty slice and an error, got %#v, %v", scores, err)
+	}
+	if updates, err := bot.GetUpdates(UpdateConfig{}); err == nil || updates == nil {
+		t.Fatalf("expected an empty slice and an error, got %#v, %v", updates, err)
+	}
+	if count, err := bot.GetChatMemberCount(ChatMemberCountConfig{}); err == nil || count != -1 {
+		t.Fatalf("expected -1 and an error, got %d, %v", count, err)
+	}
+}
+
+func TestGetUpdatesKeepsPartiallyDecodedUpdates(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{do: func(*http.Request) (*http.Response, error) {
+		return resultResponse(`[{"update_id":1},{"update_id":"2"}]`), nil
+	}})
+
+	updates, err := bot.GetUpdates(UpdateConfig{})
+	if err == nil || len(updates) != 2 || updates[0].UpdateID != 1 {
+		t.Fatalf("expected the decoded updates with the error, got %+v, %v", updates, err)
+	}
+}
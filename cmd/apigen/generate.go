@@ -0,0 +1,420 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"slices"
+	"strings"
+)
+
+// fieldKind is how a field is encoded in the params of a request.
+type fieldKind int
+
+const (
+	kindString fieldKind = iota
+	kindInt
+	kindInt64
+	kindFloat
+	kindBool
+	kindFile
+	kindUnion
+	kindJSON
+	kindSlice
+)
+
+// generator generates the declarations of a spec missing from a package.
+type generator struct {
+	spec  *Spec
+	index *packageIndex
+	pkg   string
+
+	// types are the spec types generated as structs.
+	types map[string]bool
+	// configs are the configs generated for methods.
+	configs map[string]bool
+	// checkedTypes are the structs with all fields of the spec, whose fields
+	// are checked by the generated tests.
+	checkedTypes []string
+
+	warnings []string
+	imports  map[string]bool
+}
+
+func newGenerator(spec *Spec, index *packageIndex, pkg string) *generator {
+	g := &generator{
+		spec:    spec,
+		index:   index,
+		pkg:     pkg,
+		types:   make(map[string]bool),
+		configs: make(map[string]bool),
+		imports: make(map[string]bool),
+	}
+	g.plan()
+	return g
+}
+
+// plan decides what to generate. Hand-written declarations are never
+// replaced; types missing fields are reported instead.
+func (g *generator) plan() {
+	for _, name := range sortedKeys(g.spec.Types) {
+		typ := g.spec.Types[name]
+		if len(typ.Subtypes) > 0 {
+			continue
+		}
+
+		fields, isStruct := g.index.structs[name]
+		switch {
+		case isStruct:
+			var missing []string
+			for _, field := range typ.Fields {
+				if !fields[field.Name] {
+					missing = append(missing, field.Name)
+				}
+			}
+			if len(missing) > 0 {
+				g.warnf("type %s is missing fields %s", name, strings.Join(missing, ", "))
+			} else {
+				g.checkedTypes = append(g.checkedTypes, name)
+			}
+		case g.index.names[name]:
+			g.warnf("type %s conflicts with a declaration of the package", name)
+		default:
+			g.types[name] = true
+			g.checkedTypes = append(g.checkedTypes, name)
+		}
+	}
+
+	for _, name := range sortedKeys(g.spec.Methods) {
+		if config, ok := g.index.configs[name]; ok {
+			if !g.index.results[config.name] {
+				g.warnf("method %s: %s does not declare its result", name, config.name)
+			}
+			continue
+		}
+		if config := configName(name); g.index.names[config] {
+			g.warnf("method %s: %s is declared but does not implement it", name, config)
+			continue
+		}
+		g.configs[name] = true
+	}
+}
+
+func (g *generator) warnf(format string, args ...any) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+// generate returns the source of the generated declarations.
+func (g *generator) generate() ([]byte, error) {
+	var body bytes.Buffer
+
+	for _, name := range sortedKeys(g.spec.Types) {
+		if g.types[name] {
+			g.writeType(&body, g.spec.Types[name])
+		}
+	}
+	for _, name := range sortedKeys(g.spec.Methods) {
+		if g.configs[name] {
+			g.writeConfig(&body, g.spec.Methods[name])
+		}
+	}
+
+	return g.source(&body, g.imports)
+}
+
+// generateTests returns the source of the parity tests.
+func (g *generator) generateTests() ([]byte, error) {
+	var body bytes.Buffer
+
+	body.WriteString("func TestAPIMethodConfigs(t *testing.T) {\n")
+	body.WriteString("\tconfigs := map[string]Chattable{\n")
+	for _, name := range sortedKeys(g.spec.Methods) {
+		if g.configs[name] {
+			fmt.Fprintf(&body, "\t\t%q: %s{},\n", name, configName(name))
+		} else if config, ok := g.index.configs[name]; ok && config.testable {
+			fmt.Fprintf(&body, "\t\t%q: %s{},\n", name, config.name)
+		}
+	}
+	body.WriteString("\t}\n\n")
+	body.WriteString(`	for method, config := range configs {
+		if got := config.method(); got != method {
+			t.Errorf("%T implements %s, want %s", config, got, method)
+		}
+	}
+}
+
+func TestAPITypeFields(t *testing.T) {
+	types := map[string]struct {
+		value  any
+		fields []string
+	}{
+`)
+	for _, name := range g.checkedTypes {
+		var fields []string
+		for _, field := range g.spec.Types[name].Fields {
+			fields = append(fields, fmt.Sprintf("%q", field.Name))
+		}
+		fmt.Fprintf(&body, "\t\t%q: {%s{}, []string{%s}},\n", name, name, strings.Join(fields, ", "))
+	}
+	body.WriteString(`	}
+
+	for name, typ := range types {
+		fields := make(map[string]bool)
+		generatedJSONFields(reflect.TypeOf(typ.value), fields)
+		for _, field := range typ.fields {
+			if !fields[field] {
+				t.Errorf("%s is missing field %s", name, field)
+			}
+		}
+	}
+}
+
+// generatedJSONFields collects the JSON names of the fields of a struct,
+// including embedded structs.
+func generatedJSONFields(typ reflect.Type, fields map[string]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			generatedJSONFields(field.Type, fields)
+			continue
+		}
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+}
+`)
+
+	return g.source(&body, map[string]bool{"reflect": true, "strings": true, "testing": true})
+}
+
+func (g *generator) source(body *bytes.Buffer, imports map[string]bool) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by apigen from the Bot API specification. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", g.pkg)
+	if len(imports) > 0 {
+		buf.WriteString("import (\n")
+		for _, path := range sortedKeys(imports) {
+			fmt.Fprintf(&buf, "\t%q\n", path)
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+func (g *generator) writeType(buf *bytes.Buffer, typ SpecType) {
+	writeComment(buf, "", typ.Name+" "+typeDescription(typ.Description))
+	fmt.Fprintf(buf, "type %s struct {\n", typ.Name)
+	for _, field := range typ.Fields {
+		goType, _ := g.goType(field, false)
+		writeComment(buf, "\t", goName(field.Name)+" "+lowerFirst(field.Description))
+		tag := field.Name
+		if !field.Required {
+			buf.WriteString("\t//\n\t// optional\n")
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:%q`\n", goName(field.Name), goType, tag)
+	}
+	buf.WriteString("}\n\n")
+}
+
+func (g *generator) writeConfig(buf *bytes.Buffer, method SpecMethod) {
+	name := configName(method.Name)
+
+	type configField struct {
+		name, key string
+		kind      fieldKind
+	}
+	var fields []configField
+	hasChat := false
+
+	writeComment(buf, "", fmt.Sprintf("%s contains the params of %s.", name, method.Name))
+	if len(method.Description) > 0 {
+		buf.WriteString("//\n")
+		writeComment(buf, "", strings.Join(method.Description, " "))
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, field := range method.Fields {
+		if field.Name == "chat_id" {
+			hasChat = true
+			buf.WriteString("\tChatConfig\n")
+			continue
+		}
+		goType, kind := g.goType(field, true)
+		fields = append(fields, configField{name: goName(field.Name), key: field.Name, kind: kind})
+		fmt.Fprintf(buf, "\t%s %s\n", goName(field.Name), goType)
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(buf, "func (%s) method() string {\n\treturn %q\n}\n\n", name, method.Name)
+
+	fmt.Fprintf(buf, "func (config %s) params() (Params, error) {\n", name)
+	if hasChat {
+		buf.WriteString("\tparams, err := config.ChatConfig.params()\n\tif err != nil {\n\t\treturn params, err\n\t}\n\n")
+	} else {
+		buf.WriteString("\tparams := make(Params)\n\n")
+	}
+	var files []configField
+	for _, field := range fields {
+		value := "config." + field.name
+		switch field.kind {
+		case kindString:
+			fmt.Fprintf(buf, "\tparams.AddNonEmpty(%q, %s)\n", field.key, value)
+		case kindInt:
+			fmt.Fprintf(buf, "\tparams.AddNonZero(%q, %s)\n", field.key, value)
+		case kindInt64:
+			fmt.Fprintf(buf, "\tparams.AddNonZero64(%q, %s)\n", field.key, value)
+		case kindFloat:
+			fmt.Fprintf(buf, "\tparams.AddNonZeroFloat(%q, %s)\n", field.key, value)
+		case kindBool:
+			fmt.Fprintf(buf, "\tparams.AddBool(%q, %s)\n", field.key, value)
+		case kindFile:
+			files = append(files, field)
+		case kindUnion:
+			fmt.Fprintf(buf, "\tif err := params.AddFirstValid(%q, %s); err != nil {\n\t\treturn params, err\n\t}\n", field.key, value)
+		case kindSlice:
+			fmt.Fprintf(buf, "\tif len(%s) > 0 {\n\t\tif err := params.AddInterface(%q, %s); err != nil {\n\t\t\treturn params, err\n\t\t}\n\t}\n", value, field.key, value)
+		case kindJSON:
+			fmt.Fprintf(buf, "\tif err := params.AddInterface(%q, %s); err != nil {\n\t\treturn params, err\n\t}\n", field.key, value)
+		}
+	}
+	buf.WriteString("\n\treturn params, nil\n}\n\n")
+
+	if len(files) > 0 {
+		fmt.Fprintf(buf, "func (config %s) files() []RequestFile {\n\tvar files []RequestFile\n", name)
+		for _, file := range files {
+			fmt.Fprintf(buf, "\tif config.%s != nil {\n\t\tfiles = append(files, RequestFile{Name: %q, Data: config.%s})\n\t}\n", file.name, file.key, file.name)
+		}
+		buf.WriteString("\n\treturn files\n}\n\n")
+	}
+
+	g.writeResult(buf, method)
+}
+
+// writeResult declares the result of a method, which Call decodes. Methods
+// returning either a Message or True return a Message and are marked with
+// orTrue.
+func (g *generator) writeResult(buf *bytes.Buffer, method SpecMethod) {
+	returns := method.Returns
+	orTrue := false
+	if len(returns) > 1 && slices.Contains(returns, "True") {
+		returns = slices.DeleteFunc(slices.Clone(returns), func(typ string) bool { return typ == "True" })
+		orTrue = true
+	}
+
+	result, _ := g.goType(SpecField{Name: "result", Types: returns, Required: true}, false)
+	fmt.Fprintf(buf, "func (%s) result() (_ %s) { return }\n\n", configName(method.Name), result)
+	if orTrue {
+		fmt.Fprintf(buf, "func (%s) orTrue() {}\n\n", configName(method.Name))
+	}
+}
+
+// goType returns the Go type of a field, for a config or a type.
+func (g *generator) goType(field SpecField, config bool) (string, fieldKind) {
+	types := field.Types
+	if len(types) == 0 {
+		return "any", kindJSON
+	}
+
+	if len(types) > 1 {
+		if slices.Contains(types, "InputFile") {
+			return "RequestFileData", kindFile
+		}
+		scalar := true
+		for _, typ := range types {
+			scalar = scalar && (typ == "Integer" || typ == "String")
+		}
+		if scalar {
+			return "any", kindUnion
+		}
+		return g.unknownType(config), kindJSON
+	}
+
+	typ := types[0]
+	if elem, ok := strings.CutPrefix(typ, "Array of "); ok {
+		elemType, _ := g.goType(SpecField{Name: field.Name, Types: strings.Split(elem, " or "), Required: true}, config)
+		return "[]" + elemType, kindSlice
+	}
+
+	switch typ {
+	case "Integer":
+		if isInt64Field(field.Name) {
+			return "int64", kindInt64
+		}
+		return "int", kindInt
+	case "Float", "Float number":
+		return "float64", kindFloat
+	case "String":
+		return "string", kindString
+	case "Boolean", "True":
+		return "bool", kindBool
+	case "InputFile":
+		return "RequestFileData", kindFile
+	}
+
+	_, isStruct := g.index.structs[typ]
+	switch {
+	case isStruct || g.types[typ]:
+		if !field.Required {
+			return "*" + typ, kindJSON
+		}
+		return typ, kindJSON
+	case g.index.names[typ]:
+		return typ, kindJSON
+	}
+	return g.unknownType(config), kindJSON
+}
+
+// unknownType is the type of values of unions and types not generated.
+func (g *generator) unknownType(config bool) string {
+	if config {
+		return "any"
+	}
+	g.imports["encoding/json"] = true
+	return "json.RawMessage"
+}
+
+// isInt64Field reports whether an integer field may not fit in 32 bits, like
+// the IDs of users and chats.
+func isInt64Field(name string) bool {
+	return name == "id" || name == "date" || name == "file_size" ||
+		strings.HasSuffix(name, "user_id") || strings.HasSuffix(name, "chat_id") ||
+		strings.HasSuffix(name, "_date")
+}
+
+// writeComment writes a doc comment wrapped at 80 columns.
+func writeComment(buf *bytes.Buffer, indent, text string) {
+	line := indent + "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 80 && line != indent+"//" {
+			buf.WriteString(line + "\n")
+			line = indent + "//"
+		}
+		line += " " + word
+	}
+	buf.WriteString(line + "\n")
+}
+
+// typeDescription turns the description of a type into the rest of a doc
+// comment starting with its name.
+func typeDescription(description []string) string {
+	text := strings.Join(description, " ")
+	if rest, ok := strings.CutPrefix(text, "This object "); ok {
+		return rest
+	}
+	return lowerFirst(text)
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const packageDir = "../.."
+
+func testGenerator(t *testing.T) *generator {
+	t.Helper()
+
+	spec, err := loadSpec(filepath.Join("testdata", "spec.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := indexPackage(packageDir, "api_generated.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newGenerator(spec, index, index.pkg)
+}
+
+func TestGeneratorKeepsHandWrittenDeclarations(t *testing.T) {
+	g := testGenerator(t)
+
+	if !g.types["Postcard"] || g.types["User"] || g.types["Chat"] || g.types["PostcardStamp"] {
+		t.Fatalf("unexpected generated types %v", g.types)
+	}
+	if !g.configs["sendPostcard"] || !g.configs["getPostcards"] || g.configs["sendMessage"] {
+		t.Fatalf("unexpected generated configs %v", g.configs)
+	}
+	if !slices.Contains(g.warnings, "type User is missing fields has_postcards") {
+		t.Fatalf("expected a warning for the missing field, got %q", g.warnings)
+	}
+	if slices.Contains(g.checkedTypes, "User") || !slices.Contains(g.checkedTypes, "Chat") {
+		t.Fatalf("unexpected checked types %v", g.checkedTypes)
+	}
+
+	src, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type Postcard struct",
+		"\tSender *User `json:\"sender,omitempty\"`",
+		"\tPicture PhotoSize `json:\"picture\"`",
+		"\tStamp json.RawMessage `json:\"stamp,omitempty\"`",
+		"\tDate int64 `json:\"date\"`",
+		"type SendPostcardConfig struct {\n\tChatConfig\n\tPicture RequestFileData",
+		"params.AddNonZero(\"stamp_count\", config.StampCount)",
+		"params.AddFirstValid(\"from_chat_id\", config.FromChatID)",
+		"files = append(files, RequestFile{Name: \"picture\", Data: config.Picture})",
+		"func (SendPostcardConfig) result() (_ Message) { return }",
+		"func (GetPostcardsConfig) result() (_ []Postcard) { return }",
+		"func (EditPostcardConfig) result() (_ Message) { return }",
+		"func (EditPostcardConfig) orTrue() {}",
+	} {
+		if !strings.Contains(collapseSpace(string(src)), collapseSpace(want)) {
+			t.Errorf("expected generated code to contain %q", want)
+		}
+	}
+	for _, warning := range g.warnings {
+		if strings.HasSuffix(warning, "does not declare its result") {
+			t.Errorf("expected every config of the package to declare its result, got %q", warning)
+		}
+	}
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// TestGeneratedCodeCompiles builds a copy of the package with the generated
+// code and runs the generated tests.
+func TestGeneratedCodeCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping building a copy of the package in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	g := testGenerator(t)
+	src, err := g.generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	testSrc, err := g.generateTests()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files, err := filepath.Glob(filepath.Join(packageDir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range append(files, filepath.Join(packageDir, "go.mod")) {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(file)), data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api_generated.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api_generated_test.go"), testSrc, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goBin, "test", "-run", "TestAPI", ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go test: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// packageIndex are the hand-written declarations of the package, which take
+// precedence over generated ones.
+type packageIndex struct {
+	// pkg is the name of the package.
+	pkg string
+	// names are all top-level names.
+	names map[string]bool
+	// structs are the fields of struct types, by JSON name.
+	structs map[string]map[string]bool
+	// configs are the configs implementing each method.
+	configs map[string]configDecl
+	// results are the configs declaring the result of their method.
+	results map[string]bool
+}
+
+// configDecl is a hand-written config.
+type configDecl struct {
+	name string
+	// testable reports whether the zero value of the config is a Chattable.
+	testable bool
+}
+
+// indexPackage indexes the non-test Go files of dir, except skipped files.
+func indexPackage(dir string, skip ...string) (*packageIndex, error) {
+	index := &packageIndex{
+		names:   make(map[string]bool),
+		structs: make(map[string]map[string]bool),
+		configs: make(map[string]configDecl),
+		results: make(map[string]bool),
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || slices.Contains(skip, name) {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		index.pkg = file.Name.Name
+		for _, decl := range file.Decls {
+			index.addDecl(decl)
+		}
+	}
+
+	return index, nil
+}
+
+func (index *packageIndex) addDecl(decl ast.Decl) {
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		for _, spec := range decl.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				index.names[spec.Name.Name] = true
+				if st, ok := spec.Type.(*ast.StructType); ok && spec.TypeParams == nil {
+					index.structs[spec.Name.Name] = structFields(st)
+				}
+			case *ast.ValueSpec:
+				for _, name := range spec.Names {
+					index.names[name.Name] = true
+				}
+			}
+		}
+	case *ast.FuncDecl:
+		if decl.Recv == nil {
+			index.names[decl.Name.Name] = true
+			return
+		}
+		recv := decl.Recv.List[0].Type
+		receiver := receiverName(recv)
+		switch decl.Name.Name {
+		case "result":
+			index.results[receiver] = true
+		case "method":
+			if method, ok := returnedString(decl.Body); ok {
+				_, generic := recv.(*ast.IndexExpr)
+				_, pointer := recv.(*ast.StarExpr)
+				index.configs[method] = configDecl{name: receiver, testable: !generic && !pointer}
+			}
+		}
+	}
+}
+
+// structFields returns the JSON names of the fields of a struct.
+func structFields(st *ast.StructType) map[string]bool {
+	fields := make(map[string]bool)
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		jsonTag, ok := lookupTag(tag, "json")
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(jsonTag, ",")
+		fields[name] = true
+	}
+	return fields
+}
+
+func lookupTag(tag, key string) (string, bool) {
+	for _, part := range strings.Fields(tag) {
+		name, value, ok := strings.Cut(part, ":")
+		if ok && name == key {
+			unquoted, err := strconv.Unquote(value)
+			return unquoted, err == nil
+		}
+	}
+	return "", false
+}
+
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// returnedString returns the string literal returned by a function body
+// consisting of a single return statement.
+func returnedString(body *ast.BlockStmt) (string, bool) {
+	if body == nil || len(body.List) != 1 {
+		return "", false
+	}
+	ret, ok := body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
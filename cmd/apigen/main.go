@@ -0,0 +1,62 @@
+// Command apigen generates the types, configs and BotAPI methods of the Bot
+// API specification which are missing from the package.
+//
+// The specification is the JSON document loaded by api_parity_test.go:
+//
+//	go run ./cmd/apigen -spec api.json
+//
+// Hand-written declarations always take precedence: a type or config
+// declared in any other file of the package is never generated, and
+// hand-written types missing fields of the specification are reported so
+// they can be updated by hand. The generated tests check that every method
+// has a config and that the types have all fields of the specification.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	specPath := flag.String("spec", "api.json", "Bot API specification")
+	dir := flag.String("dir", ".", "package directory")
+	out := flag.String("out", "api_generated.go", "generated declarations, in the package directory")
+	testOut := flag.String("test", "api_generated_test.go", "generated tests, in the package directory")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("apigen: ")
+
+	spec, err := loadSpec(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	index, err := indexPackage(*dir, *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	g := newGenerator(spec, index, index.pkg)
+	src, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	testSrc, err := g.generateTests()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(*dir, *out), src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *testOut), testSrc, 0o644); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, warning := range g.warnings {
+		fmt.Fprintln(os.Stderr, "apigen: "+warning)
+	}
+	fmt.Fprintf(os.Stderr, "apigen: generated %d types and %d methods\n", len(g.types), len(g.configs))
+}
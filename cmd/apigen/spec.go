@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Spec is a machine-readable Bot API specification, in the format loaded by
+// api_parity_test.go.
+type Spec struct {
+	Version string                `json:"version"`
+	Methods map[string]SpecMethod `json:"methods"`
+	Types   map[string]SpecType   `json:"types"`
+}
+
+// SpecMethod is a method of the Bot API.
+type SpecMethod struct {
+	Name        string      `json:"name"`
+	Description []string    `json:"description"`
+	Returns     []string    `json:"returns"`
+	Fields      []SpecField `json:"fields"`
+}
+
+// SpecType is a type of the Bot API. Types with subtypes are unions of them.
+type SpecType struct {
+	Name        string      `json:"name"`
+	Description []string    `json:"description"`
+	Fields      []SpecField `json:"fields"`
+	Subtypes    []string    `json:"subtypes"`
+}
+
+// SpecField is a field of a type or a parameter of a method.
+type SpecField struct {
+	Name        string   `json:"name"`
+	Types       []string `json:"types"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description"`
+}
+
+func loadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	for name, method := range spec.Methods {
+		if method.Name == "" {
+			method.Name = name
+			spec.Methods[name] = method
+		}
+	}
+	for name, typ := range spec.Types {
+		if typ.Name == "" {
+			typ.Name = name
+			spec.Types[name] = typ
+		}
+	}
+
+	return &spec, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// initialisms are the words written in upper case in Go names.
+var initialisms = map[string]string{
+	"id":  "ID",
+	"ip":  "IP",
+	"url": "URL",
+}
+
+// goName converts a snake_case or camelCase API name to an exported Go name.
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if initialism, ok := initialisms[part]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// configName returns the name of the config of a method.
+func configName(method string) string {
+	return goName(method) + "Config"
+}
@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/url"
 	"os"
+	"path"
 	"strconv"
 )
 
@@ -231,6 +233,62 @@ func (fp FilePath) SendData() string {
 	panic("FilePath must be uploaded")
 }
 
+// FileFS is a file in an fs.FS, such as an embed.FS.
+//
+// The file is opened every time it is uploaded.
+type FileFS struct {
+	FS   fs.FS
+	Path string
+	// ContentType is the MIME type of the file. Optional.
+	ContentType string
+}
+
+func (ff FileFS) NeedsUpload() bool {
+	return true
+}
+
+func (ff FileFS) UploadData() (string, io.Reader, error) {
+	file, err := ff.FS.Open(ff.Path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return path.Base(ff.Path), file, nil
+}
+
+func (ff FileFS) SendData() string {
+	panic("FileFS must be uploaded")
+}
+
+// FileOpener is a file opened by a function every time it is uploaded, so it
+// can be uploaded again, unlike a FileReader.
+type FileOpener struct {
+	Name string
+	// Open returns a new reader of the content of the file.
+	Open func() (io.ReadCloser, error)
+	// Size is the size of the file in bytes, or 0 if unknown. Optional.
+	Size int64
+	// ContentType is the MIME type of the file. Optional.
+	ContentType string
+}
+
+func (fo FileOpener) NeedsUpload() bool {
+	return true
+}
+
+func (fo FileOpener) UploadData() (string, io.Reader, error) {
+	reader, err := fo.Open()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return fo.Name, reader, nil
+}
+
+func (fo FileOpener) SendData() string {
+	panic("FileOpener must be uploaded")
+}
+
 // FileURL is a URL to use as a file for a request.
 type FileURL string
 
@@ -3327,11 +3385,7 @@ func (config NewStickerSetConfig) params() (Params, error) {
 }
 
 func (config NewStickerSetConfig) files() []RequestFile {
-	requestFiles := []RequestFile{}
-	for _, v := range config.Stickers {
-		requestFiles = append(requestFiles, v.Sticker)
-	}
-	return requestFiles
+	return inputStickerFiles(config.Stickers...)
 }
 
 // AddStickerConfig allows you to add a sticker to a set.
@@ -3355,7 +3409,19 @@ func (config AddStickerConfig) params() (Params, error) {
 }
 
 func (config AddStickerConfig) files() []RequestFile {
-	return []RequestFile{config.Sticker.Sticker}
+	return inputStickerFiles(config.Sticker)
+}
+
+// inputStickerFiles returns the stickers to upload. Other stickers are
+// referenced in the JSON of their InputSticker.
+func inputStickerFiles(stickers ...InputSticker) []RequestFile {
+	requestFiles := []RequestFile{}
+	for _, sticker := range stickers {
+		if sticker.Sticker.Data != nil && sticker.Sticker.Data.NeedsUpload() {
+			requestFiles = append(requestFiles, sticker.Sticker)
+		}
+	}
+	return requestFiles
 }
 
 // SetStickerPositionConfig allows you to change the position of a sticker in a set.
@@ -3476,6 +3542,10 @@ func (config ReplaceStickerInSetConfig) params() (Params, error) {
 	return params, err
 }
 
+func (config ReplaceStickerInSetConfig) files() []RequestFile {
+	return inputStickerFiles(config.Sticker)
+}
+
 // SetStickerEmojiListConfig allows you to change the list of emoji assigned to a regular or custom emoji sticker. The sticker must belong to a sticker set created by the bot
 type SetStickerEmojiListConfig struct {
 	Sticker   string
@@ -0,0 +1,156 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxDownloadFileSize is the largest file the Bot API allows bots to download.
+const MaxDownloadFileSize = 20 << 20
+
+// Download errors. They are wrapped with details, use errors.Is to check them.
+var (
+	// ErrFileTooBig is returned when a file is larger than the Bot API allows
+	// bots to download or than the configured maximum download size.
+	ErrFileTooBig = errors.New("file is too big")
+	// ErrFilePathExpired is returned when the file path returned by getFile
+	// is no longer valid. Downloading the file again gets a new one.
+	ErrFilePathExpired = errors.New("file path expired")
+	// ErrFileSizeMismatch is returned when the downloaded content does not
+	// match the size reported by getFile.
+	ErrFileSizeMismatch = errors.New("downloaded file size mismatch")
+)
+
+// DownloadFile downloads the file with the given ID and writes its content to w.
+//
+// It returns the File returned by getFile. Files larger than the
+// BotAPI.MaxDownloadSize method returns are rejected with ErrFileTooBig,
+// unless it returns 0, which means the size is not limited. In local mode,
+// the file is read from the path returned by getFile.
+func (bot *BotAPI) DownloadFile(fileID string, w io.Writer) (File, error) {
+	return bot.DownloadFileWithContext(context.Background(), fileID, w)
+}
+
+func (bot *BotAPI) DownloadFileWithContext(ctx context.Context, fileID string, w io.Writer) (File, error) {
+	file, err := bot.getFileWithContext(ctx, fileID)
+	if err != nil {
+		return file, err
+	}
+
+	limit := bot.MaxDownloadSize()
+	if limit > 0 && file.FileSize > limit {
+		return file, fmt.Errorf("%w: %d bytes, limit is %d", ErrFileTooBig, file.FileSize, limit)
+	}
+
+	body, err := bot.openFile(ctx, file)
+	if err != nil {
+		return file, err
+	}
+	defer body.Close()
+
+	reader := io.Reader(body)
+	if limit > 0 {
+		reader = io.LimitReader(body, limit+1)
+	}
+
+	n, err := io.Copy(w, reader)
+	if err != nil {
+		return file, redactURLError(err)
+	}
+	if limit > 0 && n > limit {
+		return file, fmt.Errorf("%w: more than %d bytes", ErrFileTooBig, limit)
+	}
+	if file.FileSize > 0 && n != file.FileSize {
+		return file, fmt.Errorf("%w: got %d bytes, expected %d", ErrFileSizeMismatch, n, file.FileSize)
+	}
+
+	return file, nil
+}
+
+// openFile opens the content of a file returned by getFile.
+func (bot *BotAPI) openFile(ctx context.Context, file File) (io.ReadCloser, error) {
+	if bot.localMode && filepath.IsAbs(file.FilePath) {
+		f, err := os.Open(file.FilePath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %w", ErrFilePathExpired, err)
+		}
+		return f, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bot.FileURL(file), nil)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
+
+	resp, err := bot.Client.Do(req)
+	if err != nil {
+		return nil, redactURLError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrFilePathExpired, file.FilePath)
+	}
+
+	resp.Body.Close()
+	return nil, fmt.Errorf("download file %s: unexpected status %s", file.FilePath, resp.Status)
+}
+
+// DownloadToPath downloads the file with the given ID to path.
+//
+// The content is written to a temporary file next to path, which is renamed
+// to path once the download is complete, so path never contains a partial
+// download.
+func (bot *BotAPI) DownloadToPath(fileID, path string) (File, error) {
+	return bot.DownloadToPathWithContext(context.Background(), fileID, path)
+}
+
+func (bot *BotAPI) DownloadToPathWithContext(ctx context.Context, fileID, path string) (File, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return File{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	file, err := bot.DownloadFileWithContext(ctx, fileID, tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return file, err
+	}
+
+	return file, os.Rename(tmp.Name(), path)
+}
+
+func (bot *BotAPI) getFileWithContext(ctx context.Context, fileID string) (File, error) {
+	file, err := Call(ctx, bot, FileConfig{fileID})
+	var apiErr *Error
+	if errors.As(err, &apiErr) && strings.Contains(strings.ToLower(apiErr.Message), "file is too big") {
+		return File{}, fmt.Errorf("%w: %w", ErrFileTooBig, err)
+	}
+
+	return file, err
+}
+
+// redactURLError removes the URL, which contains the bot token, from errors
+// returned by the HTTP client.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("download file: %s: %w", urlErr.Op, urlErr.Err)
+	}
+
+	return err
+}
@@ -0,0 +1,95 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func downloadTestBot(getFile string, status int, content string) *BotAPI {
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Host == "files.example.com" {
+				return &http.Response{
+					StatusCode: status,
+					Status:     http.StatusText(status),
+					Body:       io.NopCloser(strings.NewReader(content)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(getFile)),
+			}, nil
+		},
+	})
+	bot.fileEndpoint = "https://files.example.com/bot%s/%s"
+
+	return bot
+}
+
+func TestDownloadFile(t *testing.T) {
+	bot := downloadTestBot(`{"ok":true,"result":{"file_id":"id","file_size":5,"file_path":"photos/a.jpg"}}`, http.StatusOK, "hello")
+
+	var buf bytes.Buffer
+	file, err := bot.DownloadFile("id", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "hello" || file.FilePath != "photos/a.jpg" {
+		t.Fatalf("unexpected download %q of %+v", buf.String(), file)
+	}
+
+	path := filepath.Join(t.TempDir(), "a.jpg")
+	if _, err := bot.DownloadToPathWithContext(context.Background(), "id", path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("unexpected file content %q: %v", data, err)
+	}
+}
+
+func TestDownloadFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		getFile  string
+		status   int
+		content  string
+		limit    int64
+		expected error
+	}{
+		{"api too big", `{"ok":false,"error_code":400,"description":"Bad Request: file is too big"}`, http.StatusOK, "", 0, ErrFileTooBig},
+		{"size over limit", `{"ok":true,"result":{"file_size":10,"file_path":"a"}}`, http.StatusOK, "", 5, ErrFileTooBig},
+		{"content over limit", `{"ok":true,"result":{"file_path":"a"}}`, http.StatusOK, "0123456789", 5, ErrFileTooBig},
+		{"expired", `{"ok":true,"result":{"file_path":"a"}}`, http.StatusNotFound, "", 0, ErrFilePathExpired},
+		{"truncated", `{"ok":true,"result":{"file_size":10,"file_path":"a"}}`, http.StatusOK, "01234", 0, ErrFileSizeMismatch},
+	}
+
+	for _, test := range tests {
+		bot := downloadTestBot(test.getFile, test.status, test.content)
+		bot.maxDownloadSize = test.limit
+
+		_, err := bot.DownloadFile("id", io.Discard)
+		if !errors.Is(err, test.expected) {
+			t.Fatalf("%s: expected %v, got %v", test.name, test.expected, err)
+		}
+		if strings.Contains(err.Error(), bot.Token) {
+			t.Fatalf("%s: error leaks the token: %v", test.name, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "a")
+	bot := downloadTestBot(`{"ok":true,"result":{"file_path":"a"}}`, http.StatusNotFound, "")
+	if _, err := bot.DownloadToPath("id", path); !errors.Is(err, ErrFilePathExpired) {
+		t.Fatalf("expected ErrFilePathExpired, got %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 0 {
+		t.Fatalf("failed download left files behind: %v", entries)
+	}
+}
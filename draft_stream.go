@@ -0,0 +1,399 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// MaxMessageTextLength is the maximum length of a message text, in UTF-16 code units.
+	MaxMessageTextLength = 4096
+
+	defaultDraftStreamInterval = time.Second
+)
+
+// ErrDraftStreamClosed is returned when writing to a closed DraftStream.
+var ErrDraftStreamClosed = errors.New("draft stream is closed")
+
+// DraftStreamConfig configures a DraftStream.
+//
+// BaseChat is used for the final messages; ReplyParameters only apply to the
+// first committed message and ReplyMarkup only to the last one.
+type DraftStreamConfig struct {
+	BaseChat
+	// DraftID is the identifier of the first draft. A random identifier is
+	// used when zero. Every message split off the stream uses the next one.
+	DraftID int
+	// ParseMode is the parse mode of the text. When Rich is set, ModeHTML
+	// sends the text as rich HTML and anything else as rich Markdown.
+	ParseMode string
+	// Rich sends drafts with sendRichMessageDraft and the final message with
+	// sendRichMessage instead of sendMessageDraft and sendMessage.
+	Rich bool
+	// Interval is the minimum time between two draft updates, one second by default.
+	Interval time.Duration
+	// MaxLength is the maximum length of a single message in UTF-16 code
+	// units, MaxMessageTextLength by default. Longer text is split into
+	// several messages.
+	MaxLength int
+}
+
+// NewDraftStreamConfig creates a new DraftStreamConfig for a chat.
+func NewDraftStreamConfig(chatID int64) DraftStreamConfig {
+	return DraftStreamConfig{
+		BaseChat: BaseChat{
+			ChatConfig: ChatConfig{
+				ChatID: chatID,
+			},
+		},
+	}
+}
+
+// DraftStream streams an incrementally generated reply as message drafts.
+//
+// Text written to the stream is coalesced into draft updates sent at most
+// once per Interval. Text that no longer fits into a single message is
+// committed as a message of its own and the stream continues in a new
+// draft. Close commits the remaining text as the final message.
+//
+// If the context of the stream ends, writes fail and Close returns without
+// sending anything.
+type DraftStream struct {
+	bot    *BotAPI
+	ctx    context.Context
+	config DraftStreamConfig
+
+	mu sync.Mutex
+	// sent is signalled when a draft request finishes.
+	sent      *sync.Cond
+	buf       []byte
+	draftID   int
+	lastDraft string
+	lastSent  time.Time
+	timer     *time.Timer
+	sending   bool
+	draftErr  error
+	messages  []Message
+	closed    bool
+}
+
+// NewDraftStream starts a new DraftStream. All requests are made with ctx.
+func (bot *BotAPI) NewDraftStream(ctx context.Context, config DraftStreamConfig) *DraftStream {
+	if config.Interval <= 0 {
+		config.Interval = defaultDraftStreamInterval
+	}
+	if config.MaxLength <= 0 {
+		config.MaxLength = MaxMessageTextLength
+	}
+
+	draftID := config.DraftID
+	if draftID == 0 {
+		draftID = rand.IntN(1<<31-1) + 1
+	}
+
+	s := &DraftStream{
+		bot:     bot,
+		ctx:     ctx,
+		config:  config,
+		draftID: draftID,
+	}
+	s.sent = sync.NewCond(&s.mu)
+
+	return s
+}
+
+// Write appends p to the streamed text.
+func (s *DraftStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrDraftStreamClosed
+	}
+	if err := s.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.buf = append(s.buf, p...)
+
+	for markupLength(s.text(), s.parseMode()) > s.config.MaxLength {
+		if err := s.commitOverflow(); err != nil {
+			return len(p), err
+		}
+	}
+
+	if err := s.draftErr; err != nil {
+		s.draftErr = nil
+		return len(p), err
+	}
+
+	return len(p), s.scheduleDraft()
+}
+
+// WriteString appends text to the streamed text.
+func (s *DraftStream) WriteString(text string) (int, error) {
+	return s.Write([]byte(text))
+}
+
+// Close commits the remaining text as the final message. It also returns
+// the error of a draft update not returned by Write yet.
+//
+// Calling Close more than once has no effect.
+func (s *DraftStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.stopTimer()
+	s.waitDraft()
+
+	draftErr := s.draftErr
+	s.draftErr = nil
+
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	text := strings.ToValidUTF8(string(s.buf), "")
+	if strings.TrimSpace(text) == "" {
+		return draftErr
+	}
+
+	_, err := s.commit(text, true)
+	s.buf = nil
+
+	return errors.Join(draftErr, err)
+}
+
+// Messages returns the messages committed so far.
+func (s *DraftStream) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// text returns the buffered text without a trailing incomplete rune.
+func (s *DraftStream) text() string {
+	end := len(s.buf)
+	for i := len(s.buf) - 1; i >= 0 && i >= len(s.buf)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s.buf[i]) {
+			if !utf8.FullRune(s.buf[i:]) {
+				end = i
+			}
+			break
+		}
+	}
+
+	return string(s.buf[:end])
+}
+
+// parseMode returns the markup of the streamed text.
+func (s *DraftStream) parseMode() string {
+	if s.config.Rich && s.config.ParseMode != ModeHTML {
+		return modeRichMarkdown
+	}
+
+	return s.config.ParseMode
+}
+
+func (s *DraftStream) scheduleDraft() error {
+	wait := s.config.Interval - time.Since(s.lastSent)
+	if wait <= 0 && !s.sending {
+		return s.sendDraft()
+	}
+
+	if s.timer == nil {
+		s.timer = time.AfterFunc(max(wait, 0), s.flushDraft)
+	}
+
+	return nil
+}
+
+func (s *DraftStream) flushDraft() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.timer = nil
+	if s.closed || s.ctx.Err() != nil {
+		return
+	}
+
+	if s.sending {
+		s.timer = time.AfterFunc(s.config.Interval, s.flushDraft)
+		return
+	}
+	if err := s.sendDraft(); err != nil {
+		s.draftErr = err
+	}
+}
+
+// waitDraft waits for a draft update in progress to finish.
+func (s *DraftStream) waitDraft() {
+	for s.sending {
+		s.sent.Wait()
+	}
+}
+
+func (s *DraftStream) stopTimer() {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+// sendDraft sends the buffered text as a draft. It is called with s.mu
+// locked and unlocks it while the request is made.
+func (s *DraftStream) sendDraft() error {
+	text := s.text()
+	if strings.TrimSpace(text) == "" || text == s.lastDraft {
+		return nil
+	}
+
+	var config Chattable
+	if s.config.Rich {
+		config = SendRichMessageDraftConfig{
+			ChatConfig:      s.config.ChatConfig,
+			MessageThreadID: s.config.MessageThreadID,
+			DraftID:         s.draftID,
+			RichMessage:     s.richMessage(text),
+		}
+	} else {
+		config = SendMessageDraftConfig{
+			ChatConfig:      s.config.ChatConfig,
+			MessageThreadID: s.config.MessageThreadID,
+			DraftID:         s.draftID,
+			Text:            text,
+			ParseMode:       s.config.ParseMode,
+		}
+	}
+
+	// Writes continue while the draft is sent; commits wait for it, so it
+	// cannot arrive after a message replacing it.
+	s.lastSent = time.Now()
+	s.sending = true
+	s.mu.Unlock()
+	_, err := s.bot.RequestWithContext(s.ctx, config)
+	s.mu.Lock()
+	s.sending = false
+	s.sent.Broadcast()
+
+	if err != nil {
+		return err
+	}
+	s.lastDraft = text
+
+	return nil
+}
+
+// commitOverflow sends the part of the buffer that fits into one message
+// and continues the stream in a new draft.
+//
+// Entities open at the split are closed in the message and reopened in the
+// rest of the text.
+func (s *DraftStream) commitOverflow() error {
+	s.waitDraft()
+
+	text := s.text()
+	head, rest := splitMarkup(text, s.parseMode(), s.config.MaxLength)
+
+	if _, err := s.commit(head, false); err != nil {
+		return err
+	}
+
+	s.buf = append([]byte(rest), s.buf[len(text):]...)
+	s.draftID++
+	s.lastDraft = ""
+	s.stopTimer()
+
+	return nil
+}
+
+func (s *DraftStream) commit(text string, last bool) (Message, error) {
+	base := s.config.BaseChat
+	if !last {
+		base.ReplyMarkup = nil
+	}
+	if len(s.messages) > 0 {
+		base.ReplyParameters = ReplyParameters{}
+	}
+
+	var config Chattable
+	if s.config.Rich {
+		config = SendRichMessageConfig{
+			BaseChat:    base,
+			RichMessage: s.richMessage(text),
+		}
+	} else {
+		config = MessageConfig{
+			BaseChat:  base,
+			Text:      text,
+			ParseMode: s.config.ParseMode,
+		}
+	}
+
+	message, err := s.bot.SendWithContext(s.ctx, config)
+	if err != nil {
+		return message, err
+	}
+	s.messages = append(s.messages, message)
+
+	return message, nil
+}
+
+func (s *DraftStream) richMessage(text string) InputRichMessage {
+	if s.config.ParseMode == ModeHTML {
+		return NewInputRichMessageHTML(text)
+	}
+
+	return NewInputRichMessageMarkdown(text)
+}
+
+// utf16Length returns the length of text in UTF-16 code units, the unit
+// Telegram uses for text lengths and entity offsets.
+func utf16Length(text string) int {
+	n := 0
+	for _, r := range text {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// splitTextIndex returns the byte index at which text should be split so
+// that the first part is at most limit UTF-16 code units long. It prefers
+// splitting at the last newline, then at the last space.
+func splitTextIndex(text string, limit int) int {
+	end, units := 0, 0
+	for i, r := range text {
+		units += utf16.RuneLen(r)
+		if units > limit {
+			break
+		}
+		end = i + utf8.RuneLen(r)
+	}
+	if end == 0 {
+		_, size := utf8.DecodeRuneInString(text)
+		return size
+	}
+	if end == len(text) {
+		return end
+	}
+
+	if i := strings.LastIndexByte(text[:end], '\n'); i > 0 {
+		return i
+	}
+	if i := strings.LastIndexByte(text[:end], ' '); i > 0 {
+		return i
+	}
+
+	return end
+}
@@ -0,0 +1,323 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type draftStreamRecorder struct {
+	mu       sync.Mutex
+	methods  []string
+	requests []url.Values
+	// respond, if set, is called after recording a request and returns the
+	// response to send instead of a successful one.
+	respond func(method string, count int) string
+}
+
+func (r *draftStreamRecorder) client(t *testing.T) fakeHTTPClient {
+	return fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("read request body: %v", err)
+			}
+			values, err := url.ParseQuery(string(body))
+			if err != nil {
+				t.Fatalf("parse request body: %v", err)
+			}
+
+			method := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+			r.mu.Lock()
+			r.methods = append(r.methods, method)
+			r.requests = append(r.requests, values)
+			count := len(r.methods)
+			r.mu.Unlock()
+
+			response := ""
+			if r.respond != nil {
+				response = r.respond(method, count)
+			}
+			if response == "" {
+				result := `true`
+				if method == "sendMessage" || method == "sendRichMessage" {
+					result = `{"message_id":` + strconv.Itoa(count) + `,"date":1,"chat":{"id":1,"type":"private"}}`
+				}
+				response = `{"ok":true,"result":` + result + `}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(response)),
+			}, nil
+		},
+	}
+}
+
+func (r *draftStreamRecorder) snapshot() ([]string, []url.Values) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.methods...), append([]url.Values(nil), r.requests...)
+}
+
+func TestDraftStreamCoalescesDraftsAndCommitsOnClose(t *testing.T) {
+	recorder := &draftStreamRecorder{}
+	bot := newFakeBot(recorder.client(t))
+
+	config := NewDraftStreamConfig(1)
+	config.DraftID = 7
+	config.Interval = time.Hour
+	config.ReplyMarkup = NewInlineKeyboardMarkup(NewInlineKeyboardRow(NewInlineKeyboardButtonData("ok", "ok")))
+	stream := bot.NewDraftStream(context.Background(), config)
+
+	for _, chunk := range []string{"Hel", "lo, ", "world"} {
+		if _, err := stream.WriteString(chunk); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("second close: %v", err)
+	}
+	if _, err := stream.WriteString("late"); !errors.Is(err, ErrDraftStreamClosed) {
+		t.Fatalf("expected ErrDraftStreamClosed, got %v", err)
+	}
+
+	methods, requests := recorder.snapshot()
+	if strings.Join(methods, ",") != "sendMessageDraft,sendMessage" {
+		t.Fatalf("unexpected requests: %v", methods)
+	}
+	if requests[0].Get("draft_id") != "7" || requests[0].Get("text") != "Hel" {
+		t.Fatalf("unexpected draft: %v", requests[0])
+	}
+	if requests[1].Get("text") != "Hello, world" || requests[1].Get("reply_markup") == "" {
+		t.Fatalf("unexpected final message: %v", requests[1])
+	}
+	if len(stream.Messages()) != 1 {
+		t.Fatalf("unexpected messages: %+v", stream.Messages())
+	}
+}
+
+func TestDraftStreamFlushesPendingDraftAfterInterval(t *testing.T) {
+	recorder := &draftStreamRecorder{}
+	bot := newFakeBot(recorder.client(t))
+
+	config := NewDraftStreamConfig(1)
+	config.Rich = true
+	config.Interval = 20 * time.Millisecond
+	stream := bot.NewDraftStream(context.Background(), config)
+
+	_, _ = stream.WriteString("**Hello")
+	_, _ = stream.WriteString(" world**")
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		methods, requests := recorder.snapshot()
+		if len(methods) == 2 {
+			if methods[1] != "sendRichMessageDraft" || !strings.Contains(requests[1].Get("rich_message"), `"markdown":"**Hello world**"`) {
+				t.Fatalf("unexpected trailing draft: %v %v", methods, requests[1])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pending draft was not flushed: %v", methods)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	methods, _ := recorder.snapshot()
+	if methods[len(methods)-1] != "sendRichMessage" {
+		t.Fatalf("expected final rich message: %v", methods)
+	}
+}
+
+func TestDraftStreamSplitsOverflowIntoMessages(t *testing.T) {
+	recorder := &draftStreamRecorder{}
+	bot := newFakeBot(recorder.client(t))
+
+	config := NewDraftStreamConfig(1)
+	config.DraftID = 1
+	config.Interval = time.Hour
+	config.MaxLength = 12
+	config.ReplyParameters.MessageID = 99
+	stream := bot.NewDraftStream(context.Background(), config)
+
+	if _, err := stream.WriteString("first line\nsecond line\nthird"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	methods, requests := recorder.snapshot()
+	var texts []string
+	for i, method := range methods {
+		if method == "sendMessage" {
+			texts = append(texts, requests[i].Get("text"))
+		}
+	}
+	if strings.Join(texts, "|") != "first line|second line|third" {
+		t.Fatalf("unexpected messages: %q", texts)
+	}
+	if !strings.Contains(requests[0].Get("reply_parameters"), `"message_id":99`) || strings.Contains(requests[1].Get("reply_parameters"), "99") {
+		t.Fatalf("reply parameters must only apply to the first message: %v", requests)
+	}
+	if methods[len(methods)-2] != "sendMessageDraft" || requests[len(requests)-2].Get("draft_id") != "3" {
+		t.Fatalf("expected the last draft to use a new draft ID: %v %v", methods, requests)
+	}
+}
+
+func TestDraftStreamSplitsMarkupOnEntityBoundaries(t *testing.T) {
+	recorder := &draftStreamRecorder{}
+	bot := newFakeBot(recorder.client(t))
+
+	config := NewDraftStreamConfig(1)
+	config.Interval = time.Hour
+	config.ParseMode = ModeHTML
+	config.MaxLength = 12
+	stream := bot.NewDraftStream(context.Background(), config)
+
+	if _, err := stream.WriteString("<b>bold &amp; long text</b> end"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	methods, requests := recorder.snapshot()
+	var texts []string
+	for i, method := range methods {
+		if method == "sendMessage" {
+			texts = append(texts, requests[i].Get("text"))
+		}
+	}
+	if strings.Join(texts, "|") != "<b>bold &amp; long</b>|<b>text</b> end" {
+		t.Fatalf("unexpected messages: %q", texts)
+	}
+}
+
+func TestDraftStreamCloseReturnsDraftError(t *testing.T) {
+	recorder := &draftStreamRecorder{}
+	recorder.respond = func(method string, count int) string {
+		if method == "sendMessageDraft" && count > 1 {
+			return `{"ok":false,"error_code":400,"description":"Bad Request: draft failed"}`
+		}
+		return ""
+	}
+	bot := newFakeBot(recorder.client(t))
+
+	config := NewDraftStreamConfig(1)
+	config.Interval = 10 * time.Millisecond
+	stream := bot.NewDraftStream(context.Background(), config)
+
+	_, _ = stream.WriteString("Hello")
+	_, _ = stream.WriteString(", world")
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		if methods, _ := recorder.snapshot(); len(methods) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("pending draft was not flushed")
+		}
+	}
+
+	err := stream.Close()
+	if err == nil || !strings.Contains(err.Error(), "draft failed") {
+		t.Fatalf("expected the error of the last draft, got %v", err)
+	}
+	if methods, _ := recorder.snapshot(); methods[len(methods)-1] != "sendMessage" {
+		t.Fatalf("expected the final message to be sent, got %v", methods)
+	}
+}
+
+func TestDraftStreamWriteDoesNotWaitForDraft(t *testing.T) {
+	sending, release := make(chan struct{}), make(chan struct{})
+	recorder := &draftStreamRecorder{}
+	recorder.respond = func(method string, count int) string {
+		if count == 2 {
+			close(sending)
+			<-release
+		}
+		return ""
+	}
+	bot := newFakeBot(recorder.client(t))
+
+	config := NewDraftStreamConfig(1)
+	config.Interval = 10 * time.Millisecond
+	stream := bot.NewDraftStream(context.Background(), config)
+
+	_, _ = stream.WriteString("Hello")
+	_, _ = stream.WriteString(", world")
+	<-sending
+
+	written := make(chan error)
+	go func() {
+		_, err := stream.WriteString("!")
+		written <- err
+	}()
+	select {
+	case err := <-written:
+		if err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("write waited for the draft to be sent")
+	}
+
+	close(release)
+	if err := stream.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	methods, requests := recorder.snapshot()
+	if last := requests[len(requests)-1]; methods[len(methods)-1] != "sendMessage" || last.Get("text") != "Hello, world!" {
+		t.Fatalf("unexpected final message: %v %v", methods, last)
+	}
+}
+
+func TestDraftStreamCancelledContextSkipsFinalMessage(t *testing.T) {
+	recorder := &draftStreamRecorder{}
+	bot := newFakeBot(recorder.client(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	config := NewDraftStreamConfig(1)
+	config.Interval = time.Hour
+	stream := bot.NewDraftStream(ctx, config)
+
+	_, _ = stream.WriteString("partial")
+	cancel()
+
+	if _, err := stream.WriteString(" more"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context error from write, got %v", err)
+	}
+	if err := stream.Close(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context error from close, got %v", err)
+	}
+
+	methods, _ := recorder.snapshot()
+	for _, method := range methods {
+		if method == "sendMessage" {
+			t.Fatalf("final message sent after cancellation: %v", methods)
+		}
+	}
+}
+
+func TestSplitTextIndexCountsUTF16(t *testing.T) {
+	text := "😀😀😀"
+	if got := splitTextIndex(text, 4); got != len("😀😀") {
+		t.Fatalf("unexpected split index %d", got)
+	}
+	if got := utf16Length(text); got != 6 {
+		t.Fatalf("unexpected UTF-16 length %d", got)
+	}
+}
@@ -0,0 +1,257 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DryRunRecord is a request which was not sent to Telegram because the bot
+// was created with WithDryRun.
+type DryRunRecord struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	Params Params    `json:"params"`
+	// Files maps the parameters of uploaded files to their names.
+	Files map[string]string `json:"files,omitempty"`
+	// Result is the synthetic result returned instead of the response of
+	// Telegram.
+	Result json.RawMessage `json:"result"`
+}
+
+// WithDryRun makes the bot only send read-only requests to Telegram, those
+// of the methods starting with "get" such as getMe, getUpdates, getChat and
+// getFile. Every other request is answered with a synthetic result instead,
+// such as a Message with an incrementing ID for sendMessage, and written to
+// sink as a line of JSON encoding a DryRunRecord. The sink may be nil.
+//
+// It allows running a new version of a bot against real updates without it
+// changing anything. Note that getUpdates still confirms the updates it
+// receives, so another instance polling them does not get them anymore.
+func WithDryRun(sink io.Writer) BotAPIOption {
+	return func(config *botAPIConfig) error {
+		config.dryRun = &dryRun{sink: sink}
+		return nil
+	}
+}
+
+// isReadOnlyMethod reports whether requests of a method are sent to Telegram
+// in dry-run mode.
+func isReadOnlyMethod(method string) bool {
+	return strings.HasPrefix(method, "get")
+}
+
+// dryRun records the requests of a bot in dry-run mode.
+type dryRun struct {
+	sink io.Writer
+
+	mu         sync.Mutex
+	lastID     int
+	lastFileID int
+}
+
+func (d *dryRun) request(ctx context.Context, self User, method string, params Params, files []RequestFile) (*APIResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result, err := json.Marshal(d.result(self, method, params))
+	if err != nil {
+		return nil, err
+	}
+
+	if d.sink != nil {
+		record := DryRunRecord{
+			Time:   time.Now(),
+			Method: method,
+			Params: params,
+			Result: result,
+		}
+		for _, file := range files {
+			if file.Data == nil || !file.Data.NeedsUpload() {
+				continue
+			}
+			if record.Files == nil {
+				record.Files = make(map[string]string)
+			}
+			record.Files[file.Name], _ = uploadName(file.Data)
+		}
+
+		line, err := json.Marshal(record)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := d.sink.Write(append(line, '\n')); err != nil {
+			return nil, err
+		}
+	}
+
+	return &APIResponse{Ok: true, Result: result}, nil
+}
+
+// result returns a plausible result of a request.
+func (d *dryRun) result(self User, method string, params Params) any {
+	switch method {
+	case "sendChatAction", "sendMessageDraft", "sendRichMessageDraft", "sendChatJoinRequestWebApp", "sendGift":
+		return true
+	case "sendMediaGroup":
+		var media []struct {
+			Type    string `json:"type"`
+			Caption string `json:"caption"`
+		}
+		_ = json.Unmarshal([]byte(params["media"]), &media)
+
+		messages := make([]Message, len(media))
+		for i, item := range media {
+			messages[i] = d.message(self, item.Type, params)
+			messages[i].MessageID = d.nextID()
+			messages[i].Caption = item.Caption
+			messages[i].MediaGroupID = strconv.Itoa(messages[0].MessageID)
+		}
+		return messages
+	case "copyMessage":
+		return MessageID{MessageID: d.nextID()}
+	case "copyMessages", "forwardMessages":
+		var ids []int
+		_ = json.Unmarshal([]byte(params["message_ids"]), &ids)
+
+		messageIDs := make([]MessageID, len(ids))
+		for i := range ids {
+			messageIDs[i] = MessageID{MessageID: d.nextID()}
+		}
+		return messageIDs
+	case "stopPoll":
+		return Poll{ID: strconv.Itoa(d.nextID()), IsClosed: true}
+	case "createChatInviteLink", "createChatSubscriptionInviteLink", "editChatInviteLink", "editChatSubscriptionInviteLink", "revokeChatInviteLink":
+		link := params["invite_link"]
+		if link == "" {
+			link = d.inviteLink()
+		}
+		return ChatInviteLink{
+			InviteLink: link,
+			Creator:    self,
+			Name:       params["name"],
+			IsRevoked:  method == "revokeChatInviteLink",
+		}
+	case "exportChatInviteLink":
+		return d.inviteLink()
+	case "createInvoiceLink":
+		return "https://t.me/$dry-run-" + strconv.Itoa(d.nextID())
+	case "createForumTopic":
+		return ForumTopic{MessageThreadID: d.nextID(), Name: params["name"]}
+	case "postStory", "editStory", "repostStory":
+		id, err := strconv.Atoi(params["story_id"])
+		if err != nil {
+			id = d.nextID()
+		}
+		return Story{Chat: dryRunChat(params["chat_id"]), ID: id}
+	case "uploadStickerFile":
+		id := d.nextFileID()
+		return File{FileID: id, FileUniqueID: id}
+	case "answerWebAppQuery", "answerGuestQuery":
+		return SentWebAppMessage{InlineMessageID: d.nextFileID()}
+	case "savePreparedInlineMessage":
+		return PreparedInlineMessage{ID: d.nextFileID(), ExpirationDate: time.Now().Add(24 * time.Hour).Unix()}
+	case "savePreparedKeyboardButton":
+		return PreparedKeyboardButton{ID: d.nextFileID()}
+	}
+
+	switch {
+	case strings.HasPrefix(method, "send") || method == "forwardMessage":
+		message := d.message(self, strings.ToLower(strings.TrimPrefix(method, "send")), params)
+		message.MessageID = d.nextID()
+		return message
+	case strings.HasPrefix(method, "editMessage") || method == "stopMessageLiveLocation" || method == "setGameScore":
+		if params["inline_message_id"] != "" {
+			return true
+		}
+		message := d.message(self, "", params)
+		message.MessageID, _ = strconv.Atoi(params["message_id"])
+		message.EditDate = message.Date
+		return message
+	}
+
+	return true
+}
+
+// message returns a message sent by a request with params. Media of a type
+// such as "photo" or "videonote" gets a synthetic file ID.
+func (d *dryRun) message(self User, media string, params Params) Message {
+	message := Message{
+		From:    &self,
+		Date:    time.Now().Unix(),
+		Chat:    dryRunChat(params["chat_id"]),
+		Text:    params["text"],
+		Caption: params["caption"],
+	}
+	message.MessageThreadID, _ = strconv.Atoi(params["message_thread_id"])
+
+	var markup InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(params["reply_markup"]), &markup); err == nil && len(markup.InlineKeyboard) > 0 {
+		message.ReplyMarkup = &markup
+	}
+
+	switch media {
+	case "photo":
+		id := d.nextFileID()
+		message.Photo = []PhotoSize{{FileID: id, FileUniqueID: id}}
+	case "document":
+		id := d.nextFileID()
+		message.Document = &Document{FileID: id, FileUniqueID: id}
+	case "audio":
+		id := d.nextFileID()
+		message.Audio = &Audio{FileID: id, FileUniqueID: id}
+	case "video":
+		id := d.nextFileID()
+		message.Video = &Video{FileID: id, FileUniqueID: id}
+	case "animation":
+		id := d.nextFileID()
+		message.Animation = &Animation{FileID: id, FileUniqueID: id}
+	case "voice":
+		id := d.nextFileID()
+		message.Voice = &Voice{FileID: id, FileUniqueID: id}
+	case "videonote":
+		id := d.nextFileID()
+		message.VideoNote = &VideoNote{FileID: id, FileUniqueID: id}
+	case "sticker":
+		id := d.nextFileID()
+		message.Sticker = &Sticker{FileID: id, FileUniqueID: id}
+	}
+
+	return message
+}
+
+func (d *dryRun) nextID() int {
+	d.lastID++
+	return d.lastID
+}
+
+func (d *dryRun) nextFileID() string {
+	d.lastFileID++
+	return "dry-run-" + strconv.Itoa(d.lastFileID)
+}
+
+func (d *dryRun) inviteLink() string {
+	return "https://t.me/+dry-run-" + strconv.Itoa(d.nextID())
+}
+
+// dryRunChat returns the chat of a chat_id parameter.
+func dryRunChat(chatID string) Chat {
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return Chat{UserName: strings.TrimPrefix(chatID, "@")}
+	}
+
+	chat := Chat{ID: id}
+	if id > 0 {
+		chat.Type = "private"
+	}
+	return chat
+}
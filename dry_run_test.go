@@ -0,0 +1,105 @@
+package tgbotapi
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWithDryRun(t *testing.T) {
+	var sent []string
+	client := fakeHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+		method := path.Base(req.URL.Path)
+		sent = append(sent, method)
+		if method == "getChat" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":{"id":7,"type":"private"}}`)),
+			}, nil
+		}
+		return okGetMeResponse(), nil
+	}}
+
+	var sink bytes.Buffer
+	bot, err := NewBotAPIWithOptions("token", WithHTTPClient(client), WithDryRun(&sink))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := NewMessage(7, "hello")
+	msg.ReplyMarkup = NewInlineKeyboardMarkup(NewInlineKeyboardRow(NewInlineKeyboardButtonData("OK", "ok")))
+	first, err := bot.Send(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.MessageID != 1 || first.Chat.ID != 7 || first.Text != "hello" || first.From.UserName != "test_bot" {
+		t.Fatalf("unexpected synthetic message %+v", first)
+	}
+	if first.ReplyMarkup == nil || first.ReplyMarkup.InlineKeyboard[0][0].Text != "OK" {
+		t.Fatalf("expected the reply markup in the synthetic message, got %+v", first.ReplyMarkup)
+	}
+
+	photo, err := bot.Send(NewPhoto(7, FileBytes{Name: "cat.jpg", Bytes: []byte("jpeg")}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if photo.MessageID != 2 || len(photo.Photo) != 1 || photo.Photo[0].FileID == "" {
+		t.Fatalf("unexpected synthetic photo message %+v", photo)
+	}
+
+	edited, err := bot.Send(NewEditMessageText(7, first.MessageID, "edited"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.MessageID != first.MessageID || edited.Text != "edited" {
+		t.Fatalf("unexpected synthetic edited message %+v", edited)
+	}
+
+	if _, err := bot.Request(NewDeleteMessage(7, first.MessageID)); err != nil {
+		t.Fatal(err)
+	}
+	chat, err := bot.GetChat(ChatInfoConfig{ChatConfig: ChatConfig{ChatID: 7}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chat.ID != 7 {
+		t.Fatalf("expected getChat to be sent, got %+v", chat)
+	}
+
+	if want := []string{"getMe", "getChat"}; !slices.Equal(sent, want) {
+		t.Fatalf("expected only %q to be sent, got %q", want, sent)
+	}
+
+	var records []DryRunRecord
+	scanner := bufio.NewScanner(&sink)
+	for scanner.Scan() {
+		var record DryRunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+
+	var methods []string
+	for _, record := range records {
+		methods = append(methods, record.Method)
+	}
+	if want := []string{"sendMessage", "sendPhoto", "editMessageText", "deleteMessage"}; !slices.Equal(methods, want) {
+		t.Fatalf("expected records of %q, got %q", want, methods)
+	}
+	if records[0].Params["text"] != "hello" {
+		t.Fatalf("expected the params to be recorded, got %v", records[0].Params)
+	}
+	if records[1].Files["photo"] != "cat.jpg" {
+		t.Fatalf("expected the uploaded file to be recorded, got %v", records[1].Files)
+	}
+	if string(records[3].Result) != "true" {
+		t.Fatalf("expected deleteMessage to return true, got %s", records[3].Result)
+	}
+}
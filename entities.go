@@ -0,0 +1,168 @@
+package tgbotapi
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+// Types of message entities.
+const (
+	EntityTypeMention              = "mention"
+	EntityTypeHashtag              = "hashtag"
+	EntityTypeCashtag              = "cashtag"
+	EntityTypeBotCommand           = "bot_command"
+	EntityTypeURL                  = "url"
+	EntityTypeEmail                = "email"
+	EntityTypePhoneNumber          = "phone_number"
+	EntityTypeBold                 = "bold"
+	EntityTypeItalic               = "italic"
+	EntityTypeUnderline            = "underline"
+	EntityTypeStrikethrough        = "strikethrough"
+	EntityTypeSpoiler              = "spoiler"
+	EntityTypeBlockquote           = "blockquote"
+	EntityTypeExpandableBlockquote = "expandable_blockquote"
+	EntityTypeCode                 = "code"
+	EntityTypePre                  = "pre"
+	EntityTypeTextLink             = "text_link"
+	EntityTypeTextMention          = "text_mention"
+	EntityTypeCustomEmoji          = "custom_emoji"
+	EntityTypeDateTime             = "date_time"
+)
+
+// EntityText is a MessageEntity together with the part of the text it covers.
+type EntityText struct {
+	MessageEntity
+	// Text is the part of the text covered by the entity.
+	Text string
+}
+
+// Value returns the value the entity refers to:
+//   - the username without "@" for mentions,
+//   - the hashtag without "#" for hashtags,
+//   - the currency without "$" for cashtags,
+//   - the command without "/" for bot commands, including a bot username,
+//   - the URL for URLs and text links,
+//   - the custom emoji identifier for custom emoji,
+//   - the covered text for every other entity.
+func (e EntityText) Value() string {
+	switch e.Type {
+	case EntityTypeMention:
+		return strings.TrimPrefix(e.Text, "@")
+	case EntityTypeHashtag:
+		return strings.TrimPrefix(e.Text, "#")
+	case EntityTypeCashtag:
+		return strings.TrimPrefix(e.Text, "$")
+	case EntityTypeBotCommand:
+		return strings.TrimPrefix(e.Text, "/")
+	case EntityTypeTextLink:
+		return e.URL
+	case EntityTypeCustomEmoji:
+		return e.CustomEmojiID
+	}
+
+	return e.Text
+}
+
+// ParseURL parses the URL of a "url" or "text_link" entity.
+//
+// URLs detected in text may lack a scheme, in which case http is assumed.
+func (e EntityText) ParseURL() (*url.URL, error) {
+	switch e.Type {
+	case EntityTypeTextLink:
+		return e.MessageEntity.ParseURL()
+	case EntityTypeURL:
+		link := e.Text
+		if !strings.Contains(link, "://") {
+			link = "http://" + link
+		}
+		return url.Parse(link)
+	}
+
+	return nil, errors.New(ErrBadURL)
+}
+
+// ParseEmail parses the address of an "email" entity.
+func (e EntityText) ParseEmail() (*mail.Address, error) {
+	if e.Type != EntityTypeEmail {
+		return nil, errors.New("entity is not an email address")
+	}
+
+	return mail.ParseAddress(e.Text)
+}
+
+// ParseEntities resolves entities against the text they belong to.
+//
+// Only entities of the given types are returned, or all of them if no types
+// are given. Entities outside of the text are skipped.
+func ParseEntities(text string, entities []MessageEntity, types ...string) []EntityText {
+	if len(entities) == 0 {
+		return nil
+	}
+
+	units := utf16.Encode([]rune(text))
+	result := make([]EntityText, 0, len(entities))
+
+	for _, entity := range entities {
+		if len(types) > 0 && !slices.Contains(types, entity.Type) {
+			continue
+		}
+
+		end := entity.Offset + entity.Length
+		if entity.Offset < 0 || entity.Length < 0 || end > len(units) {
+			continue
+		}
+
+		result = append(result, EntityText{
+			MessageEntity: entity,
+			Text:          string(utf16.Decode(units[entity.Offset:end])),
+		})
+	}
+
+	return result
+}
+
+// EntitiesOf returns the entities of the message text of the given types,
+// or all of them if no types are given.
+func (m *Message) EntitiesOf(types ...string) []EntityText {
+	return ParseEntities(m.Text, m.Entities, types...)
+}
+
+// CaptionEntitiesOf returns the entities of the message caption of the given
+// types, or all of them if no types are given.
+func (m *Message) CaptionEntitiesOf(types ...string) []EntityText {
+	return ParseEntities(m.Caption, m.CaptionEntities, types...)
+}
+
+// QuestionEntitiesOf returns the entities of the poll question of the given
+// types, or all of them if no types are given.
+func (p *Poll) QuestionEntitiesOf(types ...string) []EntityText {
+	return ParseEntities(p.Question, p.QuestionEntities, types...)
+}
+
+// ExplanationEntitiesOf returns the entities of the poll explanation of the
+// given types, or all of them if no types are given.
+func (p *Poll) ExplanationEntitiesOf(types ...string) []EntityText {
+	return ParseEntities(p.Explanation, p.ExplanationEntities, types...)
+}
+
+// EntitiesOf returns the entities of the quote of the given types, or all of
+// them if no types are given.
+func (q *TextQuote) EntitiesOf(types ...string) []EntityText {
+	return ParseEntities(q.Text, q.Entities, types...)
+}
+
+// TextEntitiesOf returns the entities of the task text of the given types,
+// or all of them if no types are given.
+func (t *ChecklistTask) TextEntitiesOf(types ...string) []EntityText {
+	return ParseEntities(t.Text, t.TextEntities, types...)
+}
+
+// TitleEntitiesOf returns the entities of the checklist title of the given
+// types, or all of them if no types are given.
+func (c *Checklist) TitleEntitiesOf(types ...string) []EntityText {
+	return ParseEntities(c.Title, c.TitleEntities, types...)
+}
@@ -0,0 +1,74 @@
+package tgbotapi
+
+import "testing"
+
+func TestMessageEntitiesOf(t *testing.T) {
+	message := Message{
+		Text: "😀 @user, see telegram.org or mail a@b.co $USD /start@bot",
+		Entities: []MessageEntity{
+			{Type: EntityTypeCustomEmoji, Offset: 0, Length: 2, CustomEmojiID: "42"},
+			{Type: EntityTypeMention, Offset: 3, Length: 5},
+			{Type: EntityTypeURL, Offset: 14, Length: 12},
+			{Type: EntityTypeEmail, Offset: 35, Length: 6},
+			{Type: EntityTypeCashtag, Offset: 42, Length: 4},
+			{Type: EntityTypeBotCommand, Offset: 47, Length: 10},
+			{Type: EntityTypeBold, Offset: 50, Length: 20},
+		},
+	}
+
+	entities := message.EntitiesOf()
+	if len(entities) != 6 {
+		t.Fatalf("expected entity outside of the text to be skipped: %+v", entities)
+	}
+
+	expected := []struct{ text, value string }{
+		{"😀", "42"},
+		{"@user", "user"},
+		{"telegram.org", "telegram.org"},
+		{"a@b.co", "a@b.co"},
+		{"$USD", "USD"},
+		{"/start@bot", "start@bot"},
+	}
+	for i, entity := range entities {
+		if entity.Text != expected[i].text || entity.Value() != expected[i].value {
+			t.Fatalf("entity %d: got %q (%q), expected %q (%q)", i, entity.Text, entity.Value(), expected[i].text, expected[i].value)
+		}
+	}
+
+	urls := message.EntitiesOf(EntityTypeURL, EntityTypeTextLink)
+	if len(urls) != 1 {
+		t.Fatalf("unexpected URL entities: %+v", urls)
+	}
+	link, err := urls[0].ParseURL()
+	if err != nil || link.String() != "http://telegram.org" {
+		t.Fatalf("unexpected URL %v: %v", link, err)
+	}
+
+	address, err := message.EntitiesOf(EntityTypeEmail)[0].ParseEmail()
+	if err != nil || address.Address != "a@b.co" {
+		t.Fatalf("unexpected email %v: %v", address, err)
+	}
+	if _, err := entities[1].ParseEmail(); err == nil {
+		t.Fatal("expected error parsing a mention as email")
+	}
+}
+
+func TestEntitiesOfOtherTexts(t *testing.T) {
+	poll := Poll{
+		Question:         "Ваш 👍?",
+		QuestionEntities: []MessageEntity{{Type: EntityTypeCustomEmoji, Offset: 4, Length: 2, CustomEmojiID: "1"}},
+	}
+	if entities := poll.QuestionEntitiesOf(EntityTypeCustomEmoji); len(entities) != 1 || entities[0].Text != "👍" {
+		t.Fatalf("unexpected poll entities: %+v", entities)
+	}
+
+	quote := TextQuote{Text: "a bold", Entities: []MessageEntity{{Type: EntityTypeBold, Offset: 2, Length: 4}}}
+	if entities := quote.EntitiesOf(EntityTypeItalic); len(entities) != 0 {
+		t.Fatalf("unexpected quote entities: %+v", entities)
+	}
+
+	task := ChecklistTask{Text: "ping @admin", TextEntities: []MessageEntity{{Type: EntityTypeMention, Offset: 5, Length: 6}}}
+	if entities := task.TextEntitiesOf(EntityTypeMention); len(entities) != 1 || entities[0].Value() != "admin" {
+		t.Fatalf("unexpected task entities: %+v", entities)
+	}
+}
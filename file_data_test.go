@@ -0,0 +1,100 @@
+package tgbotapi
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func multipartFileTestBot(t *testing.T, parts map[string]*multipart.Part, contents map[string]string) *BotAPI {
+	return newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			_, attrs, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+			if err != nil {
+				t.Fatalf("parse content type: %v", err)
+			}
+
+			reader := multipart.NewReader(req.Body, attrs["boundary"])
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("read multipart part: %v", err)
+				}
+				data, err := io.ReadAll(part)
+				if err != nil {
+					t.Fatalf("read multipart data: %v", err)
+				}
+				parts[part.FormName()] = part
+				contents[part.FormName()] = string(data)
+			}
+
+			return okAPIResponse(), nil
+		},
+	})
+}
+
+func TestFileFSUploadsEveryTime(t *testing.T) {
+	parts := map[string]*multipart.Part{}
+	contents := map[string]string{}
+	bot := multipartFileTestBot(t, parts, contents)
+
+	fsys := fstest.MapFS{"assets/logo.png": {Data: []byte("logo")}}
+	file := FileFS{FS: fsys, Path: "assets/logo.png", ContentType: "image/png"}
+
+	if size, ok := uploadSize(file); !ok || size != 4 {
+		t.Fatalf("expected a known size of 4, got %d", size)
+	}
+
+	for i := 0; i < 2; i++ {
+		clear(contents)
+		if _, err := bot.UploadFiles("sendDocument", Params{"chat_id": "1"}, []RequestFile{{Name: "document", Data: file}}); err != nil {
+			t.Fatal(err)
+		}
+		if contents["document"] != "logo" {
+			t.Fatalf("upload %d: unexpected content %q", i, contents["document"])
+		}
+	}
+
+	part := parts["document"]
+	if part.FileName() != "logo.png" || part.Header.Get("Content-Type") != "image/png" {
+		t.Fatalf("unexpected part headers: %v", part.Header)
+	}
+}
+
+func TestFileOpenerUploadsEveryTime(t *testing.T) {
+	parts := map[string]*multipart.Part{}
+	contents := map[string]string{}
+	bot := multipartFileTestBot(t, parts, contents)
+
+	opened := 0
+	file := FileOpener{
+		Name: "report.csv",
+		Open: func() (io.ReadCloser, error) {
+			opened++
+			return io.NopCloser(strings.NewReader("a,b")), nil
+		},
+	}
+
+	if _, ok := uploadSize(file); ok {
+		t.Fatal("expected an unknown size")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := bot.UploadFiles("sendDocument", Params{"chat_id": "1"}, []RequestFile{{Name: "document", Data: file}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if opened != 2 || contents["document"] != "a,b" {
+		t.Fatalf("expected 2 opens, got %d with content %q", opened, contents["document"])
+	}
+	if contentType := parts["document"].Header.Get("Content-Type"); contentType != "application/octet-stream" {
+		t.Fatalf("expected the default content type, got %q", contentType)
+	}
+}
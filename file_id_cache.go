@@ -0,0 +1,401 @@
+package tgbotapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"sync"
+)
+
+// FileIDStore persists the file IDs of uploaded content for a FileIDCache.
+//
+// Keys identify the method and the content of an upload.
+type FileIDStore interface {
+	// Get returns the file ID stored for key, if any.
+	Get(ctx context.Context, key string) (string, bool, error)
+	// Set stores the file ID for key.
+	Set(ctx context.Context, key, fileID string) error
+	// Delete removes the file ID stored for key.
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryFileIDStore is a FileIDStore keeping file IDs in memory.
+type MemoryFileIDStore struct {
+	mu      sync.RWMutex
+	fileIDs map[string]string
+}
+
+// NewMemoryFileIDStore creates a new MemoryFileIDStore.
+func NewMemoryFileIDStore() *MemoryFileIDStore {
+	return &MemoryFileIDStore{
+		fileIDs: make(map[string]string),
+	}
+}
+
+// Get returns the file ID stored for key, if any.
+func (s *MemoryFileIDStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fileID, ok := s.fileIDs[key]
+	return fileID, ok, nil
+}
+
+// Set stores the file ID for key.
+func (s *MemoryFileIDStore) Set(_ context.Context, key, fileID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fileIDs[key] = fileID
+	return nil
+}
+
+// Delete removes the file ID stored for key.
+func (s *MemoryFileIDStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.fileIDs, key)
+	return nil
+}
+
+// FileIDCache sends media like BotAPI.Send, but uploads each distinct file
+// content only once.
+//
+// After a successful upload, the file ID of the sent media is stored under
+// a hash of the content, along with the content of its thumbnail and cover.
+// Later sends of the same content with the same method use the file ID
+// instead. If Telegram rejects a stored file ID, it is removed and the
+// content is uploaded again.
+//
+// Photos, audio, documents, stickers, videos, animations, video notes and
+// voice messages are cached; other Chattables are sent unchanged. Content of
+// a FileReader and thumbnails and covers are read into memory to hash them.
+// Other files are hashed again while they are uploaded, so the file ID is
+// stored under the content that was sent even if the file changed.
+type FileIDCache struct {
+	Bot   *BotAPI
+	Store FileIDStore
+}
+
+// NewFileIDCache creates a new FileIDCache. A MemoryFileIDStore is used if
+// store is nil.
+func NewFileIDCache(bot *BotAPI, store FileIDStore) *FileIDCache {
+	if store == nil {
+		store = NewMemoryFileIDStore()
+	}
+
+	return &FileIDCache{
+		Bot:   bot,
+		Store: store,
+	}
+}
+
+// Send sends a Chattable, reusing the file ID of previously uploaded content.
+func (c *FileIDCache) Send(config Chattable) (Message, error) {
+	return c.SendWithContext(context.Background(), config)
+}
+
+// SendWithContext sends a Chattable, reusing the file ID of previously
+// uploaded content.
+//
+// If the message was sent but its file ID could not be stored, the message
+// is returned together with the error.
+func (c *FileIDCache) SendWithContext(ctx context.Context, config Chattable) (Message, error) {
+	upload, ok := newCachedUpload(config)
+	if !ok || *upload.file == nil || !(*upload.file).NeedsUpload() {
+		return c.Bot.SendWithContext(ctx, config)
+	}
+
+	file, digest, err := hashUpload(*upload.file)
+	if err != nil {
+		return Message{}, err
+	}
+	attachments, err := upload.hashAttachments()
+	if err != nil {
+		return Message{}, err
+	}
+	key := config.method() + ":" + digest + attachments
+
+	fileID, ok, err := c.Store.Get(ctx, key)
+	if err != nil {
+		return Message{}, fmt.Errorf("get cached file ID: %w", err)
+	}
+	if ok {
+		*upload.file = FileID(fileID)
+		restore := upload.dropThumbnails()
+		message, err := c.Bot.SendWithContext(ctx, upload.chattable())
+		if !isStaleFileIDError(err) {
+			return message, err
+		}
+		restore()
+		if err := c.Store.Delete(ctx, key); err != nil {
+			return Message{}, fmt.Errorf("delete stale file ID: %w", err)
+		}
+	}
+
+	// Hash the content again while it is uploaded, unless it is already in
+	// memory or a local server reads it from disk.
+	var uploaded hash.Hash
+	*upload.file = file
+	if _, inMemory := file.(FileBytes); !inMemory && !(c.Bot.localMode && isFilePath(file)) {
+		uploaded = sha256.New()
+		*upload.file = hashingUpload(file, uploaded)
+	}
+
+	message, err := c.Bot.SendWithContext(ctx, upload.chattable())
+	if err != nil {
+		return message, err
+	}
+	if uploaded != nil {
+		key = config.method() + ":" + hex.EncodeToString(uploaded.Sum(nil)) + attachments
+	}
+
+	if fileID := messageFileID(message); fileID != "" {
+		if err := c.Store.Set(ctx, key, fileID); err != nil {
+			return message, fmt.Errorf("cache file ID: %w", err)
+		}
+	}
+
+	return message, nil
+}
+
+// cachedUpload is a copy of a config whose file ID can be cached. Its fields
+// point into the copy.
+type cachedUpload struct {
+	file *RequestFileData
+	// attachments are the thumbnail and cover of the media.
+	attachments []cachedAttachment
+	chattable   func() Chattable
+}
+
+type cachedAttachment struct {
+	name string
+	data *RequestFileData
+}
+
+func newCachedUpload(config Chattable) (cachedUpload, bool) {
+	var upload cachedUpload
+	switch config := config.(type) {
+	case PhotoConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+		upload.thumbnail(&config.Thumb)
+	case AudioConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+		upload.thumbnail(&config.Thumb)
+	case DocumentConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+		upload.thumbnail(&config.Thumb)
+	case StickerConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+	case VideoConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+		upload.thumbnail(&config.Thumb)
+		upload.attachment("cover", &config.Cover)
+	case AnimationConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+		upload.thumbnail(&config.Thumb)
+	case VideoNoteConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+		upload.thumbnail(&config.Thumb)
+	case VoiceConfig:
+		upload = cachedUpload{file: &config.File, chattable: func() Chattable { return config }}
+		upload.thumbnail(&config.Thumb)
+	default:
+		return cachedUpload{}, false
+	}
+
+	return upload, true
+}
+
+func (u *cachedUpload) thumbnail(data *RequestFileData) {
+	u.attachment("thumbnail", data)
+}
+
+func (u *cachedUpload) attachment(name string, data *RequestFileData) {
+	if *data != nil {
+		u.attachments = append(u.attachments, cachedAttachment{name: name, data: data})
+	}
+}
+
+// dropThumbnails clears the thumbnails, which Telegram ignores for media sent
+// by file ID, and returns a function restoring them. A cover is kept, as it
+// still applies to a video sent by file ID.
+func (u *cachedUpload) dropThumbnails() (restore func()) {
+	var dropped []cachedAttachment
+	var data []RequestFileData
+	for _, attachment := range u.attachments {
+		if attachment.name == "thumbnail" {
+			dropped = append(dropped, attachment)
+			data = append(data, *attachment.data)
+			*attachment.data = nil
+		}
+	}
+
+	return func() {
+		for i, attachment := range dropped {
+			*attachment.data = data[i]
+		}
+	}
+}
+
+// hashAttachments reads the attachments into memory and returns the part of
+// the cache key identifying them.
+func (u *cachedUpload) hashAttachments() (string, error) {
+	var key strings.Builder
+	for _, attachment := range u.attachments {
+		data := *attachment.data
+		if !data.NeedsUpload() {
+			key.WriteString("+" + attachment.name + "=" + data.SendData())
+			continue
+		}
+
+		file, err := readUpload(data)
+		if err != nil {
+			return "", fmt.Errorf("read upload %q: %w", attachment.name, err)
+		}
+		*attachment.data = file
+
+		sum := sha256.Sum256(file.Bytes)
+		key.WriteString("+" + attachment.name + ":" + hex.EncodeToString(sum[:]))
+	}
+
+	return key.String(), nil
+}
+
+// hashUpload returns the SHA-256 digest of the content of file. A FileReader
+// is read into memory and returned as FileBytes, so it can still be uploaded.
+func hashUpload(file RequestFileData) (RequestFileData, string, error) {
+	hash := sha256.New()
+
+	switch data := file.(type) {
+	case FileBytes:
+		hash.Write(data.Bytes)
+	case FileReader:
+		content, err := readUpload(data)
+		if err != nil {
+			return file, "", err
+		}
+		hash.Write(content.Bytes)
+		file = content
+	default:
+		_, reader, err := file.UploadData()
+		if err != nil {
+			return file, "", err
+		}
+		_, err = io.Copy(hash, reader)
+		if closeErr := closeUploadReader(reader); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return file, "", err
+		}
+	}
+
+	return file, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readUpload reads the content of file into memory.
+func readUpload(file RequestFileData) (FileBytes, error) {
+	if data, ok := file.(FileBytes); ok {
+		return data, nil
+	}
+
+	name, reader, err := file.UploadData()
+	if err != nil {
+		return FileBytes{}, err
+	}
+	content, err := io.ReadAll(reader)
+	if closeErr := closeUploadReader(reader); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return FileBytes{}, err
+	}
+
+	return FileBytes{Name: name, Bytes: content}, nil
+}
+
+// hashingUpload returns file as a FileOpener writing the content to hash
+// while it is read.
+func hashingUpload(file RequestFileData, hash hash.Hash) FileOpener {
+	name, _ := uploadName(file)
+	size, _ := uploadSize(file)
+
+	return FileOpener{
+		Name: name,
+		Open: func() (io.ReadCloser, error) {
+			_, reader, err := file.UploadData()
+			if err != nil {
+				return nil, err
+			}
+
+			hash.Reset()
+			return hashingReader{Reader: io.TeeReader(reader, hash), source: reader}, nil
+		},
+		Size:        size,
+		ContentType: uploadContentType(file),
+	}
+}
+
+type hashingReader struct {
+	io.Reader
+	source io.Reader
+}
+
+func (r hashingReader) Close() error {
+	return closeUploadReader(r.source)
+}
+
+func isFilePath(file RequestFileData) bool {
+	_, ok := file.(FilePath)
+	return ok
+}
+
+// messageFileID returns the file ID of the media of a message.
+func messageFileID(message Message) string {
+	switch {
+	case len(message.Photo) > 0:
+		largest := message.Photo[0]
+		for _, photo := range message.Photo[1:] {
+			if photo.Width*photo.Height > largest.Width*largest.Height {
+				largest = photo
+			}
+		}
+		return largest.FileID
+	case message.Animation != nil:
+		return message.Animation.FileID
+	case message.Video != nil:
+		return message.Video.FileID
+	case message.Audio != nil:
+		return message.Audio.FileID
+	case message.Voice != nil:
+		return message.Voice.FileID
+	case message.VideoNote != nil:
+		return message.VideoNote.FileID
+	case message.Sticker != nil:
+		return message.Sticker.FileID
+	case message.Document != nil:
+		return message.Document.FileID
+	}
+
+	return ""
+}
+
+// isStaleFileIDError returns true if err is Telegram rejecting a file ID.
+func isStaleFileIDError(err error) bool {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 {
+		return false
+	}
+
+	description := strings.ToLower(apiErr.Message)
+	return strings.Contains(description, "file identifier") ||
+		strings.Contains(description, "file reference") ||
+		strings.Contains(description, "file_id")
+}
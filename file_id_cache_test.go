@@ -0,0 +1,155 @@
+package tgbotapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func fileIDCacheTestBot(t *testing.T, uploads, reuses *int) *BotAPI {
+	return newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			response := `{"ok":true,"result":{"message_id":1,"date":1,"chat":{"id":1,"type":"private"},"photo":[{"file_id":"small","width":90,"height":90},{"file_id":"large","width":800,"height":600}]}}`
+
+			if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+				*uploads++
+			} else {
+				if err := req.ParseForm(); err != nil {
+					t.Fatalf("parse form: %v", err)
+				}
+				*reuses++
+				if req.PostForm.Get("photo") != "large" {
+					response = `{"ok":false,"error_code":400,"description":"Bad Request: wrong file identifier/HTTP URL specified"}`
+				}
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(response)),
+			}, nil
+		},
+	})
+}
+
+func TestFileIDCacheReusesUploadedContent(t *testing.T) {
+	var uploads, reuses int
+	cache := NewFileIDCache(fileIDCacheTestBot(t, &uploads, &reuses), nil)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Send(NewPhoto(1, FileBytes{Name: "logo.png", Bytes: []byte("logo")})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cache.Send(NewPhoto(1, FileReader{Name: "logo.png", Reader: strings.NewReader("logo")})); err != nil {
+		t.Fatal(err)
+	}
+	if uploads != 1 || reuses != 3 {
+		t.Fatalf("expected 1 upload and 3 reuses, got %d and %d", uploads, reuses)
+	}
+
+	if _, err := cache.Send(NewDocument(1, FileBytes{Name: "logo.png", Bytes: []byte("logo")})); err != nil {
+		t.Fatal(err)
+	}
+	if uploads != 2 {
+		t.Fatalf("expected the same content sent with another method to be uploaded, got %d uploads", uploads)
+	}
+}
+
+func TestFileIDCacheInvalidatesStaleFileIDs(t *testing.T) {
+	var uploads, reuses int
+	store := NewMemoryFileIDStore()
+	cache := NewFileIDCache(fileIDCacheTestBot(t, &uploads, &reuses), store)
+
+	file := FileBytes{Name: "logo.png", Bytes: []byte("logo")}
+	if _, err := cache.Send(NewPhoto(1, file)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, digest, _ := hashUpload(file)
+	key := "sendPhoto:" + digest
+	if err := store.Set(context.Background(), key, "expired"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := cache.Send(NewPhoto(1, file)); err != nil {
+		t.Fatal(err)
+	}
+	if uploads != 2 || reuses != 1 {
+		t.Fatalf("expected the stale file ID to cause a new upload, got %d uploads and %d reuses", uploads, reuses)
+	}
+	if fileID, _, _ := store.Get(context.Background(), key); fileID != "large" {
+		t.Fatalf("expected the new file ID to be stored, got %q", fileID)
+	}
+}
+
+// photoUploads returns a bot counting the sends uploading a photo and the
+// sends of a photo by file ID.
+func photoUploads(t *testing.T, uploads, reuses *int) *BotAPI {
+	return newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			if err := req.ParseMultipartForm(1 << 20); err != nil && err != http.ErrNotMultipart {
+				t.Fatalf("parse form: %v", err)
+			}
+			switch {
+			case req.MultipartForm != nil && req.MultipartForm.File["photo"] != nil:
+				*uploads++
+			case req.MultipartForm != nil:
+				t.Errorf("expected a cached file ID to be sent without multipart, got files %v", req.MultipartForm.File)
+			default:
+				*reuses++
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":{"message_id":1,"date":1,"chat":{"id":1,"type":"private"},"photo":[{"file_id":"large","width":800,"height":600}]}}`)),
+			}, nil
+		},
+	})
+}
+
+func TestFileIDCacheKeysThumbnails(t *testing.T) {
+	var uploads, reuses int
+	cache := NewFileIDCache(photoUploads(t, &uploads, &reuses), nil)
+
+	send := func(thumb string) {
+		photo := NewPhoto(1, FileBytes{Name: "logo.png", Bytes: []byte("logo")})
+		photo.Thumb = FileReader{Name: "thumb.jpg", Reader: strings.NewReader(thumb)}
+		if _, err := cache.Send(photo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send("first")
+	send("first")
+	send("second")
+
+	if uploads != 2 || reuses != 1 {
+		t.Fatalf("expected another thumbnail to cause an upload, got %d uploads and %d reuses", uploads, reuses)
+	}
+}
+
+func TestFileIDCacheKeysUploadedContent(t *testing.T) {
+	var uploads, reuses int
+	cache := NewFileIDCache(photoUploads(t, &uploads, &reuses), nil)
+
+	// The content changes after it has been hashed to look up the cache.
+	opened := 0
+	changing := FileOpener{Name: "logo.png", Open: func() (io.ReadCloser, error) {
+		opened++
+		return io.NopCloser(strings.NewReader("version " + strconv.Itoa(opened))), nil
+	}}
+	if _, err := cache.Send(NewPhoto(1, changing)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, content := range []string{"version 2", "version 1"} {
+		if _, err := cache.Send(NewPhoto(1, FileBytes{Name: "logo.png", Bytes: []byte(content)})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if opened != 2 || uploads != 2 || reuses != 1 {
+		t.Fatalf("expected only the uploaded content to be cached, got %d opens, %d uploads and %d reuses", opened, uploads, reuses)
+	}
+}
@@ -72,6 +72,354 @@ func NewSendRichMessageDraft(chatID int64, draftID int, richMessage InputRichMes
 	}
 }
 
+// NewRichText joins several rich text parts into a single rich text value.
+func NewRichText(parts ...RichText) RichText {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return append([]RichText(nil), parts...)
+}
+
+// NewRichTextBold creates bold text.
+func NewRichTextBold(text ...RichText) RichTextBold {
+	return RichTextBold{
+		Type: RichTextTypeBold,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextItalic creates italic text.
+func NewRichTextItalic(text ...RichText) RichTextItalic {
+	return RichTextItalic{
+		Type: RichTextTypeItalic,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextUnderline creates underlined text.
+func NewRichTextUnderline(text ...RichText) RichTextUnderline {
+	return RichTextUnderline{
+		Type: RichTextTypeUnderline,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextStrikethrough creates strikethrough text.
+func NewRichTextStrikethrough(text ...RichText) RichTextStrikethrough {
+	return RichTextStrikethrough{
+		Type: RichTextTypeStrikethrough,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextSpoiler creates spoiler text.
+func NewRichTextSpoiler(text ...RichText) RichTextSpoiler {
+	return RichTextSpoiler{
+		Type: RichTextTypeSpoiler,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextSubscript creates subscript text.
+func NewRichTextSubscript(text ...RichText) RichTextSubscript {
+	return RichTextSubscript{
+		Type: RichTextTypeSubscript,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextSuperscript creates superscript text.
+func NewRichTextSuperscript(text ...RichText) RichTextSuperscript {
+	return RichTextSuperscript{
+		Type: RichTextTypeSuperscript,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextMarked creates marked text.
+func NewRichTextMarked(text ...RichText) RichTextMarked {
+	return RichTextMarked{
+		Type: RichTextTypeMarked,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextCode creates monowidth text.
+func NewRichTextCode(text ...RichText) RichTextCode {
+	return RichTextCode{
+		Type: RichTextTypeCode,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichTextURL creates text with a link.
+func NewRichTextURL(link string, text ...RichText) RichTextUrl {
+	return RichTextUrl{
+		Type: RichTextTypeURL,
+		Text: NewRichText(text...),
+		URL:  link,
+	}
+}
+
+// NewRichTextEmailAddress creates text with an email address.
+func NewRichTextEmailAddress(emailAddress string, text ...RichText) RichTextEmailAddress {
+	return RichTextEmailAddress{
+		Type:         RichTextTypeEmailAddress,
+		Text:         NewRichText(text...),
+		EmailAddress: emailAddress,
+	}
+}
+
+// NewRichTextPhoneNumber creates text with a phone number.
+func NewRichTextPhoneNumber(phoneNumber string, text ...RichText) RichTextPhoneNumber {
+	return RichTextPhoneNumber{
+		Type:        RichTextTypePhoneNumber,
+		Text:        NewRichText(text...),
+		PhoneNumber: phoneNumber,
+	}
+}
+
+// NewRichTextMention creates text with a username mention.
+func NewRichTextMention(username string, text ...RichText) RichTextMention {
+	return RichTextMention{
+		Type:     RichTextTypeMention,
+		Text:     NewRichText(text...),
+		Username: username,
+	}
+}
+
+// NewRichTextHashtag creates text with a hashtag.
+func NewRichTextHashtag(hashtag string, text ...RichText) RichTextHashtag {
+	return RichTextHashtag{
+		Type:    RichTextTypeHashtag,
+		Text:    NewRichText(text...),
+		Hashtag: hashtag,
+	}
+}
+
+// NewRichTextCashtag creates text with a cashtag.
+func NewRichTextCashtag(cashtag string, text ...RichText) RichTextCashtag {
+	return RichTextCashtag{
+		Type:    RichTextTypeCashtag,
+		Text:    NewRichText(text...),
+		Cashtag: cashtag,
+	}
+}
+
+// NewRichTextBotCommand creates text with a bot command.
+func NewRichTextBotCommand(command string, text ...RichText) RichTextBotCommand {
+	return RichTextBotCommand{
+		Type:       RichTextTypeBotCommand,
+		Text:       NewRichText(text...),
+		BotCommand: command,
+	}
+}
+
+// NewRichTextAnchorLink creates text linking to an anchor.
+func NewRichTextAnchorLink(anchorName string, text ...RichText) RichTextAnchorLink {
+	return RichTextAnchorLink{
+		Type:       RichTextTypeAnchorLink,
+		Text:       NewRichText(text...),
+		AnchorName: anchorName,
+	}
+}
+
+// NewRichTextReference creates text with a reference name.
+func NewRichTextReference(name string, text ...RichText) RichTextReference {
+	return RichTextReference{
+		Type: RichTextTypeReference,
+		Text: NewRichText(text...),
+		Name: name,
+	}
+}
+
+// NewRichTextReferenceLink creates text linking to a reference.
+func NewRichTextReferenceLink(referenceName string, text ...RichText) RichTextReferenceLink {
+	return RichTextReferenceLink{
+		Type:          RichTextTypeReferenceLink,
+		Text:          NewRichText(text...),
+		ReferenceName: referenceName,
+	}
+}
+
+// NewRichTextTextMention creates a mention of a user without a username.
+func NewRichTextTextMention(user User, text ...RichText) RichTextTextMention {
+	return RichTextTextMention{
+		Type: RichTextTypeTextMention,
+		Text: NewRichText(text...),
+		User: user,
+	}
+}
+
+// NewRichTextDateTime creates formatted date and time text.
+func NewRichTextDateTime(unixTime int64, format string, text ...RichText) RichTextDateTime {
+	return RichTextDateTime{
+		Type:           RichTextTypeDateTime,
+		Text:           NewRichText(text...),
+		UnixTime:       unixTime,
+		DateTimeFormat: format,
+	}
+}
+
+// NewRichTextCustomEmoji creates a custom emoji with its alternative text.
+func NewRichTextCustomEmoji(customEmojiID, alternativeText string) RichTextCustomEmoji {
+	return RichTextCustomEmoji{
+		Type:            RichTextTypeCustomEmoji,
+		CustomEmojiID:   customEmojiID,
+		AlternativeText: alternativeText,
+	}
+}
+
+// NewRichTextMathematicalExpression creates an inline mathematical expression.
+func NewRichTextMathematicalExpression(expression string) RichTextMathematicalExpression {
+	return RichTextMathematicalExpression{
+		Type:       RichTextTypeMathematicalExpression,
+		Expression: expression,
+	}
+}
+
+// NewRichTextAnchor creates an anchor that can be linked with NewRichTextAnchorLink.
+func NewRichTextAnchor(name string) RichTextAnchor {
+	return RichTextAnchor{
+		Type: RichTextTypeAnchor,
+		Name: name,
+	}
+}
+
+// NewRichBlockParagraph creates a paragraph block.
+func NewRichBlockParagraph(text ...RichText) RichBlockParagraph {
+	return RichBlockParagraph{
+		Type: RichBlockTypeParagraph,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichBlockSectionHeading creates a heading block of the given size.
+func NewRichBlockSectionHeading(size int, text ...RichText) RichBlockSectionHeading {
+	return RichBlockSectionHeading{
+		Type: RichBlockTypeSectionHeading,
+		Text: NewRichText(text...),
+		Size: size,
+	}
+}
+
+// NewRichBlockPreformatted creates a preformatted block, language may be empty.
+func NewRichBlockPreformatted(language string, text ...RichText) RichBlockPreformatted {
+	return RichBlockPreformatted{
+		Type:     RichBlockTypePreformatted,
+		Text:     NewRichText(text...),
+		Language: language,
+	}
+}
+
+// NewRichBlockFooter creates a footer block.
+func NewRichBlockFooter(text ...RichText) RichBlockFooter {
+	return RichBlockFooter{
+		Type: RichBlockTypeFooter,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichBlockDivider creates a divider block.
+func NewRichBlockDivider() RichBlockDivider {
+	return RichBlockDivider{
+		Type: RichBlockTypeDivider,
+	}
+}
+
+// NewRichBlockMathematicalExpression creates a block mathematical expression.
+func NewRichBlockMathematicalExpression(expression string) RichBlockMathematicalExpression {
+	return RichBlockMathematicalExpression{
+		Type:       RichBlockTypeMathematicalExpression,
+		Expression: expression,
+	}
+}
+
+// NewRichBlockAnchor creates an anchor block.
+func NewRichBlockAnchor(name string) RichBlockAnchor {
+	return RichBlockAnchor{
+		Type: RichBlockTypeAnchor,
+		Name: name,
+	}
+}
+
+// NewRichBlockList creates a list block.
+func NewRichBlockList(items ...RichBlockListItem) RichBlockList {
+	return RichBlockList{
+		Type:  RichBlockTypeList,
+		Items: items,
+	}
+}
+
+// NewRichBlockListItem creates a list item containing the given blocks.
+func NewRichBlockListItem(blocks ...RichBlock) RichBlockListItem {
+	return RichBlockListItem{
+		Blocks: blocks,
+	}
+}
+
+// NewRichBlockBlockQuotation creates a block quotation.
+func NewRichBlockBlockQuotation(blocks ...RichBlock) RichBlockBlockQuotation {
+	return RichBlockBlockQuotation{
+		Type:   RichBlockTypeBlockQuotation,
+		Blocks: blocks,
+	}
+}
+
+// NewRichBlockPullQuotation creates a pull quotation.
+func NewRichBlockPullQuotation(text ...RichText) RichBlockPullQuotation {
+	return RichBlockPullQuotation{
+		Type: RichBlockTypePullQuotation,
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichBlockTable creates a table block from rows of cells.
+func NewRichBlockTable(rows ...[]RichBlockTableCell) RichBlockTable {
+	return RichBlockTable{
+		Type:  RichBlockTypeTable,
+		Cells: rows,
+	}
+}
+
+// NewRichBlockTableRow creates a row of table cells.
+func NewRichBlockTableRow(cells ...RichBlockTableCell) []RichBlockTableCell {
+	return cells
+}
+
+// NewRichBlockTableCell creates a table cell.
+func NewRichBlockTableCell(text ...RichText) RichBlockTableCell {
+	return RichBlockTableCell{
+		Text: NewRichText(text...),
+	}
+}
+
+// NewRichBlockTableHeaderCell creates a table header cell.
+func NewRichBlockTableHeaderCell(text ...RichText) RichBlockTableCell {
+	return RichBlockTableCell{
+		Text:     NewRichText(text...),
+		IsHeader: true,
+	}
+}
+
+// NewRichBlockDetails creates an expandable details block.
+func NewRichBlockDetails(summary RichText, blocks ...RichBlock) RichBlockDetails {
+	return RichBlockDetails{
+		Type:    RichBlockTypeDetails,
+		Summary: summary,
+		Blocks:  blocks,
+	}
+}
+
+// NewRichBlockThinking creates a thinking placeholder block.
+func NewRichBlockThinking(text ...RichText) RichBlockThinking {
+	return RichBlockThinking{
+		Type: RichBlockTypeThinking,
+		Text: NewRichText(text...),
+	}
+}
+
 // NewDeleteMessage creates a request to delete a message.
 func NewDeleteMessage(chatID int64, messageID int) DeleteMessageConfig {
 	return DeleteMessageConfig{
@@ -0,0 +1,156 @@
+// Command mockgen generates the methods of the MockBot of tgbotapitest from
+// the Bot interface.
+//
+// Every method taking a config records it and decodes the result set for
+// its Bot API method. Methods without a config are listed in noConfig.
+// Types of the library are qualified with the name of its package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// noConfig are the requests made by methods not taking a config.
+var noConfig = map[string]string{
+	"GetMe":                     `tgbotapi.NewRawRequest("getMe", nil)`,
+	"GetMeWithContext":          `tgbotapi.NewRawRequest("getMe", nil)`,
+	"GetWebhookInfo":            `tgbotapi.NewRawRequest("getWebhookInfo", nil)`,
+	"GetWebhookInfoWithContext": `tgbotapi.NewRawRequest("getWebhookInfo", nil)`,
+	"GetMyCommands":             `tgbotapi.GetMyCommandsConfig{}`,
+}
+
+// importPath is the import path of the package declaring the Bot interface.
+const importPath = "github.com/OvyFlash/telegram-bot-api"
+
+func main() {
+	in := flag.String("in", "bot_interface.go", "file declaring the Bot interface")
+	out := flag.String("out", "tgbotapitest/mock_bot.go", "output file")
+	pkg := flag.String("pkg", "tgbotapitest", "package of the output file")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *in, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	iface := findInterface(file, "Bot")
+	if iface == nil {
+		log.Fatalf("%s: no Bot interface", *in)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by mockgen. DO NOT EDIT.\n\n")
+	buf.WriteString("package " + *pkg + "\n\n")
+	fmt.Fprintf(&buf, "import (\n\t\"context\"\n\n\t%s %q\n)\n\n", file.Name.Name, importPath)
+	fmt.Fprintf(&buf, "var _ %s.Bot = (*MockBot)(nil)\n", file.Name.Name)
+
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			continue
+		}
+		qualify(fn, file.Name.Name)
+		if err := writeMethod(&buf, fset, field.Names[0].Name, fn); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("format generated code: %v\n%s", err, buf.Bytes())
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func findInterface(file *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok && typeSpec.Name.Name == name {
+				return iface
+			}
+		}
+	}
+	return nil
+}
+
+func writeMethod(buf *bytes.Buffer, fset *token.FileSet, name string, fn *ast.FuncType) error {
+	ctx := "context.Background()"
+	request, hasRequest := noConfig[name]
+
+	var params []string
+	for _, field := range fn.Params.List {
+		typ := source(fset, field.Type)
+		for _, ident := range field.Names {
+			params = append(params, ident.Name+" "+typ)
+			switch {
+			case typ == "context.Context":
+				ctx = ident.Name
+			case !hasRequest:
+				request, hasRequest = ident.Name, true
+			}
+		}
+	}
+	if !hasRequest {
+		return fmt.Errorf("%s: no config param, add it to noConfig", name)
+	}
+
+	if fn.Results == nil || len(fn.Results.List) != 2 {
+		return fmt.Errorf("%s: expected a result and an error", name)
+	}
+	result := source(fset, fn.Results.List[0].Type)
+
+	fmt.Fprintf(buf, "\n// %s records the request.\n", name)
+	fmt.Fprintf(buf, "func (m *MockBot) %s(%s) (%s, error) {\n", name, strings.Join(params, ", "), result)
+	if strings.HasSuffix(result, ".APIResponse") {
+		fmt.Fprintf(buf, "\treturn m.call(%s, %q, %s)\n}\n", ctx, name, request)
+		return nil
+	}
+	fmt.Fprintf(buf, "\tvar result %s\n", result)
+	fmt.Fprintf(buf, "\terr := m.do(%s, %q, %s, &result)\n", ctx, name, request)
+	fmt.Fprintf(buf, "\treturn result, err\n}\n")
+
+	return nil
+}
+
+// qualify prefixes the exported types of the library in the signature of a
+// method with the name of its package.
+func qualify(fn *ast.FuncType, pkg string) {
+	ast.Inspect(fn, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			// Already qualified, such as context.Context.
+			return false
+		case *ast.Ident:
+			if node.Name != "" && unicode.IsUpper(rune(node.Name[0])) {
+				node.Name = pkg + "." + node.Name
+			}
+		}
+		return true
+	})
+}
+
+func source(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		log.Fatal(err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,180 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MaxUploadFileSize is the largest file bots can upload to the cloud Bot API server.
+	MaxUploadFileSize = 50 << 20
+	// MaxLocalUploadFileSize is the largest file bots can upload to a local Bot API server.
+	MaxLocalUploadFileSize = 2000 << 20
+)
+
+// WithLocalServer configures the bot to use a Bot API server running with
+// --local at serverURL, for example "http://localhost:8081".
+//
+// In local mode, FilePath uploads are sent as file:// references the server
+// reads from disk, downloaded files are read straight from the path returned
+// by getFile and the larger limits of a local server apply.
+func WithLocalServer(serverURL string) BotAPIOption {
+	return func(config *botAPIConfig) error {
+		serverURL = strings.TrimSuffix(serverURL, "/")
+		if serverURL == "" {
+			return fmt.Errorf("invalid local server URL %q", serverURL)
+		}
+
+		config.apiEndpoint = serverURL + "/bot%s/%s"
+		config.fileEndpoint = serverURL + "/file/bot%s/%s"
+		config.localMode = true
+		return nil
+	}
+}
+
+// IsLocalMode returns true if the bot uses a local Bot API server, see WithLocalServer.
+func (bot *BotAPI) IsLocalMode() bool {
+	return bot.localMode
+}
+
+// MaxUploadSize returns the largest file the bot can upload.
+func (bot *BotAPI) MaxUploadSize() int64 {
+	if bot.localMode {
+		return MaxLocalUploadFileSize
+	}
+
+	return MaxUploadFileSize
+}
+
+// MaxDownloadSize returns the largest file the bot downloads, or 0 if
+// downloads are not limited.
+//
+// It is MaxDownloadFileSize for the cloud Bot API server and unlimited for a
+// local one, unless set with WithMaxDownloadSize.
+func (bot *BotAPI) MaxDownloadSize() int64 {
+	switch {
+	case bot.maxDownloadSize > 0:
+		return bot.maxDownloadSize
+	case bot.localMode:
+		return 0
+	}
+
+	return MaxDownloadFileSize
+}
+
+// LogOut logs the bot out of the cloud Bot API server before moving it to a
+// local server. The bot cannot log back in to the cloud server for 10 minutes.
+func (bot *BotAPI) LogOut(ctx context.Context) error {
+	_, err := bot.RequestWithContext(ctx, LogOutConfig{})
+	return err
+}
+
+// CloseInstance closes the bot instance on its server before moving it from
+// one local server to another or back to the cloud server. The bot stops
+// working until it is started on another server. It cannot be called in the
+// first 10 minutes after the bot has started.
+func (bot *BotAPI) CloseInstance(ctx context.Context) error {
+	_, err := bot.RequestWithContext(ctx, CloseConfig{})
+	return err
+}
+
+// MigrateToLocalServer logs the bot out of its current server and returns a
+// new BotAPI using the local server at serverURL.
+//
+// The new BotAPI keeps every setting of the current one but its server;
+// options are applied after them. The current BotAPI must not be used
+// afterwards.
+func (bot *BotAPI) MigrateToLocalServer(ctx context.Context, serverURL string, options ...BotAPIOption) (*BotAPI, error) {
+	if bot.localMode {
+		if err := bot.CloseInstance(ctx); err != nil {
+			return nil, err
+		}
+	} else if err := bot.LogOut(ctx); err != nil {
+		return nil, err
+	}
+
+	return newBotAPI(bot.Token, bot.currentConfig(), append([]BotAPIOption{WithLocalServer(serverURL)}, options...))
+}
+
+// MigrateToCloudServer closes the bot instance on its local server and
+// returns a new BotAPI using the cloud Bot API server.
+//
+// The new BotAPI keeps every setting of the current one but its server;
+// options are applied after them. The current BotAPI must not be used
+// afterwards.
+func (bot *BotAPI) MigrateToCloudServer(ctx context.Context, options ...BotAPIOption) (*BotAPI, error) {
+	if !bot.localMode {
+		return nil, fmt.Errorf("bot does not use a local server")
+	}
+	if err := bot.CloseInstance(ctx); err != nil {
+		return nil, err
+	}
+
+	config := bot.currentConfig()
+	config.apiEndpoint = APIEndpoint
+	config.fileEndpoint = FileEndpoint
+	config.localMode = false
+
+	return newBotAPI(bot.Token, config, options)
+}
+
+// currentConfig returns the settings of the bot, including the changes made
+// to its exported fields.
+func (bot *BotAPI) currentConfig() botAPIConfig {
+	config := bot.botAPIConfig
+	config.client = bot.Client
+	config.debug = bot.Debug
+	config.buffer = bot.Buffer
+
+	return config
+}
+
+// localFileReferences replaces FilePath uploads with file:// references a
+// local Bot API server reads from disk. Files attached to input media with
+// attach:// are replaced in the serialized media as well.
+func localFileReferences(params Params, files []RequestFile) (Params, []RequestFile, error) {
+	var remaining []RequestFile
+
+	for _, file := range files {
+		path, ok := file.Data.(FilePath)
+		if !ok {
+			remaining = append(remaining, file)
+			continue
+		}
+
+		abs, err := filepath.Abs(string(path))
+		if err != nil {
+			return params, files, fmt.Errorf("resolve upload %q: %w", file.Name, err)
+		}
+		ref := "file://" + filepath.ToSlash(abs)
+
+		if params == nil {
+			params = make(Params)
+		}
+		if !replaceAttachReference(params, file.Name, ref) {
+			params[file.Name] = ref
+		}
+	}
+
+	return params, remaining, nil
+}
+
+// replaceAttachReference replaces the attach:// reference to name in the
+// JSON serialized params and reports whether there was one.
+func replaceAttachReference(params Params, name, ref string) bool {
+	attach, _ := json.Marshal("attach://" + name)
+	value, _ := json.Marshal(ref)
+
+	replaced := false
+	for key, param := range params {
+		if strings.Contains(param, string(attach)) {
+			params[key] = strings.ReplaceAll(param, string(attach), string(value))
+			replaced = true
+		}
+	}
+
+	return replaced
+}
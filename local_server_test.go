@@ -0,0 +1,165 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLocalModeSendsFilePathsAsReferences(t *testing.T) {
+	var requests []*http.Request
+	var bodies []url.Values
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			body, _ := io.ReadAll(req.Body)
+			values, _ := url.ParseQuery(string(body))
+			bodies = append(bodies, values)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":{"message_id":1,"date":1,"chat":{"id":1,"type":"private"}}}`)),
+			}, nil
+		},
+	})
+	bot.localMode = true
+
+	path := filepath.Join(t.TempDir(), "photo.jpg")
+	if _, err := bot.Send(NewPhoto(1, FilePath(path))); err != nil {
+		t.Fatal(err)
+	}
+	first, second := NewInputMediaPhoto(FilePath(path)), NewInputMediaPhoto(FileID("id"))
+	if _, err := bot.Request(NewMediaGroup(1, []InputMedia{&first, &second})); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, req := range requests {
+		if req.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Fatalf("expected form request, got %s", req.Header.Get("Content-Type"))
+		}
+	}
+	ref := "file://" + filepath.ToSlash(path)
+	if bodies[0].Get("photo") != ref {
+		t.Fatalf("unexpected photo reference: %v", bodies[0])
+	}
+	if media := bodies[1].Get("media"); !strings.Contains(media, `"media":"`+ref+`"`) || strings.Contains(media, "attach://") {
+		t.Fatalf("unexpected media: %s", media)
+	}
+}
+
+func TestLocalModeDownloadsFromDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "document.txt")
+	if err := os.WriteFile(path, []byte("content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			if !strings.HasSuffix(req.URL.Path, "/getFile") {
+				t.Fatalf("unexpected request to %s", req.URL)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":{"file_id":"id","file_size":7,"file_path":` + strconv.Quote(path) + `}}`)),
+			}, nil
+		},
+	})
+	bot.localMode = true
+
+	var buf bytes.Buffer
+	file, err := bot.DownloadFile("id", &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "content" || bot.FileURL(file) != "file://"+filepath.ToSlash(path) {
+		t.Fatalf("unexpected download %q from %s", buf.String(), bot.FileURL(file))
+	}
+	if bot.MaxDownloadSize() != 0 || bot.MaxUploadSize() != MaxLocalUploadFileSize {
+		t.Fatalf("unexpected local limits %d, %d", bot.MaxDownloadSize(), bot.MaxUploadSize())
+	}
+}
+
+func TestMigrateToLocalServer(t *testing.T) {
+	var urls []string
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.String())
+			if strings.HasSuffix(req.URL.Path, "/getMe") {
+				return okGetMeResponse(), nil
+			}
+			return okAPIResponse(), nil
+		},
+	})
+
+	local, err := bot.MigrateToLocalServer(context.Background(), "http://localhost:8081/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !local.IsLocalMode() || local.Self.UserName != "test_bot" {
+		t.Fatalf("unexpected local bot: %+v", local)
+	}
+
+	if _, err := local.MigrateToCloudServer(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"https://example.com/bottoken/logOut",
+		"http://localhost:8081/bottoken/getMe",
+		"http://localhost:8081/bottoken/close",
+		"https://api.telegram.org/bottoken/getMe",
+	}
+	if strings.Join(urls, " ") != strings.Join(expected, " ") {
+		t.Fatalf("unexpected requests: %v", urls)
+	}
+}
+
+func TestMigrationKeepsOptions(t *testing.T) {
+	var sent []string
+	client := fakeHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+		sent = append(sent, req.URL.String())
+		return okGetMeResponse(), nil
+	}}
+
+	var sink bytes.Buffer
+	bot, err := NewBotAPIWithOptions("token", WithHTTPClient(client), WithDryRun(&sink), WithRequestValidation(), WithMaxDownloadSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local, err := bot.MigrateToLocalServer(context.Background(), "http://localhost:8081")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cloud, err := local.MigrateToCloudServer(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, migrated := range []*BotAPI{local, cloud} {
+		if migrated.dryRun == nil || !migrated.validate || migrated.MaxDownloadSize() != 10 {
+			t.Fatalf("expected the options to be kept, got %+v", migrated.botAPIConfig)
+		}
+	}
+	if _, err := cloud.Send(NewMessage(1, "hello")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"https://api.telegram.org/bottoken/getMe",
+		"http://localhost:8081/bottoken/getMe",
+		"https://api.telegram.org/bottoken/getMe",
+	}
+	if strings.Join(sent, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected only getMe to be sent, got %v", sent)
+	}
+	if records := strings.Count(sink.String(), "\n"); records != 3 {
+		t.Fatalf("expected logOut, close and sendMessage to be recorded, got %s", sink.String())
+	}
+}
@@ -0,0 +1,384 @@
+package tgbotapi
+
+import (
+	"html"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// modeRichMarkdown is the markup of rich messages sent as Markdown.
+const modeRichMarkdown = "RichMarkdown"
+
+// markupSyntax describes the delimiters of a Markdown flavour, longest first.
+type markupSyntax struct {
+	delimiters []string
+	// code are the delimiters inside which other delimiters are literal.
+	code []string
+}
+
+var markupSyntaxes = map[string]markupSyntax{
+	ModeMarkdown: {
+		delimiters: []string{"```", "`", "*", "_"},
+		code:       []string{"```", "`"},
+	},
+	ModeMarkdownV2: {
+		delimiters: []string{"```", "`", "||", "__", "*", "_", "~"},
+		code:       []string{"```", "`"},
+	},
+	modeRichMarkdown: {
+		delimiters: []string{"```", "`", "**", "__", "~~", "||", "*", "_"},
+		code:       []string{"```", "`"},
+	},
+}
+
+// markupToken is a part of a text in a parse mode: a visible character, an
+// escape or HTML entity, or markup opening or closing an entity.
+type markupToken struct {
+	raw string
+	// units is the length of the visible text in UTF-16 code units.
+	units int
+	open  *markupEntity
+	close bool
+}
+
+// markupEntity is an entity opened by markup.
+type markupEntity struct {
+	open  string
+	close string
+	// closeAt is the byte offset of the closing markup of a link.
+	closeAt int
+	// unclosed is set when the closing markup could not be found, so the
+	// entity cannot be closed at a split.
+	unclosed bool
+	code     bool
+}
+
+func (t markupToken) separator() bool {
+	return t.raw == "\n" || t.raw == " "
+}
+
+// tokenizeMarkup splits a text in a parse mode into tokens. Unknown parse
+// modes are treated as plain text. Malformed markup is treated as text.
+func tokenizeMarkup(text, parseMode string) []markupToken {
+	if parseMode == ModeHTML {
+		return tokenizeHTML(text)
+	}
+	if syntax, ok := markupSyntaxes[parseMode]; ok {
+		return tokenizeMarkdown(text, syntax)
+	}
+
+	tokens := make([]markupToken, 0, len(text))
+	for _, r := range text {
+		tokens = append(tokens, runeToken(r))
+	}
+	return tokens
+}
+
+func runeToken(r rune) markupToken {
+	return markupToken{raw: string(r), units: utf16.RuneLen(r)}
+}
+
+func tokenizeHTML(text string) []markupToken {
+	var tokens []markupToken
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '<' && strings.IndexByte(rest, '>') > 1:
+			tag := rest[:strings.IndexByte(rest, '>')+1]
+			if strings.HasPrefix(tag, "</") {
+				tokens = append(tokens, markupToken{raw: tag, close: true})
+			} else {
+				name, _, _ := strings.Cut(strings.Trim(tag, "<>"), " ")
+				tokens = append(tokens, markupToken{raw: tag, open: &markupEntity{
+					open:  tag,
+					close: "</" + name + ">",
+				}})
+			}
+			i += len(tag)
+		case rest[0] == '&' && htmlEntityLength(rest) > 0:
+			entity := rest[:htmlEntityLength(rest)]
+			tokens = append(tokens, markupToken{raw: entity, units: utf16Length(html.UnescapeString(entity))})
+			i += len(entity)
+		default:
+			r, size := utf8.DecodeRuneInString(rest)
+			tokens = append(tokens, runeToken(r))
+			i += size
+		}
+	}
+
+	return tokens
+}
+
+// htmlEntityLength returns the length of the HTML entity text starts with,
+// or zero if it does not start with one.
+func htmlEntityLength(text string) int {
+	for i := 1; i < len(text) && i <= 10; i++ {
+		c := text[i]
+		switch {
+		case c == ';':
+			if i == 1 {
+				return 0
+			}
+			return i + 1
+		case c == '#' && i == 1, c >= '0' && c <= '9', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+func tokenizeMarkdown(text string, syntax markupSyntax) []markupToken {
+	var (
+		tokens []markupToken
+		stack  []*markupEntity
+	)
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		var top *markupEntity
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if top != nil && top.closeAt == i && top.close != "" && strings.HasPrefix(rest, top.close) {
+			tokens = append(tokens, markupToken{raw: top.close, close: true})
+			stack = stack[:len(stack)-1]
+			i += len(top.close)
+			continue
+		}
+		if top != nil && top.code && strings.HasPrefix(rest, top.close) {
+			tokens = append(tokens, markupToken{raw: top.close, close: true})
+			stack = stack[:len(stack)-1]
+			i += len(top.close)
+			continue
+		}
+
+		if rest[0] == '\\' && len(rest) > 1 {
+			r, size := utf8.DecodeRuneInString(rest[1:])
+			tokens = append(tokens, markupToken{raw: rest[:1+size], units: utf16.RuneLen(r)})
+			i += 1 + size
+			continue
+		}
+
+		if top == nil || !top.code {
+			if delimiter := markupDelimiter(rest, syntax.delimiters); delimiter != "" {
+				if top != nil && top.close == delimiter && top.closeAt < 0 {
+					tokens = append(tokens, markupToken{raw: delimiter, close: true})
+					stack = stack[:len(stack)-1]
+					i += len(delimiter)
+					continue
+				}
+
+				entity := &markupEntity{open: delimiter, close: delimiter, closeAt: -1}
+				for _, code := range syntax.code {
+					entity.code = entity.code || code == delimiter
+				}
+				if delimiter == "```" {
+					// The language of a code block is a part of its opening markup.
+					if end := strings.IndexByte(rest, '\n'); end >= 0 && !strings.ContainsAny(rest[3:end], "` ") {
+						entity.open = rest[:end+1]
+					}
+				}
+				tokens = append(tokens, markupToken{raw: entity.open, open: entity})
+				stack = append(stack, entity)
+				i += len(entity.open)
+				continue
+			}
+
+			if rest[0] == '[' || strings.HasPrefix(rest, "![") {
+				open := rest[:strings.IndexByte(rest, '[')+1]
+				entity := &markupEntity{open: open, closeAt: -1, unclosed: true}
+				if end, link := markdownLinkEnd(text, i+len(open)); end >= 0 {
+					entity.close, entity.closeAt, entity.unclosed = link, end, false
+				}
+				tokens = append(tokens, markupToken{raw: open, open: entity})
+				stack = append(stack, entity)
+				i += len(open)
+				continue
+			}
+		}
+
+		r, size := utf8.DecodeRuneInString(rest)
+		tokens = append(tokens, runeToken(r))
+		i += size
+	}
+
+	return tokens
+}
+
+// markupDelimiter returns the delimiter text starts with, if any.
+func markupDelimiter(text string, delimiters []string) string {
+	for _, delimiter := range delimiters {
+		if strings.HasPrefix(text, delimiter) {
+			return delimiter
+		}
+	}
+	return ""
+}
+
+// markdownLinkEnd finds the "](url)" closing the text of a link starting at
+// start. It returns its offset and text, or -1 if the link is not closed.
+func markdownLinkEnd(text string, start int) (int, string) {
+	depth := 0
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if !strings.HasPrefix(text[i:], "](") {
+				return -1, ""
+			}
+			for j := i + 2; j < len(text); j++ {
+				switch text[j] {
+				case '\\':
+					j++
+				case ')':
+					return i, text[i : j+1]
+				}
+			}
+			return -1, ""
+		}
+	}
+	return -1, ""
+}
+
+// markupLength returns the length of the visible text of a text in a parse
+// mode, in UTF-16 code units.
+func markupLength(text, parseMode string) int {
+	n := 0
+	for _, token := range tokenizeMarkup(text, parseMode) {
+		n += token.units
+	}
+	return n
+}
+
+// splitMarkup splits a text in a parse mode so that the visible text of the
+// first part is at most limit UTF-16 code units long. It prefers splitting
+// outside of entities, at the last newline, then at the last space. Entities
+// open at the split are closed at the end of the first part and reopened at
+// the start of the rest.
+func splitMarkup(text, parseMode string, limit int) (string, string) {
+	tokens := tokenizeMarkup(text, parseMode)
+
+	const (
+		newlineOutside = iota
+		spaceOutside
+		newline
+		space
+		closable
+		anywhere
+		tiers
+	)
+	var cuts [tiers]int
+
+	var stack []*markupEntity
+	units, unclosed := 0, 0
+	fits := true
+	for i, token := range tokens {
+		if units > 0 {
+			switch {
+			case token.raw == "\n" && len(stack) == 0:
+				cuts[newlineOutside] = i
+			case token.raw == " " && len(stack) == 0:
+				cuts[spaceOutside] = i
+			}
+			switch {
+			case token.raw == "\n" && unclosed == 0:
+				cuts[newline] = i
+			case token.raw == " " && unclosed == 0:
+				cuts[space] = i
+			}
+			if unclosed == 0 {
+				cuts[closable] = i
+			}
+			cuts[anywhere] = i
+		}
+
+		if units+token.units > limit {
+			fits = false
+			break
+		}
+		units += token.units
+
+		switch {
+		case token.open != nil:
+			stack = append(stack, token.open)
+			if token.open.unclosed {
+				unclosed++
+			}
+		case token.close && len(stack) > 0:
+			if stack[len(stack)-1].unclosed {
+				unclosed--
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if fits {
+		return text, ""
+	}
+
+	cut := 0
+	for _, c := range cuts {
+		if c > 0 {
+			cut = c
+			break
+		}
+	}
+	if cut == 0 {
+		// Not even the first character fits; split after it anyway.
+		for cut < len(tokens)-1 && tokens[cut].units == 0 {
+			cut++
+		}
+		cut++
+	}
+
+	// Keep markup opening an entity with its text, and markup closing one
+	// with the text before it.
+	drop := cut < len(tokens) && tokens[cut].separator()
+	for !drop && cut > 1 && tokens[cut-1].open != nil {
+		cut--
+	}
+	for !drop && cut < len(tokens) && tokens[cut].close {
+		cut++
+	}
+
+	stack = stack[:0]
+	var head strings.Builder
+	for _, token := range tokens[:cut] {
+		head.WriteString(token.raw)
+		switch {
+		case token.open != nil:
+			stack = append(stack, token.open)
+		case token.close && len(stack) > 0:
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	var rest strings.Builder
+	for i := len(stack) - 1; i >= 0; i-- {
+		head.WriteString(stack[i].close)
+	}
+	for _, entity := range stack {
+		if !entity.unclosed {
+			rest.WriteString(entity.open)
+		}
+	}
+	if drop {
+		cut++
+	}
+	for _, token := range tokens[cut:] {
+		rest.WriteString(token.raw)
+	}
+
+	return head.String(), rest.String()
+}
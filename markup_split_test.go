@@ -0,0 +1,89 @@
+package tgbotapi
+
+import "testing"
+
+func TestSplitMarkup(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		parseMode string
+		limit     int
+		head      string
+		rest      string
+	}{
+		{
+			name:  "plain text at a space",
+			text:  "hello there world",
+			limit: 12,
+			head:  "hello there",
+			rest:  "world",
+		},
+		{
+			name:      "HTML outside of entities",
+			text:      "intro text <b>bold</b>",
+			parseMode: ModeHTML,
+			limit:     12,
+			head:      "intro text",
+			rest:      "<b>bold</b>",
+		},
+		{
+			name:      "HTML entity reopened",
+			text:      `<a href="https://example.com">a long link</a>`,
+			parseMode: ModeHTML,
+			limit:     7,
+			head:      `<a href="https://example.com">a long</a>`,
+			rest:      `<a href="https://example.com">link</a>`,
+		},
+		{
+			name:      "HTML escapes are not cut",
+			text:      "&lt;&lt;&lt;&lt;",
+			parseMode: ModeHTML,
+			limit:     3,
+			head:      "&lt;&lt;&lt;",
+			rest:      "&lt;",
+		},
+		{
+			name:      "MarkdownV2 nested entities and escapes",
+			text:      `*bold _italic\. text_ more*`,
+			parseMode: ModeMarkdownV2,
+			limit:     13,
+			head:      `*bold _italic\._*`,
+			rest:      `*_text_ more*`,
+		},
+		{
+			name:      "MarkdownV2 link",
+			text:      `[some link text](https://example.com/a\)b)`,
+			parseMode: ModeMarkdownV2,
+			limit:     10,
+			head:      `[some link](https://example.com/a\)b)`,
+			rest:      `[text](https://example.com/a\)b)`,
+		},
+		{
+			name:      "Markdown code block keeps its language",
+			text:      "```go\nfirst()\nsecond()\n```",
+			parseMode: ModeMarkdown,
+			limit:     10,
+			head:      "```go\nfirst()```",
+			rest:      "```go\nsecond()\n```",
+		},
+		{
+			name:      "fits",
+			text:      "<b>short</b>",
+			parseMode: ModeHTML,
+			limit:     5,
+			head:      "<b>short</b>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, rest := splitMarkup(tt.text, tt.parseMode, tt.limit)
+			if head != tt.head || rest != tt.rest {
+				t.Fatalf("expected %q and %q, got %q and %q", tt.head, tt.rest, head, rest)
+			}
+			if n := markupLength(head, tt.parseMode); n > tt.limit {
+				t.Fatalf("head is %d units long", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package tgbotapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+const (
+	// MinMediaGroupSize is the smallest number of items in a media group.
+	MinMediaGroupSize = 2
+	// MaxMediaGroupSize is the largest number of items in a media group.
+	MaxMediaGroupSize = 10
+	// MaxCaptionLength is the maximum length of a caption, in UTF-16 code units.
+	MaxCaptionLength = 1024
+)
+
+// MediaGroupBuilder builds media groups from any number of items.
+//
+// Items are split into as few consecutive groups of 2-10 items as possible.
+// Photos, videos and live photos can be mixed; audio and documents can only
+// be grouped with items of the same type. The caption is shown once: on the
+// first item for photos and videos, and on the last item for audio and
+// documents, where Telegram shows it below the album.
+type MediaGroupBuilder struct {
+	BaseChat
+	media           []InputMedia
+	caption         string
+	parseMode       string
+	captionEntities []MessageEntity
+}
+
+// NewMediaGroupBuilder creates a new MediaGroupBuilder for a chat.
+func NewMediaGroupBuilder(chatID int64) *MediaGroupBuilder {
+	return &MediaGroupBuilder{
+		BaseChat: BaseChat{
+			ChatConfig: ChatConfig{ChatID: chatID},
+		},
+	}
+}
+
+// Add adds items to the album.
+func (b *MediaGroupBuilder) Add(media ...InputMedia) *MediaGroupBuilder {
+	b.media = append(b.media, media...)
+	return b
+}
+
+// Photo adds a photo to the album.
+func (b *MediaGroupBuilder) Photo(file RequestFileData) *MediaGroupBuilder {
+	media := NewInputMediaPhoto(file)
+	return b.Add(&media)
+}
+
+// Video adds a video to the album.
+func (b *MediaGroupBuilder) Video(file RequestFileData) *MediaGroupBuilder {
+	media := NewInputMediaVideo(file)
+	return b.Add(&media)
+}
+
+// Audio adds an audio file to the album.
+func (b *MediaGroupBuilder) Audio(file RequestFileData) *MediaGroupBuilder {
+	media := NewInputMediaAudio(file)
+	return b.Add(&media)
+}
+
+// Document adds a document to the album.
+func (b *MediaGroupBuilder) Document(file RequestFileData) *MediaGroupBuilder {
+	media := NewInputMediaDocument(file)
+	return b.Add(&media)
+}
+
+// Caption sets the caption of the album, formatted with parseMode.
+func (b *MediaGroupBuilder) Caption(caption, parseMode string) *MediaGroupBuilder {
+	b.caption = caption
+	b.parseMode = parseMode
+	return b
+}
+
+// CaptionEntities sets the entities of the caption of the album.
+func (b *MediaGroupBuilder) CaptionEntities(entities ...MessageEntity) *MediaGroupBuilder {
+	b.captionEntities = entities
+	return b
+}
+
+// Validate checks the album against the rules of the Bot API.
+func (b *MediaGroupBuilder) Validate() error {
+	var errs []error
+
+	if len(b.media) < MinMediaGroupSize {
+		errs = append(errs, fmt.Errorf("media group: %d items, at least %d are required", len(b.media), MinMediaGroupSize))
+	}
+
+	kind := ""
+	for i, media := range b.media {
+		itemKind, err := mediaGroupKind(media)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("media group: item %d: %w", i, err))
+			continue
+		}
+
+		switch {
+		case kind == "":
+			kind = itemKind
+		case kind != itemKind:
+			errs = append(errs, fmt.Errorf("media group: item %d: %s cannot be mixed with %s", i, itemKind, kind))
+		}
+
+		if base := inputMediaBase(media); b.caption != "" && base != nil && base.Caption != "" {
+			errs = append(errs, fmt.Errorf("media group: item %d: has its own caption although the album has one", i))
+		}
+	}
+
+	if n := markupLength(b.caption, b.parseMode); n > MaxCaptionLength {
+		errs = append(errs, fmt.Errorf("media group: caption is %d characters long, the limit is %d", n, MaxCaptionLength))
+	}
+	if b.parseMode != "" && len(b.captionEntities) > 0 {
+		errs = append(errs, errors.New("media group: caption cannot have both a parse mode and entities"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Build validates the album and splits it into media groups.
+//
+// ReplyParameters only apply to the first group.
+func (b *MediaGroupBuilder) Build() ([]MediaGroupConfig, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+
+	groups := (len(b.media) + MaxMediaGroupSize - 1) / MaxMediaGroupSize
+	configs := make([]MediaGroupConfig, 0, groups)
+
+	start := 0
+	for i := 0; i < groups; i++ {
+		// Spread the items evenly, so no group is left with a single item.
+		end := start + (len(b.media)-start)/(groups-i)
+		if (len(b.media)-start)%(groups-i) != 0 {
+			end++
+		}
+
+		config := MediaGroupConfig{
+			BaseChat: b.BaseChat,
+			Media:    cloneMediaSlice(b.media[start:end]),
+		}
+		if i > 0 {
+			config.ReplyParameters = ReplyParameters{}
+		}
+		configs = append(configs, config)
+
+		start = end
+	}
+
+	if b.caption != "" || len(b.captionEntities) > 0 {
+		kind, _ := mediaGroupKind(b.media[0])
+		target := inputMediaBase(configs[0].Media[0])
+		if kind == "audio" || kind == "document" {
+			last := configs[len(configs)-1].Media
+			target = inputMediaBase(last[len(last)-1])
+		}
+
+		target.Caption = b.caption
+		target.ParseMode = b.parseMode
+		target.CaptionEntities = b.captionEntities
+	}
+
+	return configs, nil
+}
+
+// SendMediaGroups sends media groups one after another and returns all sent
+// messages in order. It stops at the first group that fails to send and
+// returns the messages sent so far.
+func (bot *BotAPI) SendMediaGroups(configs ...MediaGroupConfig) ([]Message, error) {
+	var messages []Message
+
+	for _, config := range configs {
+		sent, err := bot.SendMediaGroup(config)
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, sent...)
+	}
+
+	return messages, nil
+}
+
+// mediaGroupKind returns the kind of items an item can be grouped with.
+func mediaGroupKind(media InputMedia) (string, error) {
+	// A nil pointer in the interface is as invalid as a nil interface.
+	if value := reflect.ValueOf(media); media == nil || value.Kind() == reflect.Pointer && value.IsNil() {
+		return "", errors.New("item is nil")
+	}
+
+	switch media.(type) {
+	case *InputMediaPhoto, *InputMediaVideo, *InputMediaLivePhoto:
+		return "photos and videos", nil
+	case *InputMediaAudio:
+		return "audio", nil
+	case *InputMediaDocument:
+		return "document", nil
+	}
+
+	return "", fmt.Errorf("%s cannot be sent in a media group", media.getType())
+}
+
+// inputMediaBase returns the BaseInputMedia of media group items.
+func inputMediaBase(media InputMedia) *BaseInputMedia {
+	switch media := media.(type) {
+	case *InputMediaPhoto:
+		return &media.BaseInputMedia
+	case *InputMediaVideo:
+		return &media.BaseInputMedia
+	case *InputMediaLivePhoto:
+		return &media.BaseInputMedia
+	case *InputMediaAudio:
+		return &media.BaseInputMedia
+	case *InputMediaDocument:
+		return &media.BaseInputMedia
+	}
+
+	return nil
+}
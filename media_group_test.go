@@ -0,0 +1,148 @@
+package tgbotapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMediaGroupBuilderValidate(t *testing.T) {
+	single := NewMediaGroupBuilder(1).Photo(FileID("a"))
+	if err := single.Validate(); err == nil {
+		t.Fatal("expected a single item to be rejected")
+	}
+
+	mixed := NewMediaGroupBuilder(1).Photo(FileID("a")).Video(FileID("b")).Audio(FileID("c"))
+	err := mixed.Validate()
+	if err == nil || !strings.Contains(err.Error(), "item 2: audio cannot be mixed") {
+		t.Fatalf("expected audio mixed with photos to be rejected, got %v", err)
+	}
+
+	sticker := NewInputMediaSticker(FileID("s"))
+	other := NewMediaGroupBuilder(1).Photo(FileID("a")).Add(&sticker)
+	if err := other.Validate(); err == nil {
+		t.Fatal("expected a sticker to be rejected")
+	}
+
+	captioned := NewInputMediaPhoto(FileID("a"))
+	captioned.Caption = "own"
+	twice := NewMediaGroupBuilder(1).Add(&captioned).Photo(FileID("b")).Caption("album", "")
+	if err := twice.Validate(); err == nil {
+		t.Fatal("expected an item caption to conflict with the album caption")
+	}
+
+	if err := NewMediaGroupBuilder(1).Document(FileID("a")).Document(FileID("b")).Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	typedNil := NewMediaGroupBuilder(1).Photo(FileID("a")).Add((*InputMediaPhoto)(nil)).Caption("album", "")
+	if err := typedNil.Validate(); err == nil || !strings.Contains(err.Error(), "item 1: item is nil") {
+		t.Fatalf("expected a nil photo to be rejected, got %v", err)
+	}
+	if _, err := typedNil.Build(); err == nil {
+		t.Fatal("expected building with a nil photo to fail")
+	}
+}
+
+func TestMediaGroupBuilderSplitsAndPlacesCaption(t *testing.T) {
+	builder := NewMediaGroupBuilder(1).Caption("album", ModeHTML)
+	builder.ReplyParameters.MessageID = 42
+	for i := 0; i < 21; i++ {
+		builder.Document(FileID(strconv.Itoa(i)))
+	}
+
+	configs, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(configs) != 3 || len(configs[0].Media) != 7 || len(configs[1].Media) != 7 || len(configs[2].Media) != 7 {
+		t.Fatalf("expected 3 groups of 7 items, got %d groups", len(configs))
+	}
+	if configs[0].ReplyParameters.MessageID != 42 || configs[1].ReplyParameters.MessageID != 0 {
+		t.Fatal("expected only the first group to be a reply")
+	}
+
+	last := configs[2].Media[6].(*InputMediaDocument)
+	if last.Caption != "album" || last.ParseMode != ModeHTML || last.getMedia() != FileID("20") {
+		t.Fatalf("expected the caption on the last document, got %+v", last)
+	}
+	if first := configs[0].Media[0].(*InputMediaDocument); first.Caption != "" {
+		t.Fatalf("expected no caption on the first document, got %q", first.Caption)
+	}
+
+	photos := NewMediaGroupBuilder(1).Photo(FileID("a")).Video(FileID("b")).Caption("album", "")
+	configs, err = photos.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := configs[0].Media[0].(*InputMediaPhoto); first.Caption != "album" {
+		t.Fatalf("expected the caption on the first photo, got %q", first.Caption)
+	}
+	if photos.media[0].(*InputMediaPhoto).Caption != "" {
+		t.Fatal("expected Build not to modify the added items")
+	}
+}
+
+func TestSendMediaGroupsReturnsMessagesInOrder(t *testing.T) {
+	nextID := 0
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			if err := req.ParseForm(); err != nil {
+				t.Fatalf("parse form: %v", err)
+			}
+			var media []json.RawMessage
+			if err := json.Unmarshal([]byte(req.PostForm.Get("media")), &media); err != nil {
+				t.Fatalf("decode media: %v", err)
+			}
+
+			messages := make([]string, len(media))
+			for i := range media {
+				nextID++
+				messages[i] = `{"message_id":` + strconv.Itoa(nextID) + `,"date":1,"chat":{"id":1,"type":"private"}}`
+			}
+			body := `{"ok":true,"result":[` + strings.Join(messages, ",") + `]}`
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		},
+	})
+
+	builder := NewMediaGroupBuilder(1)
+	for i := 0; i < 13; i++ {
+		builder.Photo(FileID(strconv.Itoa(i)))
+	}
+	configs, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := bot.SendMediaGroups(configs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 13 {
+		t.Fatalf("expected 13 messages, got %d", len(messages))
+	}
+	for i, message := range messages {
+		if message.MessageID != i+1 {
+			t.Fatalf("expected message %d to have ID %d, got %d", i, i+1, message.MessageID)
+		}
+	}
+}
+
+func TestMediaGroupBuilderCountsVisibleCaption(t *testing.T) {
+	fits := "<b>" + strings.Repeat("a", MaxCaptionLength) + "</b>"
+	if _, err := NewMediaGroupBuilder(1).Photo(FileID("a")).Photo(FileID("b")).Caption(fits, ModeHTML).Build(); err != nil {
+		t.Fatalf("expected markup not to count towards the caption length, got %v", err)
+	}
+
+	long := "<b>" + strings.Repeat("a", MaxCaptionLength+1) + "</b>"
+	if _, err := NewMediaGroupBuilder(1).Photo(FileID("a")).Photo(FileID("b")).Caption(long, ModeHTML).Build(); err == nil {
+		t.Fatal("expected a long HTML caption to be rejected")
+	}
+}
@@ -0,0 +1,57 @@
+package tgbotapi
+
+import "maps"
+
+// RawRequest is a request of a Bot API method defined by its name, params
+// and files. It can be sent with Request and Send like any config, so it
+// allows calling methods the library has no config for yet.
+//
+// Files which do not need to be uploaded, such as a FileID, are sent as
+// params like those of configs.
+type RawRequest struct {
+	Method string
+	Params Params
+	Files  []RequestFile
+}
+
+// NewRawRequest creates a request of a Bot API method.
+func NewRawRequest(method string, params Params, files ...RequestFile) RawRequest {
+	return RawRequest{
+		Method: method,
+		Params: params,
+		Files:  files,
+	}
+}
+
+func (r RawRequest) method() string {
+	return r.Method
+}
+
+func (r RawRequest) params() (Params, error) {
+	params := maps.Clone(r.Params)
+	if params == nil {
+		params = make(Params)
+	}
+	return params, nil
+}
+
+func (r RawRequest) files() []RequestFile {
+	return r.Files
+}
+
+// Encode returns the method, params and files a config is sent with.
+//
+// The files are returned as the config declares them: files which do not
+// need to be uploaded are only added to the params when sending.
+func Encode(c Chattable) (RawRequest, error) {
+	params, err := c.params()
+	if err != nil {
+		return RawRequest{}, err
+	}
+
+	request := RawRequest{Method: c.method(), Params: params}
+	if f, ok := c.(Fileable); ok {
+		request.Files = f.files()
+	}
+	return request, nil
+}
@@ -0,0 +1,79 @@
+package tgbotapi
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"testing"
+)
+
+func TestRawRequest(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			if method := path.Base(req.URL.Path); method != "setFutureFeature" {
+				t.Fatalf("unexpected method %q", method)
+			}
+
+			switch req.Header.Get("Content-Type") {
+			case "application/x-www-form-urlencoded":
+				if err := req.ParseForm(); err != nil {
+					t.Fatal(err)
+				}
+				if req.PostForm.Get("enabled") != "true" || req.PostForm.Get("icon") != "file-id" {
+					t.Fatalf("unexpected params %v", req.PostForm)
+				}
+			default:
+				if err := req.ParseMultipartForm(1 << 20); err != nil {
+					t.Fatal(err)
+				}
+				file, _, err := req.FormFile("icon")
+				if err != nil {
+					t.Fatal(err)
+				}
+				content, _ := io.ReadAll(file)
+				if string(content) != "icon" {
+					t.Fatalf("unexpected upload %q", content)
+				}
+			}
+			return okAPIResponse(), nil
+		},
+	})
+
+	params := Params{"enabled": "true"}
+	request := NewRawRequest("setFutureFeature", params, RequestFile{Name: "icon", Data: FileID("file-id")})
+	if _, err := bot.Request(request); err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 1 {
+		t.Fatalf("expected the params of the request not to be changed, got %v", params)
+	}
+
+	request.Files[0].Data = FileBytes{Name: "icon.png", Bytes: []byte("icon")}
+	if _, err := bot.Request(request); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	photo := NewPhoto(1, FileBytes{Name: "cat.jpg", Bytes: []byte("jpeg")})
+	photo.Caption = "cat"
+
+	request, err := Encode(photo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Method != "sendPhoto" || request.Params["chat_id"] != "1" || request.Params["caption"] != "cat" {
+		t.Fatalf("unexpected request %+v", request)
+	}
+	if len(request.Files) != 1 || request.Files[0].Name != "photo" {
+		t.Fatalf("unexpected files %+v", request.Files)
+	}
+
+	encoded, err := Encode(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded.Method != request.Method || len(encoded.Params) != len(request.Params) || len(encoded.Files) != 1 {
+		t.Fatalf("expected encoding a RawRequest to return it, got %+v", encoded)
+	}
+}
@@ -0,0 +1,229 @@
+package tgbotapi
+
+// The results of the methods of configs, which Call decodes. Methods returning
+// either a Message or True, such as editMessageText, return a Message and are
+// marked with orTrue.
+
+func (EditMessageCaptionConfig) orTrue()      {}
+func (EditMessageLiveLocationConfig) orTrue() {}
+func (EditMessageMediaConfig) orTrue()        {}
+func (EditMessageReplyMarkupConfig) orTrue()  {}
+func (EditMessageTextConfig) orTrue()         {}
+func (SetGameScoreConfig) orTrue()            {}
+func (StopMessageLiveLocationConfig) orTrue() {}
+
+func (AnimationConfig) result() (_ Message)               { return }
+func (AudioConfig) result() (_ Message)                   { return }
+func (ContactConfig) result() (_ Message)                 { return }
+func (DiceConfig) result() (_ Message)                    { return }
+func (DocumentConfig) result() (_ Message)                { return }
+func (EditMessageCaptionConfig) result() (_ Message)      { return }
+func (EditMessageChecklistConfig) result() (_ Message)    { return }
+func (EditMessageLiveLocationConfig) result() (_ Message) { return }
+func (EditMessageMediaConfig) result() (_ Message)        { return }
+func (EditMessageReplyMarkupConfig) result() (_ Message)  { return }
+func (EditMessageTextConfig) result() (_ Message)         { return }
+func (ForwardConfig) result() (_ Message)                 { return }
+func (GameConfig) result() (_ Message)                    { return }
+func (InvoiceConfig) result() (_ Message)                 { return }
+func (LocationConfig) result() (_ Message)                { return }
+func (MessageConfig) result() (_ Message)                 { return }
+func (PaidMediaConfig) result() (_ Message)               { return }
+func (PhotoConfig) result() (_ Message)                   { return }
+func (SendChecklistConfig) result() (_ Message)           { return }
+func (SendLivePhotoConfig) result() (_ Message)           { return }
+func (SendPollConfig) result() (_ Message)                { return }
+func (SendRichMessageConfig) result() (_ Message)         { return }
+func (SetGameScoreConfig) result() (_ Message)            { return }
+func (StickerConfig) result() (_ Message)                 { return }
+func (StopMessageLiveLocationConfig) result() (_ Message) { return }
+func (VenueConfig) result() (_ Message)                   { return }
+func (VideoConfig) result() (_ Message)                   { return }
+func (VideoNoteConfig) result() (_ Message)               { return }
+func (VoiceConfig) result() (_ Message)                   { return }
+
+func (MediaGroupConfig) result() (_ []Message)               { return }
+func (UserPersonalChatMessagesConfig) result() (_ []Message) { return }
+
+func (CopyMessageConfig) result() (_ MessageID) { return }
+
+func (CopyMessagesConfig) result() (_ []MessageID)    { return }
+func (ForwardMessagesConfig) result() (_ []MessageID) { return }
+
+func (GetManagedBotAccessSettingsConfig) result() (_ BotAccessSettings) { return }
+
+func (GetMyCommandsConfig) result() (_ []BotCommand) { return }
+
+func (GetMyDescriptionConfig) result() (_ BotDescription) { return }
+
+func (GetMyNameConfig) result() (_ BotName) { return }
+
+func (GetMyShortDescriptionConfig) result() (_ BotShortDescription) { return }
+
+func (GetBusinessConnectionConfig) result() (_ BusinessConnection) { return }
+
+func (GetMyDefaultAdministratorRightsConfig) result() (_ ChatAdministratorRights) { return }
+
+func (ChatInfoConfig) result() (_ ChatFullInfo) { return }
+
+func (CreateChatInviteLinkConfig) result() (_ ChatInviteLink)       { return }
+func (CreateChatSubscriptionLinkConfig) result() (_ ChatInviteLink) { return }
+func (EditChatInviteLinkConfig) result() (_ ChatInviteLink)         { return }
+func (EditChatSubscriptionLinkConfig) result() (_ ChatInviteLink)   { return }
+func (RevokeChatInviteLinkConfig) result() (_ ChatInviteLink)       { return }
+
+func (ChatAdministratorsConfig) result() (_ []ChatMember) { return }
+
+func (GetChatMemberConfig) result() (_ ChatMember) { return }
+
+func (FileConfig) result() (_ File)          { return }
+func (UploadStickerConfig) result() (_ File) { return }
+
+func (CreateForumTopicConfig) result() (_ ForumTopic) { return }
+
+func (GetGameHighScoresConfig) result() (_ []GameHighScore) { return }
+
+func (GetAvailableGiftsConfig) result() (_ Gifts) { return }
+
+func (GetChatMenuButtonConfig) result() (_ MenuButton) { return }
+
+func (GetBusinessAccountGiftsConfig) result() (_ OwnedGifts) { return }
+func (GetChatGiftsConfig) result() (_ OwnedGifts)            { return }
+func (GetUserGiftsConfig) result() (_ OwnedGifts)            { return }
+
+func (StopPollConfig) result() (_ Poll) { return }
+
+func (SavePreparedInlineMessageConfig[T]) result() (_ PreparedInlineMessage) { return }
+
+func (SavePreparedKeyboardButtonConfig) result() (_ PreparedKeyboardButton) { return }
+
+func (AnswerGuestQueryConfig) result() (_ SentGuestMessage) { return }
+
+func (AnswerWebAppQueryConfig) result() (_ SentWebAppMessage) { return }
+
+func (GetBusinessAccountStarBalanceConfig) result() (_ StarAmount) { return }
+func (GetMyStarBalanceConfig) result() (_ StarAmount)              { return }
+
+func (GetStarTransactionsConfig) result() (_ StarTransactions) { return }
+
+func (GetCustomEmojiStickersConfig) result() (_ []Sticker)    { return }
+func (GetForumTopicIconStickersConfig) result() (_ []Sticker) { return }
+
+func (GetStickerSetConfig) result() (_ StickerSet) { return }
+
+func (EditStoryConfig) result() (_ Story)   { return }
+func (PostStoryConfig) result() (_ Story)   { return }
+func (RepostStoryConfig) result() (_ Story) { return }
+
+func (UpdateConfig) result() (_ []Update) { return }
+
+func (GetUserChatBoostsConfig) result() (_ UserChatBoosts) { return }
+
+func (UserProfileAudiosConfig) result() (_ UserProfileAudios) { return }
+
+func (UserProfilePhotosConfig) result() (_ UserProfilePhotos) { return }
+
+func (ChatMemberCountConfig) result() (_ int) { return }
+
+func (ChatInviteLinkConfig) result() (_ string)         { return }
+func (GetManagedBotTokenConfig) result() (_ string)     { return }
+func (InvoiceLinkConfig) result() (_ string)            { return }
+func (ReplaceManagedBotTokenConfig) result() (_ string) { return }
+
+func (AddStickerConfig) result() (_ bool)                        { return }
+func (AnswerChatJoinRequestQueryConfig) result() (_ bool)        { return }
+func (ApproveChatJoinRequestConfig) result() (_ bool)            { return }
+func (ApproveSuggestedPostConfig) result() (_ bool)              { return }
+func (BanChatMemberConfig) result() (_ bool)                     { return }
+func (BanChatSenderChatConfig) result() (_ bool)                 { return }
+func (CallbackConfig) result() (_ bool)                          { return }
+func (ChatActionConfig) result() (_ bool)                        { return }
+func (CloseConfig) result() (_ bool)                             { return }
+func (CloseForumTopicConfig) result() (_ bool)                   { return }
+func (CloseGeneralForumTopicConfig) result() (_ bool)            { return }
+func (ConvertGiftToStarsConfig) result() (_ bool)                { return }
+func (DeclineChatJoinRequest) result() (_ bool)                  { return }
+func (DeclineSuggestedPostConfig) result() (_ bool)              { return }
+func (DeleteAllMessageReactionsConfig) result() (_ bool)         { return }
+func (DeleteBusinessMessagesConfig) result() (_ bool)            { return }
+func (DeleteChatPhotoConfig) result() (_ bool)                   { return }
+func (DeleteChatStickerSetConfig) result() (_ bool)              { return }
+func (DeleteForumTopicConfig) result() (_ bool)                  { return }
+func (DeleteMessageConfig) result() (_ bool)                     { return }
+func (DeleteMessageReactionConfig) result() (_ bool)             { return }
+func (DeleteMessagesConfig) result() (_ bool)                    { return }
+func (DeleteMyCommandsConfig) result() (_ bool)                  { return }
+func (DeleteStickerConfig) result() (_ bool)                     { return }
+func (DeleteStickerSetConfig) result() (_ bool)                  { return }
+func (DeleteStoryConfig) result() (_ bool)                       { return }
+func (DeleteWebhookConfig) result() (_ bool)                     { return }
+func (EditForumTopicConfig) result() (_ bool)                    { return }
+func (EditGeneralForumTopicConfig) result() (_ bool)             { return }
+func (EditUserStarSubscriptionConfig) result() (_ bool)          { return }
+func (GiftPremiumSubscriptionConfig) result() (_ bool)           { return }
+func (HideGeneralForumTopicConfig) result() (_ bool)             { return }
+func (InlineConfig) result() (_ bool)                            { return }
+func (LeaveChatConfig) result() (_ bool)                         { return }
+func (LogOutConfig) result() (_ bool)                            { return }
+func (NewStickerSetConfig) result() (_ bool)                     { return }
+func (PinChatMessageConfig) result() (_ bool)                    { return }
+func (PreCheckoutConfig) result() (_ bool)                       { return }
+func (PromoteChatMemberConfig) result() (_ bool)                 { return }
+func (ReadBusinessMessageConfig) result() (_ bool)               { return }
+func (RefundStarPaymentConfig) result() (_ bool)                 { return }
+func (RemoveBusinessAccountProfilePhotoConfig) result() (_ bool) { return }
+func (RemoveChatVerificationConfig) result() (_ bool)            { return }
+func (RemoveMyProfilePhotoConfig) result() (_ bool)              { return }
+func (RemoveUserVerificationConfig) result() (_ bool)            { return }
+func (ReopenForumTopicConfig) result() (_ bool)                  { return }
+func (ReopenGeneralForumTopicConfig) result() (_ bool)           { return }
+func (ReplaceStickerInSetConfig) result() (_ bool)               { return }
+func (RestrictChatMemberConfig) result() (_ bool)                { return }
+func (SendChatJoinRequestWebAppConfig) result() (_ bool)         { return }
+func (SendGiftConfig) result() (_ bool)                          { return }
+func (SendMessageDraftConfig) result() (_ bool)                  { return }
+func (SendRichMessageDraftConfig) result() (_ bool)              { return }
+func (SetBusinessAccountBioConfig) result() (_ bool)             { return }
+func (SetBusinessAccountGiftSettingsConfig) result() (_ bool)    { return }
+func (SetBusinessAccountNameConfig) result() (_ bool)            { return }
+func (SetBusinessAccountProfilePhotoConfig) result() (_ bool)    { return }
+func (SetBusinessAccountUsernameConfig) result() (_ bool)        { return }
+func (SetChatAdministratorCustomTitle) result() (_ bool)         { return }
+func (SetChatDescriptionConfig) result() (_ bool)                { return }
+func (SetChatMemberTagConfig) result() (_ bool)                  { return }
+func (SetChatMenuButtonConfig) result() (_ bool)                 { return }
+func (SetChatPermissionsConfig) result() (_ bool)                { return }
+func (SetChatPhotoConfig) result() (_ bool)                      { return }
+func (SetChatStickerSetConfig) result() (_ bool)                 { return }
+func (SetChatTitleConfig) result() (_ bool)                      { return }
+func (SetCustomEmojiStickerSetThumbnailConfig) result() (_ bool) { return }
+func (SetManagedBotAccessSettingsConfig) result() (_ bool)       { return }
+func (SetMessageReactionConfig) result() (_ bool)                { return }
+func (SetMyCommandsConfig) result() (_ bool)                     { return }
+func (SetMyDefaultAdministratorRightsConfig) result() (_ bool)   { return }
+func (SetMyDescriptionConfig) result() (_ bool)                  { return }
+func (SetMyNameConfig) result() (_ bool)                         { return }
+func (SetMyProfilePhotoConfig) result() (_ bool)                 { return }
+func (SetMyShortDescriptionConfig) result() (_ bool)             { return }
+func (SetPassportDataErrorsConfig) result() (_ bool)             { return }
+func (SetStickerEmojiListConfig) result() (_ bool)               { return }
+func (SetStickerKeywordsConfig) result() (_ bool)                { return }
+func (SetStickerMaskPositionConfig) result() (_ bool)            { return }
+func (SetStickerPositionConfig) result() (_ bool)                { return }
+func (SetStickerSetThumbConfig) result() (_ bool)                { return }
+func (SetStickerSetTitleConfig) result() (_ bool)                { return }
+func (SetUserEmojiStatusConfig) result() (_ bool)                { return }
+func (ShippingConfig) result() (_ bool)                          { return }
+func (TransferBusinessAccountStarsConfig) result() (_ bool)      { return }
+func (TransferGiftConfig) result() (_ bool)                      { return }
+func (UnbanChatMemberConfig) result() (_ bool)                   { return }
+func (UnbanChatSenderChatConfig) result() (_ bool)               { return }
+func (UnhideGeneralForumTopicConfig) result() (_ bool)           { return }
+func (UnpinAllChatMessagesConfig) result() (_ bool)              { return }
+func (UnpinAllForumTopicMessagesConfig) result() (_ bool)        { return }
+func (UnpinAllGeneralForumTopicMessagesConfig) result() (_ bool) { return }
+func (UnpinChatMessageConfig) result() (_ bool)                  { return }
+func (UpgradeGiftConfig) result() (_ bool)                       { return }
+func (VerifyChatConfig) result() (_ bool)                        { return }
+func (VerifyUserConfig) result() (_ bool)                        { return }
+func (WebhookConfig) result() (_ bool)                           { return }
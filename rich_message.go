@@ -0,0 +1,1306 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Constant values for rich text types
+const (
+	RichTextTypeBold                   = "bold"
+	RichTextTypeItalic                 = "italic"
+	RichTextTypeUnderline              = "underline"
+	RichTextTypeStrikethrough          = "strikethrough"
+	RichTextTypeSpoiler                = "spoiler"
+	RichTextTypeDateTime               = "date_time"
+	RichTextTypeTextMention            = "text_mention"
+	RichTextTypeSubscript              = "subscript"
+	RichTextTypeSuperscript            = "superscript"
+	RichTextTypeMarked                 = "marked"
+	RichTextTypeCode                   = "code"
+	RichTextTypeCustomEmoji            = "custom_emoji"
+	RichTextTypeMathematicalExpression = "mathematical_expression"
+	RichTextTypeURL                    = "url"
+	RichTextTypeEmailAddress           = "email_address"
+	RichTextTypePhoneNumber            = "phone_number"
+	RichTextTypeBankCardNumber         = "bank_card_number"
+	RichTextTypeMention                = "mention"
+	RichTextTypeHashtag                = "hashtag"
+	RichTextTypeCashtag                = "cashtag"
+	RichTextTypeBotCommand             = "bot_command"
+	RichTextTypeAnchor                 = "anchor"
+	RichTextTypeAnchorLink             = "anchor_link"
+	RichTextTypeReference              = "reference"
+	RichTextTypeReferenceLink          = "reference_link"
+)
+
+// Constant values for rich block types
+const (
+	RichBlockTypeParagraph              = "paragraph"
+	RichBlockTypeSectionHeading         = "heading"
+	RichBlockTypePreformatted           = "preformatted"
+	RichBlockTypeFooter                 = "footer"
+	RichBlockTypeDivider                = "divider"
+	RichBlockTypeMathematicalExpression = "mathematical_expression"
+	RichBlockTypeAnchor                 = "anchor"
+	RichBlockTypeList                   = "list"
+	RichBlockTypeBlockQuotation         = "block_quotation"
+	RichBlockTypePullQuotation          = "pull_quotation"
+	RichBlockTypeCollage                = "collage"
+	RichBlockTypeSlideshow              = "slideshow"
+	RichBlockTypeTable                  = "table"
+	RichBlockTypeDetails                = "details"
+	RichBlockTypeMap                    = "map"
+	RichBlockTypeAnimation              = "animation"
+	RichBlockTypeAudio                  = "audio"
+	RichBlockTypePhoto                  = "photo"
+	RichBlockTypeVideo                  = "video"
+	RichBlockTypeVoiceNote              = "voice_note"
+	RichBlockTypeThinking               = "thinking"
+)
+
+var richTextTypes = map[string]reflect.Type{
+	RichTextTypeBold:                   reflect.TypeFor[RichTextBold](),
+	RichTextTypeItalic:                 reflect.TypeFor[RichTextItalic](),
+	RichTextTypeUnderline:              reflect.TypeFor[RichTextUnderline](),
+	RichTextTypeStrikethrough:          reflect.TypeFor[RichTextStrikethrough](),
+	RichTextTypeSpoiler:                reflect.TypeFor[RichTextSpoiler](),
+	RichTextTypeDateTime:               reflect.TypeFor[RichTextDateTime](),
+	RichTextTypeTextMention:            reflect.TypeFor[RichTextTextMention](),
+	RichTextTypeSubscript:              reflect.TypeFor[RichTextSubscript](),
+	RichTextTypeSuperscript:            reflect.TypeFor[RichTextSuperscript](),
+	RichTextTypeMarked:                 reflect.TypeFor[RichTextMarked](),
+	RichTextTypeCode:                   reflect.TypeFor[RichTextCode](),
+	RichTextTypeCustomEmoji:            reflect.TypeFor[RichTextCustomEmoji](),
+	RichTextTypeMathematicalExpression: reflect.TypeFor[RichTextMathematicalExpression](),
+	RichTextTypeURL:                    reflect.TypeFor[RichTextUrl](),
+	RichTextTypeEmailAddress:           reflect.TypeFor[RichTextEmailAddress](),
+	RichTextTypePhoneNumber:            reflect.TypeFor[RichTextPhoneNumber](),
+	RichTextTypeBankCardNumber:         reflect.TypeFor[RichTextBankCardNumber](),
+	RichTextTypeMention:                reflect.TypeFor[RichTextMention](),
+	RichTextTypeHashtag:                reflect.TypeFor[RichTextHashtag](),
+	RichTextTypeCashtag:                reflect.TypeFor[RichTextCashtag](),
+	RichTextTypeBotCommand:             reflect.TypeFor[RichTextBotCommand](),
+	RichTextTypeAnchor:                 reflect.TypeFor[RichTextAnchor](),
+	RichTextTypeAnchorLink:             reflect.TypeFor[RichTextAnchorLink](),
+	RichTextTypeReference:              reflect.TypeFor[RichTextReference](),
+	RichTextTypeReferenceLink:          reflect.TypeFor[RichTextReferenceLink](),
+}
+
+var richBlockTypes = map[string]reflect.Type{
+	RichBlockTypeParagraph:              reflect.TypeFor[RichBlockParagraph](),
+	RichBlockTypeSectionHeading:         reflect.TypeFor[RichBlockSectionHeading](),
+	RichBlockTypePreformatted:           reflect.TypeFor[RichBlockPreformatted](),
+	RichBlockTypeFooter:                 reflect.TypeFor[RichBlockFooter](),
+	RichBlockTypeDivider:                reflect.TypeFor[RichBlockDivider](),
+	RichBlockTypeMathematicalExpression: reflect.TypeFor[RichBlockMathematicalExpression](),
+	RichBlockTypeAnchor:                 reflect.TypeFor[RichBlockAnchor](),
+	RichBlockTypeList:                   reflect.TypeFor[RichBlockList](),
+	RichBlockTypeBlockQuotation:         reflect.TypeFor[RichBlockBlockQuotation](),
+	RichBlockTypePullQuotation:          reflect.TypeFor[RichBlockPullQuotation](),
+	RichBlockTypeCollage:                reflect.TypeFor[RichBlockCollage](),
+	RichBlockTypeSlideshow:              reflect.TypeFor[RichBlockSlideshow](),
+	RichBlockTypeTable:                  reflect.TypeFor[RichBlockTable](),
+	RichBlockTypeDetails:                reflect.TypeFor[RichBlockDetails](),
+	RichBlockTypeMap:                    reflect.TypeFor[RichBlockMap](),
+	RichBlockTypeAnimation:              reflect.TypeFor[RichBlockAnimation](),
+	RichBlockTypeAudio:                  reflect.TypeFor[RichBlockAudio](),
+	RichBlockTypePhoto:                  reflect.TypeFor[RichBlockPhoto](),
+	RichBlockTypeVideo:                  reflect.TypeFor[RichBlockVideo](),
+	RichBlockTypeVoiceNote:              reflect.TypeFor[RichBlockVoiceNote](),
+	RichBlockTypeThinking:               reflect.TypeFor[RichBlockThinking](),
+}
+
+var (
+	richTextInterface  = reflect.TypeFor[RichText]()
+	richBlockInterface = reflect.TypeFor[RichBlock]()
+)
+
+// UnmarshalJSON decodes the message blocks into their concrete RichBlock types.
+func (m *RichMessage) UnmarshalJSON(data []byte) error {
+	type richMessageAlias RichMessage
+	aux := struct {
+		*richMessageAlias
+		Blocks []json.RawMessage `json:"blocks"`
+	}{
+		richMessageAlias: (*richMessageAlias)(m),
+	}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	m.Blocks = nil
+	for _, raw := range aux.Blocks {
+		block, err := DecodeRichBlock(raw)
+		if err != nil {
+			return err
+		}
+		m.Blocks = append(m.Blocks, block)
+	}
+
+	return nil
+}
+
+// DecodeRichText decodes a JSON rich text value.
+//
+// Plain strings are returned as string, arrays as []RichText and objects as
+// the concrete RichText* struct matching their type. Objects of unknown type
+// are returned as map[string]any.
+func DecodeRichText(data []byte) (RichText, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty rich text")
+	}
+
+	switch data[0] {
+	case '"':
+		var text string
+		err := json.Unmarshal(data, &text)
+		return text, err
+	case '[':
+		var raws []json.RawMessage
+		if err := json.Unmarshal(data, &raws); err != nil {
+			return nil, err
+		}
+		texts := make([]RichText, 0, len(raws))
+		for _, raw := range raws {
+			text, err := DecodeRichText(raw)
+			if err != nil {
+				return nil, err
+			}
+			texts = append(texts, text)
+		}
+		return texts, nil
+	case '{':
+		return decodeRichObject(data, richTextTypes)
+	case 'n':
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected rich text value %s", data)
+	}
+}
+
+// DecodeRichBlock decodes a JSON rich block into the concrete RichBlock* struct
+// matching its type. Blocks of unknown type are returned as map[string]any.
+func DecodeRichBlock(data []byte) (RichBlock, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("empty rich block")
+	}
+
+	switch data[0] {
+	case '{':
+		return decodeRichObject(data, richBlockTypes)
+	case 'n':
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected rich block value %s", data)
+	}
+}
+
+func decodeRichObject(data []byte, types map[string]reflect.Type) (any, error) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+
+	typ, ok := types[head.Type]
+	if !ok {
+		var generic map[string]any
+		err := json.Unmarshal(data, &generic)
+		return generic, err
+	}
+
+	value := reflect.New(typ)
+	if err := json.Unmarshal(data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("decode rich %s: %w", head.Type, err)
+	}
+	if err := resolveRichValues(value.Elem()); err != nil {
+		return nil, err
+	}
+
+	return value.Elem().Interface(), nil
+}
+
+// resolveRichValues replaces the generic values the json package stores in
+// RichText and RichBlock fields with their concrete types.
+func resolveRichValues(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+
+		var decode func([]byte) (any, error)
+		switch v.Type() {
+		case richTextInterface:
+			decode = func(data []byte) (any, error) { return DecodeRichText(data) }
+		case richBlockInterface:
+			decode = func(data []byte) (any, error) { return DecodeRichBlock(data) }
+		default:
+			return nil
+		}
+
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return err
+		}
+		decoded, err := decode(data)
+		if err != nil {
+			return err
+		}
+		if decoded == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		v.Set(reflect.ValueOf(decoded))
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := resolveRichValues(v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			if err := resolveRichValues(v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return resolveRichValues(v.Elem())
+		}
+	}
+
+	return nil
+}
+
+// RichMessageBuilder composes a RichMessage block by block.
+//
+// Build validates the result, and InputRichMessage renders it to HTML so it
+// can be used with SendRichMessageConfig or SendRichMessageDraftConfig.
+type RichMessageBuilder struct {
+	message RichMessage
+}
+
+// NewRichMessageBuilder creates an empty RichMessageBuilder.
+func NewRichMessageBuilder() *RichMessageBuilder {
+	return &RichMessageBuilder{}
+}
+
+// RTL marks the message as written in a right-to-left language.
+func (b *RichMessageBuilder) RTL(isRTL bool) *RichMessageBuilder {
+	b.message.IsRTL = isRTL
+	return b
+}
+
+// Block appends an arbitrary block.
+func (b *RichMessageBuilder) Block(blocks ...RichBlock) *RichMessageBuilder {
+	b.message.Blocks = append(b.message.Blocks, blocks...)
+	return b
+}
+
+// Paragraph appends a paragraph made of the given text parts.
+func (b *RichMessageBuilder) Paragraph(text ...RichText) *RichMessageBuilder {
+	return b.Block(NewRichBlockParagraph(text...))
+}
+
+// Heading appends a section heading of the given size.
+func (b *RichMessageBuilder) Heading(size int, text ...RichText) *RichMessageBuilder {
+	return b.Block(NewRichBlockSectionHeading(size, text...))
+}
+
+// Preformatted appends a preformatted block, language may be empty.
+func (b *RichMessageBuilder) Preformatted(language string, text ...RichText) *RichMessageBuilder {
+	return b.Block(NewRichBlockPreformatted(language, text...))
+}
+
+// Footer appends a footer block.
+func (b *RichMessageBuilder) Footer(text ...RichText) *RichMessageBuilder {
+	return b.Block(NewRichBlockFooter(text...))
+}
+
+// Divider appends a divider block.
+func (b *RichMessageBuilder) Divider() *RichMessageBuilder {
+	return b.Block(NewRichBlockDivider())
+}
+
+// List appends a list block.
+func (b *RichMessageBuilder) List(items ...RichBlockListItem) *RichMessageBuilder {
+	return b.Block(NewRichBlockList(items...))
+}
+
+// Quote appends a block quotation.
+func (b *RichMessageBuilder) Quote(blocks ...RichBlock) *RichMessageBuilder {
+	return b.Block(NewRichBlockBlockQuotation(blocks...))
+}
+
+// Table appends a table block.
+func (b *RichMessageBuilder) Table(rows ...[]RichBlockTableCell) *RichMessageBuilder {
+	return b.Block(NewRichBlockTable(rows...))
+}
+
+// Details appends an expandable details block.
+func (b *RichMessageBuilder) Details(summary RichText, blocks ...RichBlock) *RichMessageBuilder {
+	return b.Block(NewRichBlockDetails(summary, blocks...))
+}
+
+// Build validates and returns the composed message.
+func (b *RichMessageBuilder) Build() (RichMessage, error) {
+	message := RichMessage{
+		Blocks: append([]RichBlock(nil), b.message.Blocks...),
+		IsRTL:  b.message.IsRTL,
+	}
+
+	return message, message.Validate()
+}
+
+// InputRichMessage validates the composed message and renders it as
+// HTML input ready to be sent. Messages with media, map or thinking blocks,
+// which HTML input cannot represent, are rejected.
+func (b *RichMessageBuilder) InputRichMessage() (InputRichMessage, error) {
+	message, err := b.Build()
+	if err != nil {
+		return InputRichMessage{}, err
+	}
+	if types := htmlUnsupportedBlocks(message.Blocks); len(types) > 0 {
+		return InputRichMessage{}, fmt.Errorf("rich message: %s blocks cannot be sent as HTML", strings.Join(types, ", "))
+	}
+
+	input := NewInputRichMessageHTML(message.HTML())
+	input.IsRTL = message.IsRTL
+
+	return input, nil
+}
+
+// Validate checks the message against the nesting rules for rich messages:
+//
+//   - every block and text object is of a known type with a matching Type field;
+//   - section heading sizes are between 1 and 6;
+//   - collages and slideshows are not empty and contain only photo, video and
+//     animation blocks;
+//   - thinking blocks only appear at the top level;
+//   - list items contain at least one block, tables at least one row and
+//     table cell alignment values are valid;
+//   - code and mathematical expressions contain plain text only, and links
+//     are not nested in other links;
+//   - anchor and reference links point to an anchor or reference in the message.
+//
+// All violations are reported together, each prefixed with its path.
+func (m RichMessage) Validate() error {
+	v := richValidator{
+		anchors:    map[string]bool{},
+		references: map[string]bool{},
+	}
+	walkRichBlocks(m.Blocks, v.collect)
+
+	for i, block := range m.Blocks {
+		v.block(fmt.Sprintf("blocks[%d]", i), block, true)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+type richValidator struct {
+	anchors    map[string]bool
+	references map[string]bool
+	errs       []error
+}
+
+func (v *richValidator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (v *richValidator) collect(node any) {
+	switch n := node.(type) {
+	case RichTextAnchor:
+		v.anchors[n.Name] = true
+	case RichBlockAnchor:
+		v.anchors[n.Name] = true
+	case RichTextReference:
+		v.references[n.Name] = true
+	}
+}
+
+func (v *richValidator) checkType(path, got, want string) {
+	if got != want {
+		v.fail(path, "type is %q, expected %q", got, want)
+	}
+}
+
+func (v *richValidator) blocks(path string, blocks []RichBlock) {
+	for i, block := range blocks {
+		v.block(fmt.Sprintf("%s.blocks[%d]", path, i), block, false)
+	}
+}
+
+func (v *richValidator) caption(path string, caption *RichBlockCaption) {
+	if caption == nil {
+		return
+	}
+	v.text(path+".caption.text", caption.Text, false)
+	v.text(path+".caption.credit", caption.Credit, false)
+}
+
+func (v *richValidator) block(path string, block RichBlock, topLevel bool) {
+	switch b := block.(type) {
+	case RichBlockParagraph:
+		v.checkType(path, b.Type, RichBlockTypeParagraph)
+		v.text(path+".text", b.Text, false)
+	case RichBlockSectionHeading:
+		v.checkType(path, b.Type, RichBlockTypeSectionHeading)
+		if b.Size < 1 || b.Size > 6 {
+			v.fail(path, "heading size %d is out of range 1-6", b.Size)
+		}
+		v.text(path+".text", b.Text, false)
+	case RichBlockPreformatted:
+		v.checkType(path, b.Type, RichBlockTypePreformatted)
+		v.plainText(path+".text", b.Text)
+	case RichBlockFooter:
+		v.checkType(path, b.Type, RichBlockTypeFooter)
+		v.text(path+".text", b.Text, false)
+	case RichBlockDivider:
+		v.checkType(path, b.Type, RichBlockTypeDivider)
+	case RichBlockMathematicalExpression:
+		v.checkType(path, b.Type, RichBlockTypeMathematicalExpression)
+		if b.Expression == "" {
+			v.fail(path, "expression is empty")
+		}
+	case RichBlockAnchor:
+		v.checkType(path, b.Type, RichBlockTypeAnchor)
+		if b.Name == "" {
+			v.fail(path, "anchor name is empty")
+		}
+	case RichBlockList:
+		v.checkType(path, b.Type, RichBlockTypeList)
+		if len(b.Items) == 0 {
+			v.fail(path, "list has no items")
+		}
+		for i, item := range b.Items {
+			itemPath := fmt.Sprintf("%s.items[%d]", path, i)
+			if len(item.Blocks) == 0 {
+				v.fail(itemPath, "list item has no blocks")
+			}
+			v.blocks(itemPath, item.Blocks)
+		}
+	case RichBlockBlockQuotation:
+		v.checkType(path, b.Type, RichBlockTypeBlockQuotation)
+		v.blocks(path, b.Blocks)
+		v.text(path+".credit", b.Credit, false)
+	case RichBlockPullQuotation:
+		v.checkType(path, b.Type, RichBlockTypePullQuotation)
+		v.text(path+".text", b.Text, false)
+		v.text(path+".credit", b.Credit, false)
+	case RichBlockCollage:
+		v.checkType(path, b.Type, RichBlockTypeCollage)
+		v.mediaBlocks(path, "collage", b.Blocks)
+		v.caption(path, b.Caption)
+	case RichBlockSlideshow:
+		v.checkType(path, b.Type, RichBlockTypeSlideshow)
+		v.mediaBlocks(path, "slideshow", b.Blocks)
+		v.caption(path, b.Caption)
+	case RichBlockTable:
+		v.checkType(path, b.Type, RichBlockTypeTable)
+		v.table(path, b)
+	case RichBlockDetails:
+		v.checkType(path, b.Type, RichBlockTypeDetails)
+		v.text(path+".summary", b.Summary, false)
+		v.blocks(path, b.Blocks)
+	case RichBlockMap:
+		v.checkType(path, b.Type, RichBlockTypeMap)
+		v.caption(path, b.Caption)
+	case RichBlockAnimation:
+		v.checkType(path, b.Type, RichBlockTypeAnimation)
+		v.caption(path, b.Caption)
+	case RichBlockAudio:
+		v.checkType(path, b.Type, RichBlockTypeAudio)
+		v.caption(path, b.Caption)
+	case RichBlockPhoto:
+		v.checkType(path, b.Type, RichBlockTypePhoto)
+		v.caption(path, b.Caption)
+	case RichBlockVideo:
+		v.checkType(path, b.Type, RichBlockTypeVideo)
+		v.caption(path, b.Caption)
+	case RichBlockVoiceNote:
+		v.checkType(path, b.Type, RichBlockTypeVoiceNote)
+		v.caption(path, b.Caption)
+	case RichBlockThinking:
+		v.checkType(path, b.Type, RichBlockTypeThinking)
+		if !topLevel {
+			v.fail(path, "thinking blocks are only allowed at the top level")
+		}
+		v.text(path+".text", b.Text, false)
+	case nil:
+		v.fail(path, "block is nil")
+	default:
+		v.fail(path, "unsupported block %T", block)
+	}
+}
+
+func (v *richValidator) mediaBlocks(path, kind string, blocks []RichBlock) {
+	if len(blocks) == 0 {
+		v.fail(path, "%s has no blocks", kind)
+	}
+	for i, block := range blocks {
+		switch block.(type) {
+		case RichBlockPhoto, RichBlockVideo, RichBlockAnimation:
+			v.block(fmt.Sprintf("%s.blocks[%d]", path, i), block, false)
+		default:
+			v.fail(fmt.Sprintf("%s.blocks[%d]", path, i), "%s may only contain photo, video and animation blocks, got %T", kind, block)
+		}
+	}
+}
+
+func (v *richValidator) table(path string, table RichBlockTable) {
+	if len(table.Cells) == 0 {
+		v.fail(path, "table has no rows")
+	}
+	for r, row := range table.Cells {
+		for c, cell := range row {
+			cellPath := fmt.Sprintf("%s.cells[%d][%d]", path, r, c)
+			if cell.Colspan < 0 || cell.Rowspan < 0 {
+				v.fail(cellPath, "colspan and rowspan must not be negative")
+			}
+			switch cell.Align {
+			case "", "left", "center", "right":
+			default:
+				v.fail(cellPath, "invalid align %q", cell.Align)
+			}
+			switch cell.Valign {
+			case "", "top", "middle", "bottom":
+			default:
+				v.fail(cellPath, "invalid valign %q", cell.Valign)
+			}
+			v.text(cellPath+".text", cell.Text, false)
+		}
+	}
+	v.text(path+".caption", table.Caption, false)
+}
+
+func (v *richValidator) plainText(path string, text RichText) {
+	switch t := text.(type) {
+	case nil, string:
+	case []RichText:
+		for i, part := range t {
+			v.plainText(fmt.Sprintf("%s[%d]", path, i), part)
+		}
+	default:
+		v.fail(path, "only plain text is allowed here, got %T", text)
+	}
+}
+
+func (v *richValidator) text(path string, text RichText, inLink bool) {
+	link := func(kind string) {
+		if inLink {
+			v.fail(path, "%s cannot be nested in another link", kind)
+		}
+	}
+
+	switch t := text.(type) {
+	case nil, string:
+	case []RichText:
+		for i, part := range t {
+			v.text(fmt.Sprintf("%s[%d]", path, i), part, inLink)
+		}
+	case RichTextBold:
+		v.checkType(path, t.Type, RichTextTypeBold)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextItalic:
+		v.checkType(path, t.Type, RichTextTypeItalic)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextUnderline:
+		v.checkType(path, t.Type, RichTextTypeUnderline)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextStrikethrough:
+		v.checkType(path, t.Type, RichTextTypeStrikethrough)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextSpoiler:
+		v.checkType(path, t.Type, RichTextTypeSpoiler)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextDateTime:
+		v.checkType(path, t.Type, RichTextTypeDateTime)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextSubscript:
+		v.checkType(path, t.Type, RichTextTypeSubscript)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextSuperscript:
+		v.checkType(path, t.Type, RichTextTypeSuperscript)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextMarked:
+		v.checkType(path, t.Type, RichTextTypeMarked)
+		v.text(path+".text", t.Text, inLink)
+	case RichTextCode:
+		v.checkType(path, t.Type, RichTextTypeCode)
+		v.plainText(path+".text", t.Text)
+	case RichTextCustomEmoji:
+		v.checkType(path, t.Type, RichTextTypeCustomEmoji)
+		if t.CustomEmojiID == "" {
+			v.fail(path, "custom emoji ID is empty")
+		}
+	case RichTextMathematicalExpression:
+		v.checkType(path, t.Type, RichTextTypeMathematicalExpression)
+		if t.Expression == "" {
+			v.fail(path, "expression is empty")
+		}
+	case RichTextAnchor:
+		v.checkType(path, t.Type, RichTextTypeAnchor)
+		if t.Name == "" {
+			v.fail(path, "anchor name is empty")
+		}
+	case RichTextReference:
+		v.checkType(path, t.Type, RichTextTypeReference)
+		if t.Name == "" {
+			v.fail(path, "reference name is empty")
+		}
+		v.text(path+".text", t.Text, inLink)
+	case RichTextTextMention:
+		v.checkType(path, t.Type, RichTextTypeTextMention)
+		link("text mention")
+		v.text(path+".text", t.Text, true)
+	case RichTextUrl:
+		v.checkType(path, t.Type, RichTextTypeURL)
+		link("url")
+		if t.URL == "" {
+			v.fail(path, "url is empty")
+		}
+		v.text(path+".text", t.Text, true)
+	case RichTextEmailAddress:
+		v.checkType(path, t.Type, RichTextTypeEmailAddress)
+		link("email address")
+		v.text(path+".text", t.Text, true)
+	case RichTextPhoneNumber:
+		v.checkType(path, t.Type, RichTextTypePhoneNumber)
+		link("phone number")
+		v.text(path+".text", t.Text, true)
+	case RichTextBankCardNumber:
+		v.checkType(path, t.Type, RichTextTypeBankCardNumber)
+		link("bank card number")
+		v.text(path+".text", t.Text, true)
+	case RichTextMention:
+		v.checkType(path, t.Type, RichTextTypeMention)
+		link("mention")
+		v.text(path+".text", t.Text, true)
+	case RichTextHashtag:
+		v.checkType(path, t.Type, RichTextTypeHashtag)
+		link("hashtag")
+		v.text(path+".text", t.Text, true)
+	case RichTextCashtag:
+		v.checkType(path, t.Type, RichTextTypeCashtag)
+		link("cashtag")
+		v.text(path+".text", t.Text, true)
+	case RichTextBotCommand:
+		v.checkType(path, t.Type, RichTextTypeBotCommand)
+		link("bot command")
+		v.text(path+".text", t.Text, true)
+	case RichTextAnchorLink:
+		v.checkType(path, t.Type, RichTextTypeAnchorLink)
+		link("anchor link")
+		if !v.anchors[t.AnchorName] {
+			v.fail(path, "anchor %q does not exist", t.AnchorName)
+		}
+		v.text(path+".text", t.Text, true)
+	case RichTextReferenceLink:
+		v.checkType(path, t.Type, RichTextTypeReferenceLink)
+		link("reference link")
+		if !v.references[t.ReferenceName] {
+			v.fail(path, "reference %q does not exist", t.ReferenceName)
+		}
+		v.text(path+".text", t.Text, true)
+	default:
+		v.fail(path, "unsupported rich text %T", text)
+	}
+}
+
+// walkRichBlocks calls fn for every block and rich text value reachable from blocks.
+func walkRichBlocks(blocks []RichBlock, fn func(any)) {
+	for _, block := range blocks {
+		walkRichBlock(block, fn)
+	}
+}
+
+func walkRichBlock(block RichBlock, fn func(any)) {
+	fn(block)
+
+	captioned := func(caption *RichBlockCaption) {
+		if caption != nil {
+			walkRichText(caption.Text, fn)
+			walkRichText(caption.Credit, fn)
+		}
+	}
+
+	switch b := block.(type) {
+	case RichBlockParagraph:
+		walkRichText(b.Text, fn)
+	case RichBlockSectionHeading:
+		walkRichText(b.Text, fn)
+	case RichBlockPreformatted:
+		walkRichText(b.Text, fn)
+	case RichBlockFooter:
+		walkRichText(b.Text, fn)
+	case RichBlockList:
+		for _, item := range b.Items {
+			walkRichBlocks(item.Blocks, fn)
+		}
+	case RichBlockBlockQuotation:
+		walkRichBlocks(b.Blocks, fn)
+		walkRichText(b.Credit, fn)
+	case RichBlockPullQuotation:
+		walkRichText(b.Text, fn)
+		walkRichText(b.Credit, fn)
+	case RichBlockCollage:
+		walkRichBlocks(b.Blocks, fn)
+		captioned(b.Caption)
+	case RichBlockSlideshow:
+		walkRichBlocks(b.Blocks, fn)
+		captioned(b.Caption)
+	case RichBlockTable:
+		for _, row := range b.Cells {
+			for _, cell := range row {
+				walkRichText(cell.Text, fn)
+			}
+		}
+		walkRichText(b.Caption, fn)
+	case RichBlockDetails:
+		walkRichText(b.Summary, fn)
+		walkRichBlocks(b.Blocks, fn)
+	case RichBlockMap:
+		captioned(b.Caption)
+	case RichBlockAnimation:
+		captioned(b.Caption)
+	case RichBlockAudio:
+		captioned(b.Caption)
+	case RichBlockPhoto:
+		captioned(b.Caption)
+	case RichBlockVideo:
+		captioned(b.Caption)
+	case RichBlockVoiceNote:
+		captioned(b.Caption)
+	case RichBlockThinking:
+		walkRichText(b.Text, fn)
+	}
+}
+
+func walkRichText(text RichText, fn func(any)) {
+	if text == nil {
+		return
+	}
+	fn(text)
+
+	if parts, ok := text.([]RichText); ok {
+		for _, part := range parts {
+			walkRichText(part, fn)
+		}
+		return
+	}
+	if inner, ok := richTextInner(text); ok {
+		walkRichText(inner, fn)
+	}
+}
+
+// richTextInner returns the nested text of a formatting rich text value.
+func richTextInner(text RichText) (RichText, bool) {
+	switch t := text.(type) {
+	case RichTextBold:
+		return t.Text, true
+	case RichTextItalic:
+		return t.Text, true
+	case RichTextUnderline:
+		return t.Text, true
+	case RichTextStrikethrough:
+		return t.Text, true
+	case RichTextSpoiler:
+		return t.Text, true
+	case RichTextDateTime:
+		return t.Text, true
+	case RichTextTextMention:
+		return t.Text, true
+	case RichTextSubscript:
+		return t.Text, true
+	case RichTextSuperscript:
+		return t.Text, true
+	case RichTextMarked:
+		return t.Text, true
+	case RichTextCode:
+		return t.Text, true
+	case RichTextUrl:
+		return t.Text, true
+	case RichTextEmailAddress:
+		return t.Text, true
+	case RichTextPhoneNumber:
+		return t.Text, true
+	case RichTextBankCardNumber:
+		return t.Text, true
+	case RichTextMention:
+		return t.Text, true
+	case RichTextHashtag:
+		return t.Text, true
+	case RichTextCashtag:
+		return t.Text, true
+	case RichTextBotCommand:
+		return t.Text, true
+	case RichTextAnchorLink:
+		return t.Text, true
+	case RichTextReference:
+		return t.Text, true
+	case RichTextReferenceLink:
+		return t.Text, true
+	}
+	return nil, false
+}
+
+// PlainText renders the message as plain text, suitable for logs and
+// notification previews. Media blocks are rendered as bracketed placeholders.
+func (m RichMessage) PlainText() string {
+	var sb strings.Builder
+	writePlainBlocks(&sb, m.Blocks, "")
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// HTML renders the message as HTML. The output of messages built from text
+// blocks can be sent back with NewInputRichMessageHTML; media blocks are
+// rendered as figures containing only their caption.
+func (m RichMessage) HTML() string {
+	var sb strings.Builder
+	for _, block := range m.Blocks {
+		writeHTMLBlock(&sb, block)
+	}
+	return sb.String()
+}
+
+// htmlUnsupportedBlocks returns the types of the blocks HTML renders without
+// their content, in order of appearance.
+func htmlUnsupportedBlocks(blocks []RichBlock) []string {
+	var types []string
+	walkRichBlocks(blocks, func(node any) {
+		var typ string
+		switch node.(type) {
+		case RichBlockMap:
+			typ = RichBlockTypeMap
+		case RichBlockAnimation:
+			typ = RichBlockTypeAnimation
+		case RichBlockAudio:
+			typ = RichBlockTypeAudio
+		case RichBlockPhoto:
+			typ = RichBlockTypePhoto
+		case RichBlockVideo:
+			typ = RichBlockTypeVideo
+		case RichBlockVoiceNote:
+			typ = RichBlockTypeVoiceNote
+		case RichBlockThinking:
+			typ = RichBlockTypeThinking
+		}
+		if typ != "" && !slices.Contains(types, typ) {
+			types = append(types, typ)
+		}
+	})
+	return types
+}
+
+// RichTextPlain returns the plain text content of a rich text value.
+func RichTextPlain(text RichText) string {
+	var sb strings.Builder
+	writePlainText(&sb, text)
+	return sb.String()
+}
+
+// RichTextHTML renders a rich text value as HTML.
+func RichTextHTML(text RichText) string {
+	var sb strings.Builder
+	writeHTMLText(&sb, text)
+	return sb.String()
+}
+
+func writePlainText(sb *strings.Builder, text RichText) {
+	switch t := text.(type) {
+	case nil:
+	case string:
+		sb.WriteString(t)
+	case []RichText:
+		for _, part := range t {
+			writePlainText(sb, part)
+		}
+	case RichTextCustomEmoji:
+		sb.WriteString(t.AlternativeText)
+	case RichTextMathematicalExpression:
+		sb.WriteString(t.Expression)
+	case map[string]any:
+		if s, ok := t["text"].(string); ok {
+			sb.WriteString(s)
+		}
+	default:
+		if inner, ok := richTextInner(text); ok {
+			writePlainText(sb, inner)
+		}
+	}
+}
+
+func writePlainLine(sb *strings.Builder, indent, line string) {
+	for i, l := range strings.Split(line, "\n") {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(indent)
+		sb.WriteString(l)
+	}
+	sb.WriteString("\n\n")
+}
+
+func writePlainCaption(sb *strings.Builder, indent, placeholder string, caption *RichBlockCaption) {
+	line := "[" + placeholder + "]"
+	if caption != nil {
+		if text := RichTextPlain(caption.Text); text != "" {
+			line += " " + text
+		}
+		if credit := RichTextPlain(caption.Credit); credit != "" {
+			line += " — " + credit
+		}
+	}
+	writePlainLine(sb, indent, line)
+}
+
+func writePlainBlocks(sb *strings.Builder, blocks []RichBlock, indent string) {
+	for _, block := range blocks {
+		writePlainBlock(sb, block, indent)
+	}
+}
+
+func writePlainBlock(sb *strings.Builder, block RichBlock, indent string) {
+	switch b := block.(type) {
+	case RichBlockParagraph:
+		writePlainLine(sb, indent, RichTextPlain(b.Text))
+	case RichBlockSectionHeading:
+		writePlainLine(sb, indent, RichTextPlain(b.Text))
+	case RichBlockPreformatted:
+		writePlainLine(sb, indent, RichTextPlain(b.Text))
+	case RichBlockFooter:
+		writePlainLine(sb, indent, RichTextPlain(b.Text))
+	case RichBlockThinking:
+		writePlainLine(sb, indent, RichTextPlain(b.Text))
+	case RichBlockDivider:
+		writePlainLine(sb, indent, "———")
+	case RichBlockMathematicalExpression:
+		writePlainLine(sb, indent, b.Expression)
+	case RichBlockList:
+		for _, item := range b.Items {
+			marker := item.Label
+			if marker == "" {
+				marker = "•"
+			}
+			if item.HasCheckbox {
+				if item.IsChecked {
+					marker += " [x]"
+				} else {
+					marker += " [ ]"
+				}
+			}
+
+			var inner strings.Builder
+			writePlainBlocks(&inner, item.Blocks, "")
+			pad := strings.Repeat(" ", utf8.RuneCountInString(marker)+1)
+			for i, line := range strings.Split(strings.TrimRight(inner.String(), "\n"), "\n") {
+				sb.WriteString(indent)
+				switch {
+				case i == 0:
+					sb.WriteString(marker + " ")
+				case line != "":
+					sb.WriteString(pad)
+				}
+				sb.WriteString(line)
+				sb.WriteByte('\n')
+			}
+		}
+		sb.WriteByte('\n')
+	case RichBlockBlockQuotation:
+		writePlainBlocks(sb, b.Blocks, indent+"> ")
+		if credit := RichTextPlain(b.Credit); credit != "" {
+			writePlainLine(sb, indent+"> ", "— "+credit)
+		}
+	case RichBlockPullQuotation:
+		writePlainLine(sb, indent+"> ", RichTextPlain(b.Text))
+		if credit := RichTextPlain(b.Credit); credit != "" {
+			writePlainLine(sb, indent+"> ", "— "+credit)
+		}
+	case RichBlockCollage:
+		writePlainBlocks(sb, b.Blocks, indent)
+		if b.Caption != nil {
+			writePlainLine(sb, indent, RichTextPlain(b.Caption.Text))
+		}
+	case RichBlockSlideshow:
+		writePlainBlocks(sb, b.Blocks, indent)
+		if b.Caption != nil {
+			writePlainLine(sb, indent, RichTextPlain(b.Caption.Text))
+		}
+	case RichBlockTable:
+		var rows []string
+		if caption := RichTextPlain(b.Caption); caption != "" {
+			rows = append(rows, caption)
+		}
+		for _, row := range b.Cells {
+			cells := make([]string, 0, len(row))
+			for _, cell := range row {
+				cells = append(cells, RichTextPlain(cell.Text))
+			}
+			rows = append(rows, strings.Join(cells, " | "))
+		}
+		writePlainLine(sb, indent, strings.Join(rows, "\n"))
+	case RichBlockDetails:
+		writePlainLine(sb, indent, "▸ "+RichTextPlain(b.Summary))
+		writePlainBlocks(sb, b.Blocks, indent+"  ")
+	case RichBlockMap:
+		writePlainCaption(sb, indent, fmt.Sprintf("map %s, %s",
+			strconv.FormatFloat(b.Location.Latitude, 'f', -1, 64),
+			strconv.FormatFloat(b.Location.Longitude, 'f', -1, 64)), b.Caption)
+	case RichBlockAnimation:
+		writePlainCaption(sb, indent, "animation", b.Caption)
+	case RichBlockAudio:
+		writePlainCaption(sb, indent, "audio", b.Caption)
+	case RichBlockPhoto:
+		writePlainCaption(sb, indent, "photo", b.Caption)
+	case RichBlockVideo:
+		writePlainCaption(sb, indent, "video", b.Caption)
+	case RichBlockVoiceNote:
+		writePlainCaption(sb, indent, "voice note", b.Caption)
+	case map[string]any:
+		if s, ok := b["text"].(string); ok {
+			writePlainLine(sb, indent, s)
+		}
+	}
+}
+
+func writeHTMLTag(sb *strings.Builder, tag string, text RichText, attrs ...string) {
+	sb.WriteByte('<')
+	sb.WriteString(tag)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		sb.WriteByte(' ')
+		sb.WriteString(attrs[i])
+		sb.WriteString(`="`)
+		sb.WriteString(html.EscapeString(attrs[i+1]))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('>')
+	writeHTMLText(sb, text)
+	sb.WriteString("</")
+	sb.WriteString(tag)
+	sb.WriteByte('>')
+}
+
+func writeHTMLText(sb *strings.Builder, text RichText) {
+	switch t := text.(type) {
+	case nil:
+	case string:
+		sb.WriteString(html.EscapeString(t))
+	case []RichText:
+		for _, part := range t {
+			writeHTMLText(sb, part)
+		}
+	case RichTextBold:
+		writeHTMLTag(sb, "b", t.Text)
+	case RichTextItalic:
+		writeHTMLTag(sb, "i", t.Text)
+	case RichTextUnderline:
+		writeHTMLTag(sb, "u", t.Text)
+	case RichTextStrikethrough:
+		writeHTMLTag(sb, "s", t.Text)
+	case RichTextSpoiler:
+		writeHTMLTag(sb, "tg-spoiler", t.Text)
+	case RichTextDateTime:
+		writeHTMLTag(sb, "tg-time", t.Text, "unix", strconv.FormatInt(t.UnixTime, 10), "format", t.DateTimeFormat)
+	case RichTextTextMention:
+		writeHTMLTag(sb, "a", t.Text, "href", "tg://user?id="+strconv.FormatInt(t.User.ID, 10))
+	case RichTextSubscript:
+		writeHTMLTag(sb, "sub", t.Text)
+	case RichTextSuperscript:
+		writeHTMLTag(sb, "sup", t.Text)
+	case RichTextMarked:
+		writeHTMLTag(sb, "mark", t.Text)
+	case RichTextCode:
+		writeHTMLTag(sb, "code", t.Text)
+	case RichTextCustomEmoji:
+		writeHTMLTag(sb, "tg-emoji", t.AlternativeText, "emoji-id", t.CustomEmojiID)
+	case RichTextMathematicalExpression:
+		writeHTMLTag(sb, "tg-math", t.Expression)
+	case RichTextUrl:
+		writeHTMLTag(sb, "a", t.Text, "href", t.URL)
+	case RichTextEmailAddress:
+		writeHTMLTag(sb, "a", t.Text, "href", "mailto:"+t.EmailAddress)
+	case RichTextPhoneNumber:
+		writeHTMLTag(sb, "a", t.Text, "href", "tel:"+t.PhoneNumber)
+	case RichTextAnchor:
+		writeHTMLTag(sb, "a", nil, "name", t.Name)
+	case RichTextAnchorLink:
+		writeHTMLTag(sb, "a", t.Text, "href", "#"+t.AnchorName)
+	case RichTextReference:
+		writeHTMLTag(sb, "a", t.Text, "name", t.Name)
+	case RichTextReferenceLink:
+		writeHTMLTag(sb, "a", t.Text, "href", "#"+t.ReferenceName)
+	default:
+		// Mentions, hashtags, cashtags, bot commands and card numbers are
+		// detected from plain text.
+		if inner, ok := richTextInner(text); ok {
+			writeHTMLText(sb, inner)
+			return
+		}
+		writeHTMLText(sb, RichTextPlain(text))
+	}
+}
+
+func writeHTMLFigure(sb *strings.Builder, kind string, blocks []RichBlock, caption *RichBlockCaption) {
+	sb.WriteString(`<figure data-type="`)
+	sb.WriteString(kind)
+	sb.WriteString(`">`)
+	for _, block := range blocks {
+		writeHTMLBlock(sb, block)
+	}
+	if caption != nil {
+		sb.WriteString("<figcaption>")
+		writeHTMLText(sb, caption.Text)
+		if caption.Credit != nil {
+			writeHTMLTag(sb, "cite", caption.Credit)
+		}
+		sb.WriteString("</figcaption>")
+	}
+	sb.WriteString("</figure>")
+}
+
+func writeHTMLBlock(sb *strings.Builder, block RichBlock) {
+	switch b := block.(type) {
+	case RichBlockParagraph:
+		writeHTMLTag(sb, "p", b.Text)
+	case RichBlockSectionHeading:
+		size := min(max(b.Size, 1), 6)
+		writeHTMLTag(sb, "h"+strconv.Itoa(size), b.Text)
+	case RichBlockPreformatted:
+		sb.WriteString("<pre>")
+		if b.Language != "" {
+			writeHTMLTag(sb, "code", b.Text, "class", "language-"+b.Language)
+		} else {
+			writeHTMLText(sb, b.Text)
+		}
+		sb.WriteString("</pre>")
+	case RichBlockFooter:
+		writeHTMLTag(sb, "footer", b.Text)
+	case RichBlockDivider:
+		sb.WriteString("<hr>")
+	case RichBlockMathematicalExpression:
+		writeHTMLTag(sb, "tg-math", b.Expression, "display", "block")
+	case RichBlockAnchor:
+		writeHTMLTag(sb, "a", nil, "name", b.Name)
+	case RichBlockList:
+		tag := "ul"
+		for _, item := range b.Items {
+			if item.Value != 0 {
+				tag = "ol"
+				break
+			}
+		}
+		sb.WriteString("<" + tag + ">")
+		for _, item := range b.Items {
+			if item.Value != 0 {
+				sb.WriteString(`<li value="` + strconv.Itoa(item.Value) + `">`)
+			} else {
+				sb.WriteString("<li>")
+			}
+			if item.HasCheckbox {
+				if item.IsChecked {
+					sb.WriteString("[x] ")
+				} else {
+					sb.WriteString("[ ] ")
+				}
+			}
+			for _, inner := range item.Blocks {
+				writeHTMLBlock(sb, inner)
+			}
+			sb.WriteString("</li>")
+		}
+		sb.WriteString("</" + tag + ">")
+	case RichBlockBlockQuotation:
+		sb.WriteString("<blockquote>")
+		for _, inner := range b.Blocks {
+			writeHTMLBlock(sb, inner)
+		}
+		if b.Credit != nil {
+			writeHTMLTag(sb, "cite", b.Credit)
+		}
+		sb.WriteString("</blockquote>")
+	case RichBlockPullQuotation:
+		sb.WriteString("<aside>")
+		writeHTMLText(sb, b.Text)
+		if b.Credit != nil {
+			writeHTMLTag(sb, "cite", b.Credit)
+		}
+		sb.WriteString("</aside>")
+	case RichBlockCollage:
+		writeHTMLFigure(sb, RichBlockTypeCollage, b.Blocks, b.Caption)
+	case RichBlockSlideshow:
+		writeHTMLFigure(sb, RichBlockTypeSlideshow, b.Blocks, b.Caption)
+	case RichBlockTable:
+		sb.WriteString("<table>")
+		if b.Caption != nil {
+			writeHTMLTag(sb, "caption", b.Caption)
+		}
+		for _, row := range b.Cells {
+			sb.WriteString("<tr>")
+			for _, cell := range row {
+				tag := "td"
+				if cell.IsHeader {
+					tag = "th"
+				}
+				var attrs []string
+				if cell.Colspan > 1 {
+					attrs = append(attrs, "colspan", strconv.Itoa(cell.Colspan))
+				}
+				if cell.Rowspan > 1 {
+					attrs = append(attrs, "rowspan", strconv.Itoa(cell.Rowspan))
+				}
+				if cell.Align != "" {
+					attrs = append(attrs, "align", cell.Align)
+				}
+				if cell.Valign != "" {
+					attrs = append(attrs, "valign", cell.Valign)
+				}
+				writeHTMLTag(sb, tag, cell.Text, attrs...)
+			}
+			sb.WriteString("</tr>")
+		}
+		sb.WriteString("</table>")
+	case RichBlockDetails:
+		if b.IsOpen {
+			sb.WriteString("<details open>")
+		} else {
+			sb.WriteString("<details>")
+		}
+		writeHTMLTag(sb, "summary", b.Summary)
+		for _, inner := range b.Blocks {
+			writeHTMLBlock(sb, inner)
+		}
+		sb.WriteString("</details>")
+	case RichBlockMap:
+		writeHTMLFigure(sb, RichBlockTypeMap, nil, b.Caption)
+	case RichBlockAnimation:
+		writeHTMLFigure(sb, RichBlockTypeAnimation, nil, b.Caption)
+	case RichBlockAudio:
+		writeHTMLFigure(sb, RichBlockTypeAudio, nil, b.Caption)
+	case RichBlockPhoto:
+		writeHTMLFigure(sb, RichBlockTypePhoto, nil, b.Caption)
+	case RichBlockVideo:
+		writeHTMLFigure(sb, RichBlockTypeVideo, nil, b.Caption)
+	case RichBlockVoiceNote:
+		writeHTMLFigure(sb, RichBlockTypeVoiceNote, nil, b.Caption)
+	case RichBlockThinking:
+		writeHTMLTag(sb, "p", b.Text)
+	}
+}
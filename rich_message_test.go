@@ -0,0 +1,180 @@
+package tgbotapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRichMessageUnmarshalTypedBlocks(t *testing.T) {
+	const body = `{
+		"blocks":[
+			{"type":"paragraph","text":["Hello ",{"type":"bold","text":{"type":"italic","text":"world"}}]},
+			{"type":"list","items":[{"label":"1.","blocks":[{"type":"paragraph","text":{"type":"url","text":"docs","url":"https://core.telegram.org"}}]}]},
+			{"type":"table","cells":[[{"text":"A","is_header":true,"align":"left","valign":"top"}]],"caption":{"type":"code","text":"t"}},
+			{"type":"collage","blocks":[{"type":"photo","photo":[{"file_id":"p","file_unique_id":"u","width":1,"height":1}]}],"caption":{"text":"Shot"}},
+			{"type":"future_block","text":"kept"}
+		]
+	}`
+
+	var message RichMessage
+	if err := json.Unmarshal([]byte(body), &message); err != nil {
+		t.Fatal(err)
+	}
+	if len(message.Blocks) != 5 {
+		t.Fatalf("unexpected blocks: %#v", message.Blocks)
+	}
+
+	paragraph, ok := message.Blocks[0].(RichBlockParagraph)
+	if !ok {
+		t.Fatalf("expected RichBlockParagraph, got %T", message.Blocks[0])
+	}
+	parts, ok := paragraph.Text.([]RichText)
+	if !ok || len(parts) != 2 || parts[0] != "Hello " {
+		t.Fatalf("unexpected paragraph text: %#v", paragraph.Text)
+	}
+	bold, ok := parts[1].(RichTextBold)
+	if !ok {
+		t.Fatalf("expected RichTextBold, got %T", parts[1])
+	}
+	if italic, ok := bold.Text.(RichTextItalic); !ok || italic.Text != "world" {
+		t.Fatalf("unexpected nested text: %#v", bold.Text)
+	}
+
+	list := message.Blocks[1].(RichBlockList)
+	link, ok := list.Items[0].Blocks[0].(RichBlockParagraph).Text.(RichTextUrl)
+	if !ok || link.URL != "https://core.telegram.org" {
+		t.Fatalf("unexpected list item text: %#v", list.Items[0].Blocks[0])
+	}
+
+	table := message.Blocks[2].(RichBlockTable)
+	if _, ok := table.Caption.(RichTextCode); !ok || table.Cells[0][0].Text != "A" {
+		t.Fatalf("unexpected table: %#v", table)
+	}
+
+	collage := message.Blocks[3].(RichBlockCollage)
+	if photo, ok := collage.Blocks[0].(RichBlockPhoto); !ok || photo.Photo[0].FileID != "p" || collage.Caption.Text != "Shot" {
+		t.Fatalf("unexpected collage: %#v", collage)
+	}
+
+	if unknown, ok := message.Blocks[4].(map[string]any); !ok || unknown["text"] != "kept" {
+		t.Fatalf("unexpected unknown block: %#v", message.Blocks[4])
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again RichMessage
+	if err := json.Unmarshal(data, &again); err != nil {
+		t.Fatal(err)
+	}
+	if again.PlainText() != message.PlainText() {
+		t.Fatalf("round trip changed message: %q != %q", again.PlainText(), message.PlainText())
+	}
+}
+
+func TestRichMessageBuilderRendersHTML(t *testing.T) {
+	input, err := NewRichMessageBuilder().
+		Heading(1, "News & ", NewRichTextBold("updates")).
+		Paragraph("See ", NewRichTextURL("https://example.com/?a=1&b=2", "the <site>"), ".").
+		List(
+			NewRichBlockListItem(NewRichBlockParagraph("first")),
+			NewRichBlockListItem(NewRichBlockParagraph(NewRichTextCode("second"))),
+		).
+		Table(NewRichBlockTableRow(NewRichBlockTableHeaderCell("k"), NewRichBlockTableCell("v"))).
+		Divider().
+		Footer(NewRichTextAnchor("end"), NewRichTextAnchorLink("end", "bottom")).
+		InputRichMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<h1>News &amp; <b>updates</b></h1>` +
+		`<p>See <a href="https://example.com/?a=1&amp;b=2">the &lt;site&gt;</a>.</p>` +
+		`<ul><li><p>first</p></li><li><p><code>second</code></p></li></ul>` +
+		`<table><tr><th>k</th><td>v</td></tr></table>` +
+		`<hr>` +
+		`<footer><a name="end"></a><a href="#end">bottom</a></footer>`
+	if input.HTML != expected {
+		t.Fatalf("unexpected HTML:\n%s\nexpected:\n%s", input.HTML, expected)
+	}
+}
+
+func TestRichMessageBuilderRejectsBlocksWithoutHTML(t *testing.T) {
+	photo := RichBlockPhoto{
+		Type:    RichBlockTypePhoto,
+		Photo:   []PhotoSize{{FileID: "photo", Width: 10, Height: 10}},
+		Caption: &RichBlockCaption{Text: "caption"},
+	}
+
+	_, err := NewRichMessageBuilder().
+		Paragraph("intro").
+		Block(photo).
+		Block(NewRichBlockThinking("hmm")).
+		InputRichMessage()
+	if err == nil || !strings.Contains(err.Error(), "photo, thinking blocks") {
+		t.Fatalf("expected photo and thinking blocks to be rejected, got %v", err)
+	}
+
+	if _, err := NewRichMessageBuilder().Paragraph("text").InputRichMessage(); err != nil {
+		t.Fatalf("expected text blocks to be rendered, got %v", err)
+	}
+}
+
+func TestRichMessagePlainText(t *testing.T) {
+	message, err := NewRichMessageBuilder().
+		Heading(2, "Title").
+		Paragraph("Hello ", NewRichTextCustomEmoji("123", "👋")).
+		List(
+			RichBlockListItem{Label: "1.", Blocks: []RichBlock{NewRichBlockParagraph("one")}},
+			RichBlockListItem{Label: "2.", HasCheckbox: true, IsChecked: true, Blocks: []RichBlock{NewRichBlockParagraph("two")}},
+		).
+		Quote(NewRichBlockParagraph("quoted\ntext")).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Title\n\nHello 👋\n\n1. one\n2. [x] two\n\n> quoted\n> text"
+	if got := message.PlainText(); got != expected {
+		t.Fatalf("unexpected plain text:\n%q\nexpected:\n%q", got, expected)
+	}
+}
+
+func TestRichMessageValidate(t *testing.T) {
+	message := RichMessage{
+		Blocks: []RichBlock{
+			NewRichBlockSectionHeading(9, "too big"),
+			NewRichBlockParagraph(NewRichTextURL("https://a.example", NewRichTextMention("user", "@user"))),
+			NewRichBlockParagraph(NewRichTextCode(NewRichTextBold("x"))),
+			NewRichBlockParagraph(NewRichTextAnchorLink("missing", "jump")),
+			RichBlockCollage{Type: RichBlockTypeCollage, Blocks: []RichBlock{NewRichBlockParagraph("text")}},
+			NewRichBlockDetails("more", NewRichBlockThinking("...")),
+			RichBlockParagraph{Text: "no type"},
+		},
+	}
+
+	err := message.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	for _, expected := range []string{
+		"blocks[0]: heading size 9",
+		"blocks[1].text.text: mention cannot be nested in another link",
+		"blocks[2].text.text: only plain text is allowed here",
+		`blocks[3].text: anchor "missing" does not exist`,
+		"blocks[4].blocks[0]: collage may only contain photo, video and animation blocks",
+		"blocks[5].blocks[0]: thinking blocks are only allowed at the top level",
+		`blocks[6]: type is "", expected "paragraph"`,
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Fatalf("expected %q in:\n%v", expected, err)
+		}
+	}
+
+	if _, err := NewRichMessageBuilder().Paragraph("fine").Build(); err != nil {
+		t.Fatalf("unexpected error for valid message: %v", err)
+	}
+}
@@ -0,0 +1,393 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Fallback is a way FallbackSender recovers from a failed send.
+type Fallback string
+
+const (
+	// FallbackDropReply resends without ReplyParameters when the message to
+	// reply to was not found.
+	FallbackDropReply Fallback = "drop_reply"
+	// FallbackPlainText resends without ParseMode when the markup could not
+	// be parsed.
+	FallbackPlainText Fallback = "plain_text"
+	// FallbackSplitText splits a message text that is too long into several
+	// messages.
+	FallbackSplitText Fallback = "split_text"
+	// FallbackCaptionFollowUp sends media without a caption that is too long
+	// and sends the caption as a reply to it.
+	FallbackCaptionFollowUp Fallback = "caption_follow_up"
+	// FallbackMigrateChat resends to the supergroup a group was migrated to.
+	FallbackMigrateChat Fallback = "migrate_chat"
+)
+
+// AllFallbacks contains every Fallback.
+var AllFallbacks = []Fallback{
+	FallbackDropReply,
+	FallbackPlainText,
+	FallbackSplitText,
+	FallbackCaptionFollowUp,
+	FallbackMigrateChat,
+}
+
+// FallbackSender sends Chattables like BotAPI.Send, retrying with an adjusted
+// config when the send fails with a recoverable error. Configs sending a
+// single message, such as MessageConfig and PhotoConfig, are retried; errors
+// of other configs are returned as they are.
+//
+// Files sent from a FileReader cannot be read twice, so configs uploading
+// them are never retried.
+type FallbackSender struct {
+	Bot *BotAPI
+	// Fallbacks are the enabled fallbacks.
+	Fallbacks []Fallback
+}
+
+// SendResult is the result of a FallbackSender send.
+type SendResult struct {
+	// Messages are the sent messages. There is more than one message when
+	// the text was split or the caption was sent as a follow-up.
+	Messages []Message
+	// Fallbacks are the fallbacks that were applied, in order.
+	Fallbacks []Fallback
+}
+
+// Message returns the last sent message.
+func (r SendResult) Message() Message {
+	if len(r.Messages) == 0 {
+		return Message{}
+	}
+
+	return r.Messages[len(r.Messages)-1]
+}
+
+// NewFallbackSender creates a new FallbackSender. All fallbacks are enabled
+// if none are given.
+func NewFallbackSender(bot *BotAPI, fallbacks ...Fallback) *FallbackSender {
+	if len(fallbacks) == 0 {
+		fallbacks = AllFallbacks
+	}
+
+	return &FallbackSender{
+		Bot:       bot,
+		Fallbacks: fallbacks,
+	}
+}
+
+// Send sends a Chattable, applying fallbacks when it fails.
+func (s *FallbackSender) Send(c Chattable) (SendResult, error) {
+	return s.SendWithContext(context.Background(), c)
+}
+
+// SendWithContext sends a Chattable, applying fallbacks when it fails.
+//
+// When an error is returned, the result still contains the messages sent
+// before the failure.
+func (s *FallbackSender) SendWithContext(ctx context.Context, c Chattable) (SendResult, error) {
+	var result SendResult
+	err := s.send(ctx, c, &result)
+
+	return result, err
+}
+
+func (s *FallbackSender) send(ctx context.Context, c Chattable, result *SendResult) error {
+	applied := make(map[Fallback]bool)
+
+	for {
+		message, err := s.Bot.SendWithContext(ctx, c)
+		if err == nil {
+			result.Messages = append(result.Messages, message)
+			return nil
+		}
+
+		config, ok := newFallbackConfig(c)
+		if !ok {
+			return err
+		}
+		fallback := s.match(err, config)
+		if fallback == "" || applied[fallback] {
+			return err
+		}
+
+		applied[fallback] = true
+		if !slices.Contains(result.Fallbacks, fallback) {
+			result.Fallbacks = append(result.Fallbacks, fallback)
+		}
+
+		switch fallback {
+		case FallbackMigrateChat:
+			var apiErr *Error
+			errors.As(err, &apiErr)
+			config.base.ChatID = apiErr.MigrateToChatID
+		case FallbackDropReply:
+			config.base.ReplyParameters = ReplyParameters{}
+		case FallbackPlainText:
+			*config.parseMode = ""
+		case FallbackSplitText:
+			return s.sendSplit(ctx, config, err, result)
+		case FallbackCaptionFollowUp:
+			return s.sendCaptionFollowUp(ctx, config, result)
+		}
+
+		c = config.chattable()
+	}
+}
+
+// Descriptions of the errors the fallbacks recover from, in lower case.
+var (
+	replyNotFoundErrors = []string{
+		"message to be replied not found",
+		"replied message not found",
+		"message to reply not found",
+		"reply message not found",
+	}
+	parseErrors = []string{
+		"can't parse entities",
+		"can't parse message text",
+		"can't find end of",
+		"unsupported start tag",
+		"unexpected end tag",
+	}
+	captionTooLongErrors = []string{
+		"message caption is too long",
+		"caption is too long",
+		"media_caption_too_long",
+	}
+	textTooLongErrors = []string{
+		"message is too long",
+		"text is too long",
+		"message_too_long",
+	}
+)
+
+// match returns the enabled fallback recovering from err, if any.
+func (s *FallbackSender) match(err error, config fallbackConfig) Fallback {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		return ""
+	}
+
+	description := strings.ToLower(apiErr.Message)
+	describes := func(descriptions []string) bool {
+		return slices.ContainsFunc(descriptions, func(d string) bool {
+			return strings.Contains(description, d)
+		})
+	}
+	var fallback Fallback
+
+	switch {
+	case apiErr.MigrateToChatID != 0:
+		fallback = FallbackMigrateChat
+	case describes(replyNotFoundErrors) && config.base.ReplyParameters.MessageID != 0:
+		fallback = FallbackDropReply
+	case describes(parseErrors) && config.parseMode != nil && *config.parseMode != "":
+		fallback = FallbackPlainText
+	case describes(captionTooLongErrors) && config.caption != nil:
+		fallback = FallbackCaptionFollowUp
+	case describes(textTooLongErrors) && config.text != nil:
+		fallback = FallbackSplitText
+	}
+
+	if !slices.Contains(s.Fallbacks, fallback) {
+		return ""
+	}
+
+	return fallback
+}
+
+// sendSplit sends the text of config as several messages, or returns err if
+// it fits into one. ReplyParameters only apply to the first message and
+// ReplyMarkup only to the last one.
+func (s *FallbackSender) sendSplit(ctx context.Context, config fallbackConfig, err error, result *SendResult) error {
+	var chunks []textChunk
+	if *config.parseMode != "" && len(*config.entities) == 0 {
+		for text := *config.text; text != ""; {
+			var chunk textChunk
+			chunk.text, text = splitMarkup(text, *config.parseMode, MaxMessageTextLength)
+			chunks = append(chunks, chunk)
+		}
+	} else {
+		chunks = splitMessageText(*config.text, *config.entities, MaxMessageTextLength)
+	}
+	if len(chunks) < 2 {
+		return err
+	}
+
+	for i, chunk := range chunks {
+		part, _ := newFallbackConfig(config.chattable())
+		*part.text = chunk.text
+		*part.entities = chunk.entities
+		if i > 0 {
+			part.base.ReplyParameters = ReplyParameters{}
+		}
+		if i < len(chunks)-1 {
+			part.base.ReplyMarkup = nil
+		}
+
+		if err := s.send(ctx, part.chattable(), result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendCaptionFollowUp sends the media of config without a caption and the
+// caption as a message replying to it.
+func (s *FallbackSender) sendCaptionFollowUp(ctx context.Context, config fallbackConfig, result *SendResult) error {
+	base := *config.base
+	caption, parseMode, entities := *config.caption, *config.parseMode, *config.entities
+
+	*config.caption = ""
+	*config.parseMode = ""
+	*config.entities = nil
+	config.base.ReplyMarkup = nil
+
+	if err := s.send(ctx, config.chattable(), result); err != nil {
+		return err
+	}
+
+	base.ReplyParameters = ReplyParameters{MessageID: result.Message().MessageID}
+
+	return s.send(ctx, MessageConfig{
+		BaseChat:  base,
+		Text:      caption,
+		ParseMode: parseMode,
+		Entities:  entities,
+	}, result)
+}
+
+// fallbackConfig is an editable copy of a config. Its fields point into the
+// copy; text and caption are only set for configs having them, along with
+// parseMode and entities.
+type fallbackConfig struct {
+	base      *BaseChat
+	text      *string
+	caption   *string
+	parseMode *string
+	entities  *[]MessageEntity
+	chattable func() Chattable
+}
+
+func newFallbackConfig(c Chattable) (fallbackConfig, bool) {
+	if f, ok := c.(Fileable); ok {
+		for _, file := range f.files() {
+			if _, ok := file.Data.(FileReader); ok {
+				return fallbackConfig{}, false
+			}
+		}
+	}
+
+	var config fallbackConfig
+	switch c := c.(type) {
+	case MessageConfig:
+		config = fallbackConfig{base: &c.BaseChat, text: &c.Text, parseMode: &c.ParseMode, entities: &c.Entities}
+		config.chattable = func() Chattable { return c }
+	case CopyMessageConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case SendLivePhotoConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case PaidMediaConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case PhotoConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case AudioConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case DocumentConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case VideoConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case AnimationConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case VoiceConfig:
+		config = captionFallbackConfig(&c.BaseChat, &c.Caption, &c.ParseMode, &c.CaptionEntities)
+		config.chattable = func() Chattable { return c }
+	case StickerConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case VideoNoteConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case SendChecklistConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case SendRichMessageConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case ForwardConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case LocationConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case VenueConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case ContactConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case SendPollConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case GameConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case InvoiceConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	case DiceConfig:
+		config = fallbackConfig{base: &c.BaseChat, chattable: func() Chattable { return c }}
+	default:
+		return fallbackConfig{}, false
+	}
+
+	return config, true
+}
+
+func captionFallbackConfig(base *BaseChat, caption, parseMode *string, entities *[]MessageEntity) fallbackConfig {
+	return fallbackConfig{base: base, caption: caption, parseMode: parseMode, entities: entities}
+}
+
+type textChunk struct {
+	text     string
+	entities []MessageEntity
+}
+
+// splitMessageText splits text into parts of at most limit UTF-16 code units,
+// moving the entities into the parts they belong to.
+func splitMessageText(text string, entities []MessageEntity, limit int) []textChunk {
+	var chunks []textChunk
+	start := 0
+
+	for text != "" {
+		cut := splitTextIndex(text, limit)
+		chunk := textChunk{text: text[:cut]}
+		length := utf16Length(chunk.text)
+
+		for _, entity := range entities {
+			from := max(entity.Offset, start) - start
+			to := min(entity.Offset+entity.Length, start+length) - start
+			if to <= from {
+				continue
+			}
+
+			entity.Offset, entity.Length = from, to-from
+			chunk.entities = append(chunk.entities, entity)
+		}
+
+		chunks = append(chunks, chunk)
+
+		text = text[cut:]
+		start += length
+		if strings.HasPrefix(text, "\n") || strings.HasPrefix(text, " ") {
+			text = text[1:]
+			start++
+		}
+	}
+
+	return chunks
+}
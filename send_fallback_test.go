@@ -0,0 +1,224 @@
+package tgbotapi
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fallbackTestClient answers sends like the Bot API, failing on the
+// conditions the fallbacks recover from.
+func fallbackTestClient(t *testing.T, requests *[]url.Values) fakeHTTPClient {
+	return fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("read request body: %v", err)
+			}
+			values, err := url.ParseQuery(string(body))
+			if err != nil {
+				t.Fatalf("parse request body: %v", err)
+			}
+			*requests = append(*requests, values)
+
+			var response string
+			switch {
+			case values.Get("chat_id") == "-1":
+				response = `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-100}}`
+			case strings.Contains(values.Get("reply_parameters"), `"message_id":404`):
+				response = `{"ok":false,"error_code":400,"description":"Bad Request: message to be replied not found"}`
+			case values.Get("parse_mode") != "" && strings.Contains(values.Get("text")+values.Get("caption"), "*"):
+				response = `{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities: Can't find end of the entity"}`
+			case markupLength(values.Get("text"), values.Get("parse_mode")) > MaxMessageTextLength:
+				response = `{"ok":false,"error_code":400,"description":"Bad Request: message is too long"}`
+			case utf16Length(values.Get("caption")) > 1024:
+				response = `{"ok":false,"error_code":400,"description":"Bad Request: message caption is too long"}`
+			case strings.HasSuffix(req.URL.Path, "/copyMessage"):
+				// copyMessage returns only the ID of the copy.
+				response = `{"ok":true,"result":{"message_id":` + strconv.Itoa(len(*requests)) + `}}`
+			default:
+				response = `{"ok":true,"result":{"message_id":` + strconv.Itoa(len(*requests)) + `,"date":1,"chat":{"id":` + values.Get("chat_id") + `,"type":"supergroup"}}}`
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(response)),
+			}, nil
+		},
+	}
+}
+
+func TestFallbackSenderMigratesAndDropsReply(t *testing.T) {
+	var requests []url.Values
+	sender := NewFallbackSender(newFakeBot(fallbackTestClient(t, &requests)))
+
+	config := NewMessage(-1, "hello *world")
+	config.ParseMode = ModeMarkdown
+	config.ReplyParameters.MessageID = 404
+
+	result, err := sender.Send(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Fallback{FallbackMigrateChat, FallbackDropReply, FallbackPlainText}
+	if !slices.Equal(result.Fallbacks, expected) {
+		t.Fatalf("unexpected fallbacks: %v", result.Fallbacks)
+	}
+	if result.Message().Chat.ID != -100 || len(requests) != 4 {
+		t.Fatalf("unexpected result %+v after %d requests", result, len(requests))
+	}
+}
+
+func TestFallbackSenderSplitsLongText(t *testing.T) {
+	var requests []url.Values
+	sender := NewFallbackSender(newFakeBot(fallbackTestClient(t, &requests)), FallbackSplitText)
+
+	first := strings.Repeat("a", MaxMessageTextLength-5)
+	config := NewMessage(1, first+"\n"+"bold tail")
+	config.Entities = []MessageEntity{{Type: EntityTypeBold, Offset: len(first) - 2, Length: 8}}
+	config.ReplyParameters.MessageID = 5
+	config.ReplyMarkup = NewInlineKeyboardMarkup(NewInlineKeyboardRow(NewInlineKeyboardButtonData("ok", "ok")))
+
+	result, err := sender.Send(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Messages) != 2 || !slices.Equal(result.Fallbacks, []Fallback{FallbackSplitText}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	head, tail := requests[1], requests[2]
+	if head.Get("text") != first || tail.Get("text") != "bold tail" {
+		t.Fatalf("unexpected split: %q / %q", head.Get("text"), tail.Get("text"))
+	}
+	if head.Get("reply_markup") != "" || head.Get("reply_parameters") == "" || tail.Get("reply_markup") == "" || strings.Contains(tail.Get("reply_parameters"), `"message_id":5`) {
+		t.Fatalf("reply parameters and markup must go to the first and last message: %v / %v", head, tail)
+	}
+	if !strings.Contains(head.Get("entities"), `"offset":4089,"length":2`) || !strings.Contains(tail.Get("entities"), `"offset":0,"length":5`) {
+		t.Fatalf("unexpected entities: %s / %s", head.Get("entities"), tail.Get("entities"))
+	}
+}
+
+func TestFallbackSenderMovesCaptionToFollowUp(t *testing.T) {
+	var requests []url.Values
+	sender := NewFallbackSender(newFakeBot(fallbackTestClient(t, &requests)))
+
+	config := NewPhoto(1, FileID("photo"))
+	config.Caption = strings.Repeat("c", 2000)
+
+	result, err := sender.Send(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Messages) != 2 || !slices.Equal(result.Fallbacks, []Fallback{FallbackCaptionFollowUp}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if requests[1].Get("caption") != "" || requests[2].Get("text") != config.Caption || !strings.Contains(requests[2].Get("reply_parameters"), `"message_id":2`) {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+}
+
+func TestFallbackSenderMovesCopyCaptionToFollowUp(t *testing.T) {
+	var requests []url.Values
+	sender := NewFallbackSender(newFakeBot(fallbackTestClient(t, &requests)))
+
+	config := NewCopyMessage(1, 2, 3)
+	config.Caption = strings.Repeat("c", 2000)
+
+	result, err := sender.Send(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Messages) != 2 || !slices.Equal(result.Fallbacks, []Fallback{FallbackCaptionFollowUp}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if requests[2].Get("chat_id") != "1" || requests[2].Get("text") != config.Caption || !strings.Contains(requests[2].Get("reply_parameters"), `"message_id":2`) {
+		t.Fatalf("expected the caption to follow the copy in chat 1, got %v", requests[2])
+	}
+}
+
+func TestFallbackSenderReturnsUnrecoverableErrors(t *testing.T) {
+	var requests []url.Values
+	sender := NewFallbackSender(newFakeBot(fallbackTestClient(t, &requests)), FallbackMigrateChat)
+
+	config := NewMessage(1, "*")
+	config.ParseMode = ModeMarkdown
+
+	if _, err := sender.Send(config); err == nil || len(requests) != 1 {
+		t.Fatalf("expected the error to be returned without retrying, got %v after %d requests", err, len(requests))
+	}
+}
+
+func TestFallbackSenderDropsReplyForEveryWording(t *testing.T) {
+	for _, description := range replyNotFoundErrors {
+		t.Run(description, func(t *testing.T) {
+			var requests []url.Values
+			client := fakeHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+				if err := req.ParseForm(); err != nil {
+					t.Fatal(err)
+				}
+				requests = append(requests, req.PostForm)
+
+				response := `{"ok":true,"result":{"message_id":1,"date":1,"chat":{"id":1,"type":"private"}}}`
+				if strings.Contains(req.PostForm.Get("reply_parameters"), `"message_id":404`) {
+					response = `{"ok":false,"error_code":400,"description":"Bad Request: ` + strings.ToUpper(description[:1]) + description[1:] + `"}`
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(response))}, nil
+			}}
+			sender := NewFallbackSender(newFakeBot(client), FallbackDropReply)
+
+			config := NewMessage(1, "hello")
+			config.ReplyParameters.MessageID = 404
+			result, err := sender.Send(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(result.Fallbacks, []Fallback{FallbackDropReply}) || len(requests) != 2 {
+				t.Fatalf("unexpected result %+v after %d requests", result, len(requests))
+			}
+		})
+	}
+}
+
+func TestFallbackSenderIgnoresOtherErrorCodes(t *testing.T) {
+	requests := 0
+	client := fakeHTTPClient{do: func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"ok":false,"error_code":403,"description":"Forbidden: message to be replied not found"}`)),
+		}, nil
+	}}
+	sender := NewFallbackSender(newFakeBot(client))
+
+	config := NewMessage(1, "hello")
+	config.ReplyParameters.MessageID = 404
+	if _, err := sender.Send(config); err == nil || requests != 1 {
+		t.Fatalf("expected the error to be returned without retrying, got %v after %d requests", err, requests)
+	}
+}
+
+func TestFallbackSenderSplitsMarkupOnEntityBoundaries(t *testing.T) {
+	var requests []url.Values
+	sender := NewFallbackSender(newFakeBot(fallbackTestClient(t, &requests)), FallbackSplitText)
+
+	first := strings.Repeat("a", MaxMessageTextLength-5)
+	config := NewMessage(1, "<b>"+first+" bold</b>tail")
+	config.ParseMode = ModeHTML
+
+	result, err := sender.Send(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Messages) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if head, tail := requests[1].Get("text"), requests[2].Get("text"); head != "<b>"+first+"</b>" || tail != "<b>bold</b>tail" {
+		t.Fatalf("unexpected split: %q / %q", head, tail)
+	}
+}
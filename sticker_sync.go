@@ -0,0 +1,504 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// StickerManifestFile is the name of the manifest read by StickerSync.
+	StickerManifestFile = "stickers.json"
+	// StickerLockFile is the name of the file where StickerSync records the
+	// synchronised state of a sticker set.
+	StickerLockFile = "stickers.lock.json"
+)
+
+// ErrStickerLockMissing is returned by StickerSync when the sticker set
+// exists but StickerLockFile does not, so the stickers of the set cannot be
+// matched with the files of the manifest.
+var ErrStickerLockMissing = errors.New("sticker lock file is missing")
+
+// StickerManifest describes a sticker set kept in a local directory.
+type StickerManifest struct {
+	// Name of the sticker set, which must end with "_by_<bot_username>".
+	Name string `json:"name"`
+	// Title of the sticker set. The title is not changed if it is empty.
+	Title string `json:"title,omitempty"`
+	// StickerType is used when creating the set, "regular" by default.
+	StickerType     string `json:"sticker_type,omitempty"`
+	NeedsRepainting bool   `json:"needs_repainting,omitempty"`
+	// Stickers in the order of the set.
+	Stickers []StickerManifestEntry `json:"stickers"`
+}
+
+// StickerManifestEntry describes a sticker of a StickerManifest.
+type StickerManifestEntry struct {
+	// File is the path of the sticker, relative to the directory.
+	File string `json:"file"`
+	// Format is "static", "animated" or "video". It is derived from the
+	// extension of the file if empty.
+	Format    string   `json:"format,omitempty"`
+	EmojiList []string `json:"emoji_list"`
+	Keywords  []string `json:"keywords,omitempty"`
+}
+
+// StickerChangeKind is a kind of change made by StickerSync.
+type StickerChangeKind string
+
+// Kinds of changes made by StickerSync.
+const (
+	StickerChangeCreateSet StickerChangeKind = "create"
+	StickerChangeTitle     StickerChangeKind = "title"
+	StickerChangeDelete    StickerChangeKind = "delete"
+	StickerChangeReplace   StickerChangeKind = "replace"
+	StickerChangeEmojiList StickerChangeKind = "emoji_list"
+	StickerChangeKeywords  StickerChangeKind = "keywords"
+	StickerChangeAdd       StickerChangeKind = "add"
+	StickerChangeMove      StickerChangeKind = "move"
+)
+
+// StickerChange is a change to a sticker set.
+type StickerChange struct {
+	Kind StickerChangeKind
+	// File is the sticker in the manifest. It is empty when deleting a
+	// sticker which was not added from the manifest.
+	File string
+	// Sticker is the file ID of the sticker in the set, if it exists.
+	Sticker string
+	// Position is the new position of a moved sticker.
+	Position int
+	// Value is the new title, emoji list or keywords.
+	Value []string
+}
+
+func (c StickerChange) String() string {
+	name := c.File
+	if name == "" {
+		name = c.Sticker
+	}
+
+	switch c.Kind {
+	case StickerChangeCreateSet:
+		return fmt.Sprintf("+ create set with %s", name)
+	case StickerChangeTitle:
+		return fmt.Sprintf("~ title %q", strings.Join(c.Value, ""))
+	case StickerChangeDelete:
+		return fmt.Sprintf("- delete %s", name)
+	case StickerChangeReplace:
+		return fmt.Sprintf("~ replace %s", name)
+	case StickerChangeEmojiList:
+		return fmt.Sprintf("~ emoji %s %s", name, strings.Join(c.Value, " "))
+	case StickerChangeKeywords:
+		return fmt.Sprintf("~ keywords %s %s", name, strings.Join(c.Value, ", "))
+	case StickerChangeAdd:
+		return fmt.Sprintf("+ add %s", name)
+	case StickerChangeMove:
+		return fmt.Sprintf("~ move %s to %d", name, c.Position)
+	}
+
+	return fmt.Sprintf("? %s %s", c.Kind, name)
+}
+
+// StickerSyncPlan is the list of changes needed to synchronise a sticker set.
+type StickerSyncPlan struct {
+	Changes []StickerChange
+
+	manifest StickerManifest
+	hashes   map[string]string
+	// order is the expected order of the files in the set before moves.
+	order []string
+}
+
+// String returns the changes as a diff, one change per line.
+func (p StickerSyncPlan) String() string {
+	var b strings.Builder
+	for _, change := range p.Changes {
+		b.WriteString(change.String())
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// StickerSync synchronises a sticker set with a directory containing sticker
+// files and a StickerManifest in StickerManifestFile.
+//
+// Telegram does not return the emoji list, keywords or content of stickers,
+// so the state of the set after each sync is recorded in StickerLockFile.
+// Stickers of the set not recorded there are deleted, after the new stickers
+// are added. A sync of an existing set without a lock file fails with
+// ErrStickerLockMissing instead of deleting every sticker. If a sync fails
+// halfway, the lock file is not updated and the next sync may add stickers
+// again.
+type StickerSync struct {
+	Bot *BotAPI
+	// UserID is the owner of the sticker set.
+	UserID int64
+	Dir    string
+}
+
+// NewStickerSync creates a new StickerSync.
+func NewStickerSync(bot *BotAPI, userID int64, dir string) *StickerSync {
+	return &StickerSync{
+		Bot:    bot,
+		UserID: userID,
+		Dir:    dir,
+	}
+}
+
+type stickerLock struct {
+	Stickers map[string]stickerLockEntry `json:"stickers"`
+}
+
+type stickerLockEntry struct {
+	SHA256       string   `json:"sha256"`
+	FileUniqueID string   `json:"file_unique_id"`
+	EmojiList    []string `json:"emoji_list"`
+	Keywords     []string `json:"keywords,omitempty"`
+}
+
+// Plan returns the changes Sync would make, without making them.
+func (s *StickerSync) Plan(ctx context.Context) (StickerSyncPlan, error) {
+	manifest, err := s.readManifest()
+	if err != nil {
+		return StickerSyncPlan{}, err
+	}
+
+	var lock stickerLock
+	err = s.readJSON(StickerLockFile, &lock)
+	lockMissing := errors.Is(err, os.ErrNotExist)
+	if err != nil && !lockMissing {
+		return StickerSyncPlan{}, err
+	}
+
+	hashes := make(map[string]string, len(manifest.Stickers))
+	for _, entry := range manifest.Stickers {
+		_, digest, err := hashUpload(FilePath(filepath.Join(s.Dir, entry.File)))
+		if err != nil {
+			return StickerSyncPlan{}, fmt.Errorf("sticker sync: %w", err)
+		}
+		hashes[entry.File] = digest
+	}
+
+	set, err := s.getStickerSet(ctx, manifest.Name)
+	if err != nil {
+		return StickerSyncPlan{}, err
+	}
+	if lockMissing && set != nil && len(set.Stickers) > 0 {
+		return StickerSyncPlan{}, fmt.Errorf("sticker sync: %s: %w", manifest.Name, ErrStickerLockMissing)
+	}
+
+	return planStickerSync(manifest, lock, set, hashes), nil
+}
+
+// Sync makes the changes needed for the sticker set to match the directory
+// and records the new state in StickerLockFile.
+func (s *StickerSync) Sync(ctx context.Context) (StickerSyncPlan, error) {
+	plan, err := s.Plan(ctx)
+	if err != nil || len(plan.Changes) == 0 {
+		return plan, err
+	}
+
+	entries := make(map[string]StickerManifestEntry, len(plan.manifest.Stickers))
+	for _, entry := range plan.manifest.Stickers {
+		entries[entry.File] = entry
+	}
+
+	for _, change := range plan.Changes {
+		if change.Kind == StickerChangeMove {
+			continue
+		}
+		if err := s.apply(ctx, plan.manifest, entries[change.File], change); err != nil {
+			return plan, fmt.Errorf("sticker sync: %s: %w", change, err)
+		}
+	}
+
+	set, err := s.getStickerSet(ctx, plan.manifest.Name)
+	if err != nil {
+		return plan, err
+	}
+	if set == nil || len(set.Stickers) != len(plan.order) {
+		return plan, errors.New("sticker sync: sticker set does not contain the expected stickers")
+	}
+
+	lock := stickerLock{Stickers: make(map[string]stickerLockEntry, len(plan.order))}
+	fileIDs := make(map[string]string, len(plan.order))
+	for i, file := range plan.order {
+		fileIDs[file] = set.Stickers[i].FileID
+		lock.Stickers[file] = stickerLockEntry{
+			SHA256:       plan.hashes[file],
+			FileUniqueID: set.Stickers[i].FileUniqueID,
+			EmojiList:    entries[file].EmojiList,
+			Keywords:     entries[file].Keywords,
+		}
+	}
+
+	for _, change := range plan.Changes {
+		if change.Kind != StickerChangeMove {
+			continue
+		}
+		move := SetStickerPositionConfig{Sticker: fileIDs[change.File], Position: change.Position}
+		if _, err := s.Bot.RequestWithContext(ctx, move); err != nil {
+			return plan, fmt.Errorf("sticker sync: %s: %w", change, err)
+		}
+	}
+
+	return plan, s.writeLock(lock)
+}
+
+func (s *StickerSync) apply(ctx context.Context, manifest StickerManifest, entry StickerManifestEntry, change StickerChange) error {
+	var config Chattable
+
+	switch change.Kind {
+	case StickerChangeCreateSet, StickerChangeReplace, StickerChangeAdd:
+		sticker, err := s.uploadSticker(ctx, entry)
+		if err != nil {
+			return err
+		}
+
+		switch change.Kind {
+		case StickerChangeCreateSet:
+			config = NewStickerSetConfig{
+				UserID:          s.UserID,
+				Name:            manifest.Name,
+				Title:           manifest.Title,
+				Stickers:        []InputSticker{sticker},
+				StickerType:     manifest.StickerType,
+				NeedsRepainting: manifest.NeedsRepainting,
+			}
+		case StickerChangeReplace:
+			config = ReplaceStickerInSetConfig{
+				UserID:     s.UserID,
+				Name:       manifest.Name,
+				OldSticker: change.Sticker,
+				Sticker:    sticker,
+			}
+		default:
+			config = AddStickerConfig{
+				UserID:  s.UserID,
+				Name:    manifest.Name,
+				Sticker: sticker,
+			}
+		}
+	case StickerChangeTitle:
+		config = SetStickerSetTitleConfig{Name: manifest.Name, Title: manifest.Title}
+	case StickerChangeDelete:
+		config = DeleteStickerConfig{Sticker: change.Sticker}
+	case StickerChangeEmojiList:
+		config = SetStickerEmojiListConfig{Sticker: change.Sticker, EmojiList: entry.EmojiList}
+	case StickerChangeKeywords:
+		config = SetStickerKeywordsConfig{Sticker: change.Sticker, Keywords: entry.Keywords}
+	default:
+		return fmt.Errorf("unknown change %q", change.Kind)
+	}
+
+	_, err := s.Bot.RequestWithContext(ctx, config)
+	return err
+}
+
+// uploadSticker uploads the file of a sticker with uploadStickerFile.
+func (s *StickerSync) uploadSticker(ctx context.Context, entry StickerManifestEntry) (InputSticker, error) {
+	upload := UploadStickerConfig{
+		UserID: s.UserID,
+		Sticker: RequestFile{
+			Name: "sticker",
+			Data: FilePath(filepath.Join(s.Dir, entry.File)),
+		},
+		StickerFormat: entry.Format,
+	}
+
+	file, err := Call(ctx, s.Bot, upload)
+	if err != nil {
+		return InputSticker{}, err
+	}
+
+	return InputSticker{
+		Sticker:   RequestFile{Name: "sticker", Data: FileID(file.FileID)},
+		Format:    entry.Format,
+		EmojiList: entry.EmojiList,
+		Keywords:  entry.Keywords,
+	}, nil
+}
+
+// getStickerSet returns the sticker set, or nil if it does not exist.
+func (s *StickerSync) getStickerSet(ctx context.Context, name string) (*StickerSet, error) {
+	set, err := Call(ctx, s.Bot, GetStickerSetConfig{Name: name})
+	var apiErr *Error
+	if errors.As(err, &apiErr) && strings.Contains(apiErr.Message, "STICKERSET_INVALID") {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sticker sync: get sticker set: %w", err)
+	}
+
+	return &set, nil
+}
+
+func (s *StickerSync) readManifest() (StickerManifest, error) {
+	var manifest StickerManifest
+	if err := s.readJSON(StickerManifestFile, &manifest); err != nil {
+		return manifest, err
+	}
+
+	if manifest.Name == "" {
+		return manifest, errors.New("sticker sync: manifest has no name")
+	}
+	if len(manifest.Stickers) == 0 {
+		return manifest, errors.New("sticker sync: manifest has no stickers")
+	}
+
+	seen := make(map[string]bool, len(manifest.Stickers))
+	for i, entry := range manifest.Stickers {
+		if seen[entry.File] {
+			return manifest, fmt.Errorf("sticker sync: %s is listed twice", entry.File)
+		}
+		seen[entry.File] = true
+
+		if entry.Format == "" {
+			entry.Format = stickerFormat(entry.File)
+		}
+		if entry.Format == "" {
+			return manifest, fmt.Errorf("sticker sync: unknown format of %s", entry.File)
+		}
+		if len(entry.EmojiList) == 0 {
+			return manifest, fmt.Errorf("sticker sync: %s has no emoji", entry.File)
+		}
+		manifest.Stickers[i] = entry
+	}
+
+	return manifest, nil
+}
+
+func (s *StickerSync) readJSON(name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil {
+		return fmt.Errorf("sticker sync: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("sticker sync: %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (s *StickerSync) writeLock(lock stickerLock) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(s.Dir, StickerLockFile), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("sticker sync: %w", err)
+	}
+
+	return nil
+}
+
+// stickerFormat returns the sticker format of a file from its extension.
+func stickerFormat(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".png", ".webp":
+		return "static"
+	case ".tgs":
+		return "animated"
+	case ".webm":
+		return "video"
+	}
+
+	return ""
+}
+
+// planStickerSync compares the manifest and the lock with the sticker set,
+// which is nil if it does not exist yet.
+func planStickerSync(manifest StickerManifest, lock stickerLock, set *StickerSet, hashes map[string]string) StickerSyncPlan {
+	plan := StickerSyncPlan{manifest: manifest, hashes: hashes}
+
+	if set == nil {
+		for i, entry := range manifest.Stickers {
+			kind := StickerChangeAdd
+			if i == 0 {
+				kind = StickerChangeCreateSet
+			}
+			plan.Changes = append(plan.Changes, StickerChange{Kind: kind, File: entry.File})
+			plan.order = append(plan.order, entry.File)
+		}
+		return plan
+	}
+
+	if manifest.Title != "" && manifest.Title != set.Title {
+		plan.Changes = append(plan.Changes, StickerChange{Kind: StickerChangeTitle, Value: []string{manifest.Title}})
+	}
+
+	files := make(map[string]string, len(lock.Stickers))
+	for file, entry := range lock.Stickers {
+		files[entry.FileUniqueID] = file
+	}
+
+	// Stickers which are not recorded in the lock or no longer listed in the
+	// manifest are deleted, once the other changes are made.
+	listed := make(map[string]bool, len(manifest.Stickers))
+	for _, entry := range manifest.Stickers {
+		listed[entry.File] = true
+	}
+	var deletes, updates, adds []StickerChange
+	existing := make(map[string]Sticker, len(set.Stickers))
+	for _, sticker := range set.Stickers {
+		file, ok := files[sticker.FileUniqueID]
+		if !ok || !listed[file] {
+			deletes = append(deletes, StickerChange{Kind: StickerChangeDelete, File: file, Sticker: sticker.FileID})
+			continue
+		}
+		existing[file] = sticker
+		plan.order = append(plan.order, file)
+	}
+
+	for _, entry := range manifest.Stickers {
+		sticker, ok := existing[entry.File]
+		if !ok {
+			adds = append(adds, StickerChange{Kind: StickerChangeAdd, File: entry.File})
+			plan.order = append(plan.order, entry.File)
+			continue
+		}
+
+		locked := lock.Stickers[entry.File]
+		if locked.SHA256 != hashes[entry.File] {
+			updates = append(updates, StickerChange{Kind: StickerChangeReplace, File: entry.File, Sticker: sticker.FileID})
+			continue
+		}
+		if !slices.Equal(locked.EmojiList, entry.EmojiList) {
+			updates = append(updates, StickerChange{Kind: StickerChangeEmojiList, File: entry.File, Sticker: sticker.FileID, Value: entry.EmojiList})
+		}
+		if !slices.Equal(locked.Keywords, entry.Keywords) {
+			updates = append(updates, StickerChange{Kind: StickerChangeKeywords, File: entry.File, Sticker: sticker.FileID, Value: entry.Keywords})
+		}
+	}
+
+	plan.Changes = append(plan.Changes, updates...)
+	plan.Changes = append(plan.Changes, adds...)
+	plan.Changes = append(plan.Changes, deletes...)
+
+	// Move stickers into place one position at a time.
+	order := slices.Clone(plan.order)
+	for i, entry := range manifest.Stickers {
+		if order[i] == entry.File {
+			continue
+		}
+
+		from := slices.Index(order, entry.File)
+		order = slices.Insert(slices.Delete(order, from, from+1), i, entry.File)
+		plan.Changes = append(plan.Changes, StickerChange{
+			Kind:     StickerChangeMove,
+			File:     entry.File,
+			Sticker:  existing[entry.File].FileID,
+			Position: i,
+		})
+	}
+
+	return plan
+}
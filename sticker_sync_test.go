@@ -0,0 +1,234 @@
+package tgbotapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeStickerSet keeps a sticker set for the sticker methods of a fake bot.
+type fakeStickerSet struct {
+	t        *testing.T
+	exists   bool
+	title    string
+	stickers []Sticker
+	uploads  int
+	calls    []string
+}
+
+func (f *fakeStickerSet) bot() *BotAPI {
+	return newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			method := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+			f.calls = append(f.calls, method)
+
+			if err := req.ParseMultipartForm(1 << 20); err != nil && err != http.ErrNotMultipart {
+				f.t.Fatalf("parse form: %v", err)
+			}
+			result, description := f.handle(method, req.Form)
+			body := `{"ok":true,"result":` + result + `}`
+			if description != "" {
+				body = `{"ok":false,"error_code":400,"description":` + strconv.Quote(description) + `}`
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		},
+	})
+}
+
+func (f *fakeStickerSet) handle(method string, form map[string][]string) (string, string) {
+	get := func(key string) string {
+		if values := form[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	inputSticker := func(data string) Sticker {
+		var input struct {
+			Sticker   string   `json:"sticker"`
+			EmojiList []string `json:"emoji_list"`
+		}
+		if err := json.Unmarshal([]byte(data), &input); err != nil {
+			f.t.Fatalf("decode sticker: %v", err)
+		}
+		return Sticker{FileID: input.Sticker, FileUniqueID: "u" + input.Sticker, Emoji: input.EmojiList[0]}
+	}
+	index := func(fileID string) int {
+		i := slices.IndexFunc(f.stickers, func(s Sticker) bool { return s.FileID == fileID })
+		if i < 0 {
+			f.t.Fatalf("%s: unknown sticker %q", method, fileID)
+		}
+		return i
+	}
+
+	switch method {
+	case "getStickerSet":
+		if !f.exists {
+			return "", "Bad Request: STICKERSET_INVALID"
+		}
+		data, _ := json.Marshal(StickerSet{Name: get("name"), Title: f.title, Stickers: f.stickers})
+		return string(data), ""
+	case "uploadStickerFile":
+		f.uploads++
+		return `{"file_id":"f` + strconv.Itoa(f.uploads) + `"}`, ""
+	case "createNewStickerSet":
+		var stickers []json.RawMessage
+		if err := json.Unmarshal([]byte(get("stickers")), &stickers); err != nil {
+			f.t.Fatalf("decode stickers: %v", err)
+		}
+		f.exists, f.title = true, get("title")
+		for _, sticker := range stickers {
+			f.stickers = append(f.stickers, inputSticker(string(sticker)))
+		}
+	case "addStickerToSet":
+		f.stickers = append(f.stickers, inputSticker(get("sticker")))
+	case "replaceStickerInSet":
+		f.stickers[index(get("old_sticker"))] = inputSticker(get("sticker"))
+	case "deleteStickerFromSet":
+		i := index(get("sticker"))
+		f.stickers = slices.Delete(f.stickers, i, i+1)
+	case "setStickerPositionInSet":
+		i := index(get("sticker"))
+		sticker := f.stickers[i]
+		position, _ := strconv.Atoi(get("position"))
+		f.stickers = slices.Insert(slices.Delete(f.stickers, i, i+1), position, sticker)
+	case "setStickerSetTitle":
+		f.title = get("title")
+	case "setStickerEmojiList", "setStickerKeywords":
+	default:
+		f.t.Fatalf("unexpected method %s", method)
+	}
+
+	return "true", ""
+}
+
+func writeStickerDir(t *testing.T, dir string, manifest StickerManifest, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, StickerManifestFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStickerSyncCreatesAndUpdatesSet(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fake := &fakeStickerSet{t: t}
+	sync := NewStickerSync(fake.bot(), 1, dir)
+
+	manifest := StickerManifest{
+		Name:  "pack_by_bot",
+		Title: "Pack",
+		Stickers: []StickerManifestEntry{
+			{File: "a.png", EmojiList: []string{"🅰️"}},
+			{File: "b.png", EmojiList: []string{"🅱️"}},
+			{File: "c.webm", EmojiList: []string{"©️"}},
+		},
+	}
+	writeStickerDir(t, dir, manifest, map[string]string{"a.png": "a", "b.png": "b", "c.webm": "c"})
+
+	plan, err := sync.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "+ create set with a.png\n+ add b.png\n+ add c.webm\n"; plan.String() != want {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	if len(fake.stickers) != 3 || fake.title != "Pack" {
+		t.Fatalf("unexpected set %q with %d stickers", fake.title, len(fake.stickers))
+	}
+
+	plan, err = sync.Plan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 0 {
+		t.Fatalf("expected no changes after a sync, got:\n%s", plan)
+	}
+
+	// Drop a, change b, retag c, add d and put d first.
+	manifest.Title = "New pack"
+	manifest.Stickers = []StickerManifestEntry{
+		{File: "d.tgs", EmojiList: []string{"🇩"}},
+		{File: "b.png", EmojiList: []string{"🅱️"}},
+		{File: "c.webm", EmojiList: []string{"©️", "🎥"}},
+	}
+	writeStickerDir(t, dir, manifest, map[string]string{"b.png": "b2", "d.tgs": "d"})
+
+	fake.calls = nil
+	plan, err = sync.Plan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "~ title \"New pack\"\n~ replace b.png\n~ emoji c.webm ©️ 🎥\n+ add d.tgs\n- delete a.png\n~ move d.tgs to 0\n"
+	if plan.String() != want {
+		t.Fatalf("unexpected plan:\n%s", plan)
+	}
+	if slices.Contains(fake.calls, "addStickerToSet") {
+		t.Fatal("expected Plan not to change the set")
+	}
+
+	if _, err := sync.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var emoji []string
+	for _, sticker := range fake.stickers {
+		emoji = append(emoji, sticker.Emoji)
+	}
+	if !slices.Equal(emoji, []string{"🇩", "🅱️", "©️"}) || fake.title != "New pack" {
+		t.Fatalf("unexpected set %q with stickers %v", fake.title, emoji)
+	}
+
+	plan, err = sync.Plan(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 0 {
+		t.Fatalf("expected no changes after a sync, got:\n%s", plan)
+	}
+}
+
+func TestStickerSyncRefusesExistingSetWithoutLock(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	fake := &fakeStickerSet{
+		t:        t,
+		exists:   true,
+		title:    "Pack",
+		stickers: []Sticker{{FileID: "old", FileUniqueID: "uold", Emoji: "🅰️"}},
+	}
+	sync := NewStickerSync(fake.bot(), 1, dir)
+
+	manifest := StickerManifest{
+		Name:     "pack_by_bot",
+		Stickers: []StickerManifestEntry{{File: "a.png", EmojiList: []string{"🅰️"}}},
+	}
+	writeStickerDir(t, dir, manifest, map[string]string{"a.png": "a"})
+
+	if _, err := sync.Sync(ctx); !errors.Is(err, ErrStickerLockMissing) {
+		t.Fatalf("expected ErrStickerLockMissing, got %v", err)
+	}
+	if len(fake.stickers) != 1 || slices.Contains(fake.calls, "deleteStickerFromSet") || slices.Contains(fake.calls, "addStickerToSet") {
+		t.Fatalf("expected the set not to change, got calls %v", fake.calls)
+	}
+}
@@ -0,0 +1,429 @@
+package template
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"text/template/parse"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// state is the kind of markup an action is written into.
+type state uint8
+
+const (
+	// stateText is plain text, including the text of formatting entities.
+	stateText state = iota
+	// stateCode is the content of inline code.
+	stateCode
+	// statePre is the content of a pre block.
+	statePre
+	// stateURL is the URL of a Markdown link.
+	stateURL
+	// stateTag is the inside of an HTML tag, outside of an attribute value.
+	stateTag
+	// stateAttr is a quoted HTML attribute value.
+	stateAttr
+)
+
+// context is the position in the template output reached after some text.
+type context struct {
+	state state
+	// delim is the quote closing an HTML attribute value, or the character
+	// closing the open legacy Markdown entity in stateText.
+	delim byte
+}
+
+func (c context) String() string {
+	switch c.state {
+	case stateCode:
+		return "inline code"
+	case statePre:
+		return "a pre block"
+	case stateURL:
+		return "a link URL"
+	case stateTag:
+		return "an HTML tag"
+	case stateAttr:
+		return "an HTML attribute value"
+	}
+	switch c.delim {
+	case '*':
+		return "bold text"
+	case '_':
+		return "italic text"
+	case ']':
+		return "link text"
+	}
+	return "text"
+}
+
+// escaper rewrites the actions of a single template tree.
+type escaper struct {
+	parseMode string
+	tree      *parse.Tree
+	// loops holds the contexts in which the enclosing range bodies start.
+	loops []context
+}
+
+func (e *escaper) errorf(node parse.Node, format string, args ...any) error {
+	location, _ := e.tree.ErrorContext(node)
+	return fmt.Errorf("template: %s: %s", location, fmt.Sprintf(format, args...))
+}
+
+func (e *escaper) escapeList(c context, list *parse.ListNode) (context, error) {
+	if list == nil {
+		return c, nil
+	}
+
+	for _, node := range list.Nodes {
+		var err error
+		if c, err = e.escapeNode(c, node); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+func (e *escaper) escapeNode(c context, node parse.Node) (context, error) {
+	switch n := node.(type) {
+	case *parse.TextNode:
+		return e.scan(c, string(n.Text)), nil
+	case *parse.ActionNode:
+		return c, e.escapeAction(c, n)
+	case *parse.IfNode:
+		return e.escapeBranch(c, &n.BranchNode, "if")
+	case *parse.WithNode:
+		return e.escapeBranch(c, &n.BranchNode, "with")
+	case *parse.RangeNode:
+		return e.escapeRange(c, n)
+	case *parse.TemplateNode:
+		if c != (context{}) {
+			return c, e.errorf(n, "{{template %q}} is called inside %s", n.Name, c)
+		}
+		return c, nil
+	case *parse.BreakNode, *parse.ContinueNode:
+		if len(e.loops) > 0 && c != e.loops[len(e.loops)-1] {
+			return c, e.errorf(n, "range is left inside %s", c)
+		}
+		return c, nil
+	case *parse.ListNode:
+		return e.escapeList(c, n)
+	}
+
+	return c, nil
+}
+
+func (e *escaper) escapeAction(c context, n *parse.ActionNode) error {
+	// Assignments do not produce output.
+	if len(n.Pipe.Decl) > 0 {
+		return nil
+	}
+
+	name, err := escaperName(e.parseMode, c)
+	if err != nil {
+		return e.errorf(n, "%v", err)
+	}
+
+	n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      n.Pos,
+		Args:     []parse.Node{parse.NewIdentifier(name).SetTree(e.tree).SetPos(n.Pos)},
+	})
+
+	return nil
+}
+
+func (e *escaper) escapeBranch(c context, n *parse.BranchNode, keyword string) (context, error) {
+	then, err := e.escapeList(c, n.List)
+	if err != nil {
+		return c, err
+	}
+	otherwise, err := e.escapeList(c, n.ElseList)
+	if err != nil {
+		return c, err
+	}
+
+	if then != otherwise {
+		return c, e.errorf(n, "{{%s}} branches end in different contexts: %s, %s", keyword, then, otherwise)
+	}
+
+	return then, nil
+}
+
+func (e *escaper) escapeRange(c context, n *parse.RangeNode) (context, error) {
+	e.loops = append(e.loops, c)
+	body, err := e.escapeList(c, n.List)
+	e.loops = e.loops[:len(e.loops)-1]
+	if err != nil {
+		return c, err
+	}
+	if body != c {
+		return c, e.errorf(n, "{{range}} body starts in %s but ends in %s", c, body)
+	}
+
+	otherwise, err := e.escapeList(c, n.ElseList)
+	if err != nil {
+		return c, err
+	}
+	if otherwise != c {
+		return c, e.errorf(n, "{{range}} branches end in different contexts: %s, %s", c, otherwise)
+	}
+
+	return c, nil
+}
+
+// scan returns the context reached after writing text in context c.
+func (e *escaper) scan(c context, text string) context {
+	switch e.parseMode {
+	case tgbotapi.ModeHTML:
+		return scanHTML(c, text)
+	case tgbotapi.ModeMarkdown:
+		return scanMarkdown(c, text)
+	}
+	return scanMarkdownV2(c, text)
+}
+
+func scanMarkdownV2(c context, text string) context {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\\' {
+			i++
+			continue
+		}
+
+		switch c.state {
+		case stateText:
+			switch {
+			case strings.HasPrefix(text[i:], "```"):
+				c.state = statePre
+				i += 2
+			case text[i] == '`':
+				c.state = stateCode
+			case strings.HasPrefix(text[i:], "]("):
+				c.state = stateURL
+				i++
+			}
+		case statePre:
+			if strings.HasPrefix(text[i:], "```") {
+				c.state = stateText
+				i += 2
+			}
+		case stateCode:
+			if text[i] == '`' {
+				c.state = stateText
+			}
+		case stateURL:
+			if text[i] == ')' {
+				c.state = stateText
+			}
+		}
+	}
+
+	return c
+}
+
+func scanMarkdown(c context, text string) context {
+	for i := 0; i < len(text); i++ {
+		switch c.state {
+		case stateText:
+			switch {
+			case c.delim == 0 && text[i] == '\\':
+				i++
+			case c.delim == 0 && strings.HasPrefix(text[i:], "```"):
+				c.state = statePre
+				i += 2
+			case c.delim == 0 && text[i] == '`':
+				c.state = stateCode
+			case c.delim == 0 && (text[i] == '*' || text[i] == '_'):
+				c.delim = text[i]
+			case c.delim == 0 && text[i] == '[':
+				c.delim = ']'
+			case c.delim == ']' && strings.HasPrefix(text[i:], "]("):
+				c = context{state: stateURL}
+				i++
+			case text[i] == c.delim:
+				c.delim = 0
+			}
+		case statePre:
+			if strings.HasPrefix(text[i:], "```") {
+				c.state = stateText
+				i += 2
+			}
+		case stateCode:
+			if text[i] == '`' {
+				c.state = stateText
+			}
+		case stateURL:
+			if text[i] == ')' {
+				c.state = stateText
+			}
+		}
+	}
+
+	return c
+}
+
+func scanHTML(c context, text string) context {
+	for i := 0; i < len(text); i++ {
+		switch c.state {
+		case stateText:
+			if text[i] == '<' {
+				c.state = stateTag
+			}
+		case stateTag:
+			switch text[i] {
+			case '>':
+				c.state = stateText
+			case '"', '\'':
+				c = context{state: stateAttr, delim: text[i]}
+			}
+		case stateAttr:
+			if text[i] == c.delim {
+				c = context{state: stateTag}
+			}
+		}
+	}
+
+	return c
+}
+
+// Names of the functions appended to the pipelines of actions.
+const (
+	escapeHTMLText       = "_tg_escape_html_text"
+	escapeHTMLAttr       = "_tg_escape_html_attr"
+	escapeMarkdownV2Text = "_tg_escape_markdownv2_text"
+	escapeMarkdownV2Code = "_tg_escape_markdownv2_code"
+	escapeMarkdownV2URL  = "_tg_escape_markdownv2_url"
+	escapeMarkdownText   = "_tg_escape_markdown_text"
+	escapeMarkdownBold   = "_tg_escape_markdown_bold"
+	escapeMarkdownItalic = "_tg_escape_markdown_italic"
+	escapeMarkdownLink   = "_tg_escape_markdown_link"
+	escapeMarkdownCode   = "_tg_escape_markdown_code"
+	escapeMarkdownURL    = "_tg_escape_markdown_url"
+)
+
+// escaperName returns the name of the escaping function for values written
+// in context c.
+func escaperName(parseMode string, c context) (string, error) {
+	switch parseMode {
+	case tgbotapi.ModeHTML:
+		switch c.state {
+		case stateText:
+			return escapeHTMLText, nil
+		case stateAttr:
+			return escapeHTMLAttr, nil
+		}
+	case tgbotapi.ModeMarkdownV2:
+		switch c.state {
+		case stateText:
+			return escapeMarkdownV2Text, nil
+		case stateCode, statePre:
+			return escapeMarkdownV2Code, nil
+		case stateURL:
+			return escapeMarkdownV2URL, nil
+		}
+	case tgbotapi.ModeMarkdown:
+		switch c.state {
+		case stateText:
+			switch c.delim {
+			case '*':
+				return escapeMarkdownBold, nil
+			case '_':
+				return escapeMarkdownItalic, nil
+			case ']':
+				return escapeMarkdownLink, nil
+			}
+			return escapeMarkdownText, nil
+		case stateCode, statePre:
+			return escapeMarkdownCode, nil
+		case stateURL:
+			return escapeMarkdownURL, nil
+		}
+	}
+
+	return "", fmt.Errorf("cannot write a value inside %s, use a quoted attribute value", c)
+}
+
+// escapeFuncs returns the escaping functions used by templates.
+func escapeFuncs() FuncMap {
+	return FuncMap{
+		escapeHTMLText:       escapeTrusted(html.EscapeString),
+		escapeHTMLAttr:       escapeValue(html.EscapeString),
+		escapeMarkdownV2Text: escapeTrusted(backslashEscaper("\\_*[]()~`>#+-=|{}.!")),
+		escapeMarkdownV2Code: escapeValue(backslashEscaper("\\`")),
+		escapeMarkdownV2URL:  escapeValue(backslashEscaper("\\)")),
+		escapeMarkdownText:   escapeTrusted(backslashEscaper("_*`[")),
+		// Legacy Markdown cannot escape characters inside an entity, so the
+		// character that would close the entity is dropped instead.
+		escapeMarkdownBold:   escapeTrusted(remover("*")),
+		escapeMarkdownItalic: escapeTrusted(remover("_")),
+		escapeMarkdownLink:   escapeTrusted(remover("]")),
+		escapeMarkdownCode:   escapeValue(remover("`")),
+		escapeMarkdownURL:    escapeValue(strings.NewReplacer(")", "%29").Replace),
+	}
+}
+
+// escapeValue returns a template function writing its arguments escaped.
+func escapeValue(escape func(string) string) func(...any) string {
+	return func(args ...any) string {
+		return escape(stringify(args...))
+	}
+}
+
+// escapeTrusted is like escapeValue, but writes a Markup value verbatim.
+func escapeTrusted(escape func(string) string) func(...any) string {
+	return func(args ...any) string {
+		if len(args) == 1 {
+			if markup, ok := args[0].(Markup); ok {
+				return string(markup)
+			}
+		}
+		return escape(stringify(args...))
+	}
+}
+
+// stringify formats the arguments of an escaping function the way
+// text/template prints values.
+func stringify(args ...any) string {
+	if len(args) == 1 {
+		switch v := args[0].(type) {
+		case nil:
+			return ""
+		case string:
+			return v
+		case Markup:
+			return string(v)
+		}
+	}
+	return fmt.Sprint(args...)
+}
+
+// backslashEscaper returns a function prefixing every character of special
+// with a backslash.
+func backslashEscaper(special string) func(string) string {
+	return func(s string) string {
+		var sb strings.Builder
+		sb.Grow(len(s))
+		for _, r := range s {
+			if strings.ContainsRune(special, r) {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	}
+}
+
+// remover returns a function removing every character of chars.
+func remover(chars string) func(string) string {
+	return func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if strings.ContainsRune(chars, r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+}
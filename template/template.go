@@ -0,0 +1,177 @@
+// Package template implements data-driven templates for Telegram message text
+// that escape interpolated values for the target parse mode.
+//
+// It wraps text/template in the same way html/template does: templates are
+// written in the markup of a parse mode (tgbotapi.ModeHTML,
+// tgbotapi.ModeMarkdownV2 or tgbotapi.ModeMarkdown) and every value written by
+// an action is escaped according to where it appears, so user supplied data
+// can never break or inject formatting.
+//
+//	tmpl := template.Must(template.New("greeting", tgbotapi.ModeMarkdownV2).Parse(
+//		"*Hello, {{.Name}}!* Your code is `{{.Code}}`, see [docs]({{.URL}})",
+//	))
+//	text, err := tmpl.ExecuteString(data)
+//
+// The recognised contexts are plain text (including bold, italic, link text
+// and other formatting), inline code, pre blocks, link URLs and, for HTML,
+// quoted attribute values. Values of type Markup are trusted and inserted
+// verbatim in plain text.
+package template
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// FuncMap is the type of the map defining the mapping from names to
+// functions, see text/template.FuncMap.
+type FuncMap = template.FuncMap
+
+// Markup is text already formatted for the parse mode of the template.
+// It is not escaped when written in plain text context.
+type Markup string
+
+// Template is a text/template whose output is safe to send with its parse mode.
+type Template struct {
+	text      *template.Template
+	parseMode string
+
+	mu      sync.Mutex
+	escaped bool
+	// escapeErr is the error escaping the templates failed with. The trees
+	// are partly rewritten then, so they are never escaped again.
+	escapeErr error
+}
+
+// New allocates a new template with the given name for a parse mode.
+func New(name, parseMode string) *Template {
+	t := &Template{
+		text:      template.New(name),
+		parseMode: parseMode,
+	}
+	t.text.Funcs(escapeFuncs())
+	return t
+}
+
+// Must panics if err is not nil, and returns t otherwise.
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Name returns the name of the template.
+func (t *Template) Name() string {
+	return t.text.Name()
+}
+
+// ParseMode returns the parse mode the template escapes for.
+func (t *Template) ParseMode() string {
+	return t.parseMode
+}
+
+// Funcs adds the elements of funcMap to the template's function map.
+func (t *Template) Funcs(funcMap FuncMap) *Template {
+	t.text.Funcs(funcMap)
+	return t
+}
+
+// Option sets options for the template, see text/template.Template.Option.
+func (t *Template) Option(opt ...string) *Template {
+	t.text.Option(opt...)
+	return t
+}
+
+// Parse parses text as a template body for t.
+// It returns an error if the template has already been executed.
+func (t *Template) Parse(text string) (*Template, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.escaped {
+		return nil, fmt.Errorf("template: %s: cannot Parse after Execute", t.Name())
+	}
+	if !isSupportedParseMode(t.parseMode) {
+		return nil, fmt.Errorf("template: %s: unsupported parse mode %q", t.Name(), t.parseMode)
+	}
+
+	if _, err := t.text.Parse(text); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Execute applies the template to data and writes the output to w.
+func (t *Template) Execute(w io.Writer, data any) error {
+	if err := t.escape(); err != nil {
+		return err
+	}
+	return t.text.Execute(w, data)
+}
+
+// ExecuteTemplate applies the template associated with t that has the given
+// name to data and writes the output to w.
+func (t *Template) ExecuteTemplate(w io.Writer, name string, data any) error {
+	if err := t.escape(); err != nil {
+		return err
+	}
+	return t.text.ExecuteTemplate(w, name, data)
+}
+
+// ExecuteString applies the template to data and returns the output.
+func (t *Template) ExecuteString(data any) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// escape rewrites the parsed templates once so every action ends with the
+// escaper for its context. If it fails, every later call returns the same
+// error.
+func (t *Template) escape() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.escaped {
+		t.escaped = true
+		t.escapeErr = t.escapeTrees()
+	}
+
+	return t.escapeErr
+}
+
+func (t *Template) escapeTrees() error {
+	for _, tmpl := range t.text.Templates() {
+		if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+			continue
+		}
+
+		e := escaper{parseMode: t.parseMode, tree: tmpl.Tree}
+		end, err := e.escapeList(context{}, tmpl.Tree.Root)
+		if err != nil {
+			return err
+		}
+		if end != (context{}) {
+			return fmt.Errorf("template: %s: ends inside %s", tmpl.Name(), end)
+		}
+	}
+
+	return nil
+}
+
+func isSupportedParseMode(parseMode string) bool {
+	switch parseMode {
+	case tgbotapi.ModeHTML, tgbotapi.ModeMarkdownV2, tgbotapi.ModeMarkdown:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+func TestTemplateEscapesByContext(t *testing.T) {
+	data := map[string]any{
+		"Name": "a_b*c [x](y) 1.5!",
+		"Code": "x := `y` \\ z",
+		"URL":  "https://example.com/a_(b)",
+		"Tag":  Markup("<b>bold</b>"),
+	}
+
+	tests := []struct {
+		parseMode string
+		text      string
+		expected  string
+	}{
+		{
+			tgbotapi.ModeMarkdownV2,
+			"*Hi {{.Name}}* `{{.Code}}` [link]({{.URL}})\n```go\n{{.Code}}\n```",
+			"*Hi a\\_b\\*c \\[x\\]\\(y\\) 1\\.5\\!* `x := \\`y\\` \\\\ z` [link](https://example.com/a_(b\\))\n```go\nx := \\`y\\` \\\\ z\n```",
+		},
+		{
+			tgbotapi.ModeMarkdown,
+			"{{.Name}} *{{.Name}}* `{{.Code}}` [{{.Name}}]({{.URL}})",
+			"a\\_b\\*c \\[x](y) 1.5! *a_bc [x](y) 1.5!* `x := y \\ z` [a_b*c [x(y) 1.5!](https://example.com/a_(b%29)",
+		},
+		{
+			tgbotapi.ModeHTML,
+			`<a href="{{.URL}}&q={{.Name}}">{{.Name}} & {{.Tag}}</a> <code>{{.Code}}</code>`,
+			`<a href="https://example.com/a_(b)&q=a_b*c [x](y) 1.5!">a_b*c [x](y) 1.5! & <b>bold</b></a> <code>x := ` + "`y`" + ` \ z</code>`,
+		},
+	}
+
+	for _, test := range tests {
+		tmpl := Must(New("test", test.parseMode).Parse(test.text))
+		got, err := tmpl.ExecuteString(data)
+		if err != nil {
+			t.Fatalf("%s: %v", test.parseMode, err)
+		}
+		if got != test.expected {
+			t.Fatalf("%s: unexpected output:\n%s\nexpected:\n%s", test.parseMode, got, test.expected)
+		}
+	}
+}
+
+func TestTemplateEscapesHTMLSpecialCharacters(t *testing.T) {
+	tmpl := Must(New("html", tgbotapi.ModeHTML).Parse(`<a href="{{.}}">{{.}}</a>`))
+	got, err := tmpl.ExecuteString(`"><b>x</b>&`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `<a href="&#34;&gt;&lt;b&gt;x&lt;/b&gt;&amp;">&#34;&gt;&lt;b&gt;x&lt;/b&gt;&amp;</a>`; got != expected {
+		t.Fatalf("unexpected output: %s", got)
+	}
+}
+
+func TestTemplateControlFlowAndNestedTemplates(t *testing.T) {
+	tmpl := Must(New("list", tgbotapi.ModeMarkdownV2).Parse(
+		`{{define "item"}}\- {{.}}{{end}}{{range .}}{{template "item" .}}{{"\n"}}{{else}}_empty_{{end}}`,
+	))
+
+	got, err := tmpl.ExecuteString([]string{"a.b", "c!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "\\- a\\.b\n\\- c\\!\n"; got != expected {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestTemplateRejectsAmbiguousContexts(t *testing.T) {
+	tests := []struct {
+		parseMode string
+		text      string
+		err       string
+	}{
+		{tgbotapi.ModeMarkdownV2, "{{if .}}`{{end}}x", "branches end in different contexts"},
+		{tgbotapi.ModeMarkdownV2, "{{range .}}`{{.}}{{end}}`", "body starts in text but ends in inline code"},
+		{tgbotapi.ModeMarkdownV2, "`{{template \"x\"}}`{{define \"x\"}}{{end}}", "called inside inline code"},
+		{tgbotapi.ModeMarkdownV2, "[link]({{.}}", "ends inside a link URL"},
+		{tgbotapi.ModeHTML, "<a {{.}}>x</a>", "cannot write a value inside an HTML tag"},
+	}
+
+	for _, test := range tests {
+		tmpl := Must(New("bad", test.parseMode).Parse(test.text))
+		_, err := tmpl.ExecuteString("x")
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Fatalf("%q: expected error containing %q, got %v", test.text, test.err, err)
+		}
+	}
+}
+
+func TestTemplateEscapeErrorIsPermanent(t *testing.T) {
+	tmpl := Must(New("a", tgbotapi.ModeMarkdownV2).Parse(`{{define "b"}}*{{.}}*{{end}}{{define "c"}}` + "`" + `{{end}}{{template "b" .}}`))
+
+	for i := 0; i < 2; i++ {
+		_, err := tmpl.ExecuteString("x.y")
+		if err == nil || !strings.Contains(err.Error(), "ends inside inline code") {
+			t.Fatalf("execution %d: expected the escaping error, got %v", i+1, err)
+		}
+	}
+
+	// Redefining the failing template would execute the others with their
+	// escapers added twice.
+	if _, err := tmpl.Parse(`{{define "c"}}{{end}}`); err == nil {
+		t.Fatal("expected error parsing after a failed execute")
+	}
+}
+
+func TestTemplateParseAfterExecute(t *testing.T) {
+	tmpl := Must(New("once", tgbotapi.ModeHTML).Parse("{{.}}"))
+	if _, err := tmpl.ExecuteString("x"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Parse("{{.}}"); err == nil {
+		t.Fatal("expected error parsing after execute")
+	}
+
+	if _, err := New("mode", "Plain").Parse("x"); err == nil {
+		t.Fatal("expected error for unsupported parse mode")
+	}
+}
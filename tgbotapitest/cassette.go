@@ -0,0 +1,390 @@
+package tgbotapitest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// RecordEnv is the environment variable which makes UseCassette record new
+// cassettes instead of replaying them.
+const RecordEnv = "TGBOTAPI_RECORD"
+
+// RedactedToken replaces the bot token in cassettes.
+const RedactedToken = "<TOKEN>"
+
+// ErrUnexpectedRequest is returned by a Replayer for requests which are not
+// in its cassette.
+var ErrUnexpectedRequest = errors.New("tgbotapitest: unexpected request")
+
+// Cassette is a recording of the requests made by a bot and the responses
+// of the Bot API.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and its response.
+type Interaction struct {
+	// Method is the Bot API method of the request. It is empty for file
+	// downloads.
+	Method string `json:"method,omitempty"`
+	// FilePath is the path of a downloaded file.
+	FilePath string     `json:"file_path,omitempty"`
+	Params   url.Values `json:"params,omitempty"`
+	// Files are the uploaded files, by form field.
+	Files map[string]CassetteFile `json:"files,omitempty"`
+
+	StatusCode int `json:"status_code"`
+	// Response is the response body if it is JSON, otherwise it is kept in
+	// Body.
+	Response json.RawMessage `json:"response,omitempty"`
+	Body     []byte          `json:"body,omitempty"`
+}
+
+// CassetteFile identifies an uploaded file by its hash, so cassettes do not
+// contain the uploaded data.
+type CassetteFile struct {
+	// Name is the base name of the file. It is not part of the match, so
+	// cassettes replay the same content uploaded from another directory.
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// LoadCassette reads a cassette from a file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("tgbotapitest: decode cassette %s: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette to a file, creating its directory if needed.
+func (c *Cassette) Save(path string) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// Recorder is an HTTPClient which sends requests with another client and
+// records them in a cassette. The bot token is redacted from the recording.
+type Recorder struct {
+	Client tgbotapi.HTTPClient
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder creates a Recorder sending requests with client, or
+// http.DefaultClient if it is nil.
+func NewRecorder(client tgbotapi.HTTPClient) *Recorder {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Recorder{Client: client}
+}
+
+// Do sends a request and records it with its response.
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	interaction, token, err := readInteraction(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	interaction.StatusCode = resp.StatusCode
+	redacted := redact(string(body), token)
+	if json.Valid([]byte(redacted)) {
+		interaction.Response = json.RawMessage(redacted)
+	} else {
+		interaction.Body = body
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Cassette returns the recorded interactions.
+func (r *Recorder) Cassette() *Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Cassette{Interactions: append([]Interaction(nil), r.cassette.Interactions...)}
+}
+
+// Replayer is an HTTPClient which serves the responses of a cassette.
+//
+// A request is answered with the first unused interaction with the same
+// method, params and uploaded files, so requests made concurrently may be
+// replayed in any order. Other requests fail with ErrUnexpectedRequest,
+// except getUpdates, which blocks until its context is done like a long
+// poll without updates.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer creates a Replayer serving the interactions of a cassette.
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// Do serves the recorded response of a request.
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	interaction, _, err := readInteraction(req)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, ok := r.take(interaction)
+	if !ok {
+		if interaction.Method == "getUpdates" {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedRequest, describeInteraction(interaction))
+	}
+
+	body := recorded.Body
+	header := make(http.Header)
+	if recorded.Response != nil {
+		body = recorded.Response
+		header.Set("Content-Type", "application/json")
+	}
+
+	return &http.Response{
+		Status:        http.StatusText(recorded.StatusCode),
+		StatusCode:    recorded.StatusCode,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Unused returns the interactions which have not been replayed.
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+func (r *Replayer) take(request Interaction) (Interaction, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || !sameRequest(interaction, request) {
+			continue
+		}
+		r.used[i] = true
+		return interaction, true
+	}
+	return Interaction{}, false
+}
+
+// UseCassette returns an HTTPClient for a test using the cassette at path.
+//
+// When the RecordEnv environment variable is set, requests are sent with
+// client and recorded, and the cassette is written when the test ends.
+// Otherwise the cassette is replayed: the test fails on requests which are
+// not in the cassette and on recorded requests which were never made.
+func UseCassette(t testing.TB, path string, client tgbotapi.HTTPClient) tgbotapi.HTTPClient {
+	t.Helper()
+
+	if os.Getenv(RecordEnv) != "" {
+		recorder := NewRecorder(client)
+		t.Cleanup(func() {
+			if err := recorder.Cassette().Save(path); err != nil {
+				t.Errorf("save cassette: %v", err)
+			}
+		})
+		return recorder
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("load cassette (set %s=1 to record it): %v", RecordEnv, err)
+	}
+	replayer := NewReplayer(cassette)
+	t.Cleanup(func() {
+		for _, interaction := range replayer.Unused() {
+			t.Errorf("tgbotapitest: request not made: %s", describeInteraction(interaction))
+		}
+	})
+
+	return testReplayer{t: t, replayer: replayer}
+}
+
+// testReplayer fails a test on unexpected requests.
+type testReplayer struct {
+	t        testing.TB
+	replayer *Replayer
+}
+
+func (r testReplayer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := r.replayer.Do(req)
+	if errors.Is(err, ErrUnexpectedRequest) {
+		r.t.Error(err)
+	}
+	return resp, err
+}
+
+// readInteraction reads the method, params and files of a request, leaving
+// its body readable again. It returns the bot token found in the URL.
+func readInteraction(req *http.Request) (Interaction, string, error) {
+	var interaction Interaction
+
+	prefix, rest, ok := strings.Cut(req.URL.Path, "/bot")
+	if !ok {
+		return interaction, "", fmt.Errorf("tgbotapitest: no bot token in %s", req.URL.Path)
+	}
+	token, rest, _ := strings.Cut(rest, "/")
+	if strings.HasSuffix(prefix, "/file") {
+		interaction.FilePath = rest
+	} else {
+		interaction.Method = rest
+	}
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return interaction, token, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return interaction, token, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	parsed := &http.Request{
+		Method: http.MethodPost,
+		Header: req.Header,
+		URL:    &url.URL{},
+		Body:   io.NopCloser(bytes.NewReader(body)),
+	}
+	if err := parsed.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+		return interaction, token, fmt.Errorf("tgbotapitest: parse request: %w", err)
+	}
+
+	if len(parsed.PostForm) > 0 {
+		interaction.Params = make(url.Values)
+		for key, values := range parsed.PostForm {
+			for _, value := range values {
+				interaction.Params.Add(key, redact(value, token))
+			}
+		}
+	}
+
+	if parsed.MultipartForm != nil && len(parsed.MultipartForm.File) > 0 {
+		interaction.Files = make(map[string]CassetteFile)
+		for field, headers := range parsed.MultipartForm.File {
+			f, err := headers[0].Open()
+			if err != nil {
+				return interaction, token, err
+			}
+			hash := sha256.New()
+			size, err := io.Copy(hash, f)
+			f.Close()
+			if err != nil {
+				return interaction, token, err
+			}
+			interaction.Files[field] = CassetteFile{
+				Name:   path.Base(strings.ReplaceAll(headers[0].Filename, "\\", "/")),
+				SHA256: hex.EncodeToString(hash.Sum(nil)),
+				Size:   size,
+			}
+		}
+	}
+
+	return interaction, token, nil
+}
+
+func sameRequest(a, b Interaction) bool {
+	return a.Method == b.Method &&
+		a.FilePath == b.FilePath &&
+		reflect.DeepEqual(a.Params, b.Params) &&
+		maps.EqualFunc(a.Files, b.Files, func(a, b CassetteFile) bool {
+			return a.SHA256 == b.SHA256 && a.Size == b.Size
+		})
+}
+
+func describeInteraction(interaction Interaction) string {
+	if interaction.Method == "" {
+		return "download " + interaction.FilePath
+	}
+
+	description := interaction.Method
+	if len(interaction.Params) > 0 {
+		description += " " + interaction.Params.Encode()
+	}
+	for field, file := range interaction.Files {
+		description += fmt.Sprintf(" %s=%s(sha256:%s)", field, file.Name, file.SHA256)
+	}
+	return description
+}
+
+func redact(s, token string) string {
+	if token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, token, RedactedToken)
+}
@@ -0,0 +1,137 @@
+package tgbotapitest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// sendAndDownload sends a message and a document and downloads the document.
+func sendAndDownload(bot *tgbotapi.BotAPI, chatID int64) (string, error) {
+	if _, err := bot.Send(tgbotapi.NewMessage(chatID, "hello")); err != nil {
+		return "", err
+	}
+	msg, err := bot.Send(tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: "a.txt", Bytes: []byte("content")}))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if _, err := bot.DownloadFile(msg.Document.FileID, &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "session.json")
+
+	server := NewServer()
+	user := NewUser(7, "Bob")
+	server.AddChat(PrivateChat(user))
+
+	recorder := NewRecorder(server.Client())
+	bot, err := server.NewBot(tgbotapi.WithHTTPClient(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sendAndDownload(bot, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+
+	if err := recorder.Cassette().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), server.Token) {
+		t.Fatalf("expected the token to be redacted:\n%s", data)
+	}
+	if strings.Contains(string(data), "content") {
+		t.Fatal("expected uploads to be recorded by hash")
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var methods []string
+	for _, interaction := range cassette.Interactions {
+		methods = append(methods, interaction.Method)
+	}
+	if want := []string{"getMe", "sendMessage", "sendDocument", "getFile", ""}; !slices.Equal(methods, want) {
+		t.Fatalf("expected interactions %q, got %q", want, methods)
+	}
+
+	// The server is closed, so everything is served from the cassette.
+	replayer := NewReplayer(cassette)
+	bot, err = tgbotapi.NewBotAPIWithOptions("1:other", append(server.Options(), tgbotapi.WithHTTPClient(replayer))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := sendAndDownload(bot, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "content" {
+		t.Fatalf("replayed download %q", content)
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Fatalf("expected all interactions to be replayed, %d left", len(unused))
+	}
+
+	_, err = bot.Send(tgbotapi.NewMessage(user.ID, "hello"))
+	if !errors.Is(err, ErrUnexpectedRequest) {
+		t.Fatalf("expected an unexpected request error, got %v", err)
+	}
+
+	replayer = NewReplayer(cassette)
+	bot.Client = replayer
+	_, err = bot.Send(tgbotapi.NewDocument(user.ID, tgbotapi.FileBytes{Name: "a.txt", Bytes: []byte("changed")}))
+	if !errors.Is(err, ErrUnexpectedRequest) {
+		t.Fatalf("expected a changed upload to be unexpected, got %v", err)
+	}
+}
+
+func TestCassetteMatchesUploadsByContent(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	user := NewUser(7, "Bob")
+	server.AddChat(PrivateChat(user))
+
+	recordDir, replayDir := t.TempDir(), t.TempDir()
+	for _, dir := range []string{recordDir, replayDir} {
+		if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("content"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recorder := NewRecorder(server.Client())
+	bot, err := server.NewBot(tgbotapi.WithHTTPClient(recorder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Send(tgbotapi.NewDocument(user.ID, tgbotapi.FilePath(filepath.Join(recordDir, "a.txt")))); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette := recorder.Cassette()
+	file := cassette.Interactions[len(cassette.Interactions)-1].Files["document"]
+	if file.Name != "a.txt" {
+		t.Fatalf("expected the base name of the upload to be recorded, got %q", file.Name)
+	}
+
+	bot.Client = NewReplayer(cassette)
+	if _, err := bot.Send(tgbotapi.NewDocument(user.ID, tgbotapi.FilePath(filepath.Join(replayDir, "a.txt")))); err != nil {
+		t.Fatalf("expected the upload from another directory to be replayed, got %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+package tgbotapitest
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf16"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// TestUser is the user sending fixture updates by default.
+var TestUser = tgbotapi.User{
+	ID:           42,
+	FirstName:    "Alice",
+	UserName:     "alice",
+	LanguageCode: "en",
+}
+
+// TestBot is the user of the bot of a Server or a Conversation by default.
+var TestBot = tgbotapi.User{
+	ID:        1000,
+	IsBot:     true,
+	FirstName: "Test Bot",
+	UserName:  "test_bot",
+}
+
+// TestGroup is the chat of fixture chat member updates by default.
+var TestGroup = tgbotapi.Chat{
+	ID:    -1001000000042,
+	Type:  "supergroup",
+	Title: "Test Group",
+}
+
+// Fixture IDs are shared by all fixtures, so they increase monotonically
+// like the IDs Telegram sends.
+var (
+	lastUpdateID  atomic.Int64
+	lastMessageID atomic.Int64
+	lastQueryID   atomic.Int64
+	lastFileID    atomic.Int64
+)
+
+// FixtureOption changes the sender or the chat of a fixture update.
+type FixtureOption func(*fixture)
+
+type fixture struct {
+	from tgbotapi.User
+	chat *tgbotapi.Chat
+}
+
+// WithFrom sets the user sending a fixture update. It is TestUser by
+// default.
+func WithFrom(user tgbotapi.User) FixtureOption {
+	return func(f *fixture) {
+		f.from = user
+	}
+}
+
+// WithChat sets the chat of a fixture update. It is the private chat with
+// the sender by default, or TestGroup for chat member updates.
+func WithChat(chat tgbotapi.Chat) FixtureOption {
+	return func(f *fixture) {
+		f.chat = &chat
+	}
+}
+
+func newFixture(options []FixtureOption) fixture {
+	f := fixture{from: TestUser}
+	for _, option := range options {
+		option(&f)
+	}
+	return f
+}
+
+func (f fixture) chatOr(chat tgbotapi.Chat) tgbotapi.Chat {
+	if f.chat != nil {
+		return *f.chat
+	}
+	return chat
+}
+
+// NewTestMessage returns an update with a text message. Commands, mentions,
+// hashtags and URLs in the text are marked with entities like Telegram does.
+func NewTestMessage(text string, options ...FixtureOption) tgbotapi.Update {
+	f := newFixture(options)
+
+	message := f.message()
+	message.Text = text
+	message.Entities = TextEntities(text)
+
+	return newTestUpdate(tgbotapi.Update{Message: message})
+}
+
+// NewTestCommand returns an update with a command message, such as
+// NewTestCommand("/start", "ref-1"). The leading slash may be omitted.
+func NewTestCommand(command, args string, options ...FixtureOption) tgbotapi.Update {
+	if !strings.HasPrefix(command, "/") {
+		command = "/" + command
+	}
+
+	text := command
+	if args != "" {
+		text += " " + args
+	}
+
+	return NewTestMessage(text, options...)
+}
+
+// NewTestPhotoMessage returns an update with a photo message. The photo has
+// the sizes Telegram generates for a 1280x960 image.
+func NewTestPhotoMessage(caption string, options ...FixtureOption) tgbotapi.Update {
+	f := newFixture(options)
+
+	message := f.message()
+	message.Caption = caption
+	message.CaptionEntities = TextEntities(caption)
+
+	id := strconv.FormatInt(lastFileID.Add(1), 10)
+	for i, size := range [][2]int{{90, 68}, {320, 240}, {800, 600}, {1280, 960}} {
+		message.Photo = append(message.Photo, tgbotapi.PhotoSize{
+			FileID:       "AgACAgIAAxkBAAI" + id + "-" + strconv.Itoa(i),
+			FileUniqueID: "AQAD" + id + "-" + strconv.Itoa(i),
+			Width:        size[0],
+			Height:       size[1],
+			FileSize:     size[0] * size[1] / 10,
+		})
+	}
+
+	return newTestUpdate(tgbotapi.Update{Message: message})
+}
+
+// NewTestCallback returns an update with a callback query of a button with
+// data pressed on a message.
+func NewTestCallback(data string, message tgbotapi.Message, options ...FixtureOption) tgbotapi.Update {
+	f := newFixture(options)
+
+	query := &tgbotapi.CallbackQuery{
+		ID:           strconv.FormatInt(4000000000000000000+lastQueryID.Add(1), 10),
+		From:         &f.from,
+		Message:      &message,
+		ChatInstance: strconv.FormatInt(message.Chat.ID, 10),
+		Data:         data,
+	}
+
+	return newTestUpdate(tgbotapi.Update{CallbackQuery: query})
+}
+
+// NewTestInlineQuery returns an update with an inline query. The chat type
+// is "sender" unless WithChat sets another chat.
+func NewTestInlineQuery(query string, options ...FixtureOption) tgbotapi.Update {
+	f := newFixture(options)
+
+	chatType := "sender"
+	if f.chat != nil && f.chat.ID != f.from.ID {
+		chatType = f.chat.Type
+	}
+
+	inlineQuery := &tgbotapi.InlineQuery{
+		ID:       strconv.FormatInt(5000000000000000000+lastQueryID.Add(1), 10),
+		From:     &f.from,
+		Query:    query,
+		ChatType: chatType,
+	}
+
+	return newTestUpdate(tgbotapi.Update{InlineQuery: inlineQuery})
+}
+
+// NewTestChatMemberUpdate returns an update with the status of the sender
+// in a chat changing, such as from "left" to "member" when joining.
+func NewTestChatMemberUpdate(oldStatus, newStatus string, options ...FixtureOption) tgbotapi.Update {
+	f := newFixture(options)
+
+	return newTestUpdate(tgbotapi.Update{ChatMember: f.chatMemberUpdated(f.from, oldStatus, newStatus)})
+}
+
+// NewTestMyChatMemberUpdate returns an update with the status of the bot in
+// a chat changed by the sender, such as from "left" to "administrator" when
+// the bot is added as an administrator.
+func NewTestMyChatMemberUpdate(bot tgbotapi.User, oldStatus, newStatus string, options ...FixtureOption) tgbotapi.Update {
+	f := newFixture(options)
+
+	return newTestUpdate(tgbotapi.Update{MyChatMember: f.chatMemberUpdated(bot, oldStatus, newStatus)})
+}
+
+func (f fixture) message() *tgbotapi.Message {
+	from := f.from
+	return &tgbotapi.Message{
+		MessageID: int(lastMessageID.Add(1)),
+		From:      &from,
+		Date:      time.Now().Unix(),
+		Chat:      f.chatOr(PrivateChat(f.from)),
+	}
+}
+
+func (f fixture) chatMemberUpdated(member tgbotapi.User, oldStatus, newStatus string) *tgbotapi.ChatMemberUpdated {
+	return &tgbotapi.ChatMemberUpdated{
+		Chat:          f.chatOr(TestGroup),
+		From:          f.from,
+		Date:          time.Now().Unix(),
+		OldChatMember: tgbotapi.ChatMember{User: &member, Status: oldStatus},
+		NewChatMember: tgbotapi.ChatMember{User: &member, Status: newStatus},
+	}
+}
+
+func newTestUpdate(update tgbotapi.Update) tgbotapi.Update {
+	update.UpdateID = int(lastUpdateID.Add(1))
+	return update
+}
+
+// TextEntities returns the entities Telegram detects in a text: bot
+// commands, mentions, hashtags and URLs. Offsets and lengths are in UTF-16
+// code units.
+func TextEntities(text string) []tgbotapi.MessageEntity {
+	var entities []tgbotapi.MessageEntity
+
+	offset := 0
+	for _, word := range strings.FieldsFunc(text, unicode.IsSpace) {
+		start := strings.Index(text, word)
+		offset += utf16Len(text[:start])
+		text = text[start+len(word):]
+
+		token := strings.TrimRight(word, ".,:;!?)\"'")
+		if entityType := entityTypeOf(token); entityType != "" {
+			entities = append(entities, tgbotapi.MessageEntity{
+				Type:   entityType,
+				Offset: offset,
+				Length: utf16Len(token),
+			})
+		}
+		offset += utf16Len(word)
+	}
+
+	return entities
+}
+
+func entityTypeOf(token string) string {
+	switch {
+	case strings.HasPrefix(token, "http://") || strings.HasPrefix(token, "https://"):
+		return "url"
+	case len(token) < 2:
+		return ""
+	}
+
+	name := token[1:]
+	switch token[0] {
+	case '/':
+		command, bot, _ := strings.Cut(name, "@")
+		if isWord(command) && (bot == "" || isWord(bot)) {
+			return "bot_command"
+		}
+	case '@':
+		if len(name) >= 4 && isWord(name) {
+			return "mention"
+		}
+	case '#':
+		if strings.IndexFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' }) < 0 {
+			return "hashtag"
+		}
+	}
+	return ""
+}
+
+func isWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r != '_' && (r > unicode.MaxASCII || !unicode.IsLetter(r) && !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
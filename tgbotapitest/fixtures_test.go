@@ -0,0 +1,77 @@
+package tgbotapitest
+
+import (
+	"testing"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+func TestTextEntities(t *testing.T) {
+	tests := []struct {
+		text string
+		want []tgbotapi.MessageEntity
+	}{
+		{"hello", nil},
+		{"/start", []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 6}}},
+		{"/start@test_bot ref", []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 15}}},
+		{"👋 @alice_bob, see #news!", []tgbotapi.MessageEntity{
+			{Type: "mention", Offset: 3, Length: 10},
+			{Type: "hashtag", Offset: 19, Length: 5},
+		}},
+		{"日本 https://example.com/a.", []tgbotapi.MessageEntity{{Type: "url", Offset: 3, Length: 21}}},
+		{"a / @ab", nil},
+	}
+
+	for _, test := range tests {
+		got := TextEntities(test.text)
+		if len(got) != len(test.want) {
+			t.Fatalf("%q: expected %+v, got %+v", test.text, test.want, got)
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Fatalf("%q: expected %+v, got %+v", test.text, test.want, got)
+			}
+		}
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	command := NewTestCommand("start", "ref-1")
+	message := command.Message
+	if !message.IsCommand() || message.Command() != "start" || message.CommandArguments() != "ref-1" {
+		t.Fatalf("unexpected command message %+v", message)
+	}
+	if message.From.ID != TestUser.ID || message.Chat.ID != TestUser.ID || message.Chat.Type != "private" {
+		t.Fatalf("expected a private message from TestUser, got %+v", message)
+	}
+
+	group := GroupChat(-5, "Group")
+	photo := NewTestPhotoMessage("#cats", WithChat(group))
+	if photo.UpdateID <= command.UpdateID || photo.Message.MessageID <= message.MessageID {
+		t.Fatal("expected IDs to increase")
+	}
+	if photo.Message.Chat.ID != group.ID || len(photo.Message.Photo) == 0 || photo.Message.CaptionEntities[0].Type != "hashtag" {
+		t.Fatalf("unexpected photo message %+v", photo.Message)
+	}
+
+	callback := NewTestCallback("a", *message)
+	if callback.CallbackQuery.Message.MessageID != message.MessageID || callback.CallbackQuery.From.ID != TestUser.ID {
+		t.Fatalf("unexpected callback query %+v", callback.CallbackQuery)
+	}
+	if callback.FromChat().ID != message.Chat.ID {
+		t.Fatal("expected the callback query to be in the chat of the message")
+	}
+
+	if query := NewTestInlineQuery("cats").InlineQuery; query.ChatType != "sender" || query.Query != "cats" {
+		t.Fatalf("unexpected inline query %+v", query)
+	}
+	if query := NewTestInlineQuery("cats", WithChat(group)).InlineQuery; query.ChatType != "group" {
+		t.Fatalf("unexpected inline query %+v", query)
+	}
+
+	bob := NewUser(7, "Bob")
+	member := NewTestChatMemberUpdate("left", "member", WithFrom(bob)).ChatMember
+	if member.Chat.ID != TestGroup.ID || member.NewChatMember.User.ID != bob.ID || member.OldChatMember.Status != "left" {
+		t.Fatalf("unexpected chat member update %+v", member)
+	}
+}
@@ -0,0 +1,443 @@
+package tgbotapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	_ "image/gif"  // register GIF for photo dimensions
+	_ "image/jpeg" // register JPEG for photo dimensions
+	_ "image/png"  // register PNG for photo dimensions
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// mediaMethods are the send methods of media, by the form field of the file.
+var mediaMethods = map[string]string{
+	"sendPhoto":     "photo",
+	"sendDocument":  "document",
+	"sendAudio":     "audio",
+	"sendVideo":     "video",
+	"sendVoice":     "voice",
+	"sendAnimation": "animation",
+	"sendSticker":   "sticker",
+}
+
+func (s *Server) registerMethods() {
+	s.handlers["getMe"] = s.getMe
+	s.handlers["getUpdates"] = s.getUpdates
+	s.handlers["deleteWebhook"] = s.deleteWebhook
+	s.handlers["sendMessage"] = s.sendMessage
+	for method, field := range mediaMethods {
+		s.handlers[method] = s.sendMedia(field)
+	}
+	s.handlers["editMessageText"] = s.editMessageText
+	s.handlers["editMessageCaption"] = s.editMessageCaption
+	s.handlers["editMessageReplyMarkup"] = s.editMessageReplyMarkup
+	s.handlers["deleteMessage"] = s.deleteMessage
+	s.handlers["deleteMessages"] = s.deleteMessages
+	s.handlers["answerCallbackQuery"] = s.answerCallbackQuery
+	s.handlers["sendChatAction"] = s.sendChatAction
+	s.handlers["getChat"] = s.getChat
+	s.handlers["getFile"] = s.getFile
+}
+
+func badRequest(description string) error {
+	return &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: " + description}
+}
+
+func (s *Server) getMe(Request) (any, error) {
+	return s.Bot, nil
+}
+
+func (s *Server) getUpdates(r Request) (any, error) {
+	offset, _ := strconv.Atoi(r.Params.Get("offset"))
+	limit, _ := strconv.Atoi(r.Params.Get("limit"))
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Like Telegram, an offset confirms all updates before it.
+	pending := s.updates[:0]
+	for _, update := range s.updates {
+		if update.UpdateID >= offset {
+			pending = append(pending, update)
+		}
+	}
+	s.updates = pending
+
+	updates := append([]tgbotapi.Update{}, pending[:min(limit, len(pending))]...)
+	return updates, nil
+}
+
+func (s *Server) deleteWebhook(Request) (any, error) {
+	return true, nil
+}
+
+func (s *Server) sendMessage(r Request) (any, error) {
+	message := tgbotapi.Message{Text: r.Params.Get("text")}
+	if err := decodeParam(r, "entities", &message.Entities); err != nil {
+		return nil, err
+	}
+	if message.Text == "" {
+		return nil, badRequest("message text is empty")
+	}
+
+	return s.send(r, message)
+}
+
+// sendMedia returns the handler of the send method of a media with the file
+// in field.
+func (s *Server) sendMedia(field string) HandlerFunc {
+	return func(r Request) (any, error) {
+		message := tgbotapi.Message{Caption: r.Params.Get("caption")}
+		if err := decodeParam(r, "caption_entities", &message.CaptionEntities); err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		file, err := s.requestFileLocked(r, field)
+		s.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		attachMedia(&message, field, file)
+
+		return s.send(r, message)
+	}
+}
+
+// send stores a message sent by the bot to the chat of a request.
+func (s *Server) send(r Request, message tgbotapi.Message) (any, error) {
+	var replyParameters *tgbotapi.ReplyParameters
+	if err := decodeParam(r, "reply_parameters", &replyParameters); err != nil {
+		return nil, err
+	}
+	markup, err := replyMarkup(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.requestChatLocked(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if replyParameters != nil && replyParameters.MessageID != 0 {
+		reply := s.messageLocked(state.chat.ID, replyParameters.MessageID)
+		if reply == nil && !replyParameters.AllowSendingWithoutReply {
+			return nil, badRequest("message to be replied not found")
+		}
+		if reply != nil {
+			copied := *reply
+			copied.ReplyToMessage = nil
+			message.ReplyToMessage = &copied
+		}
+	}
+
+	bot := s.Bot
+	message.From = &bot
+	message.ReplyMarkup = markup
+
+	sent := *s.addMessageLocked(state, message)
+	s.sent = append(s.sent, sent)
+
+	return sent, nil
+}
+
+func (s *Server) editMessageText(r Request) (any, error) {
+	text := r.Params.Get("text")
+	if text == "" {
+		return nil, badRequest("message text is empty")
+	}
+	var entities []tgbotapi.MessageEntity
+	if err := decodeParam(r, "entities", &entities); err != nil {
+		return nil, err
+	}
+
+	return s.edit(r, func(message *tgbotapi.Message) bool {
+		if message.Text == "" {
+			return false
+		}
+		changed := message.Text != text
+		message.Text, message.Entities = text, entities
+		return changed
+	})
+}
+
+func (s *Server) editMessageCaption(r Request) (any, error) {
+	caption := r.Params.Get("caption")
+	var entities []tgbotapi.MessageEntity
+	if err := decodeParam(r, "caption_entities", &entities); err != nil {
+		return nil, err
+	}
+
+	return s.edit(r, func(message *tgbotapi.Message) bool {
+		changed := message.Caption != caption
+		message.Caption, message.CaptionEntities = caption, entities
+		return changed
+	})
+}
+
+func (s *Server) editMessageReplyMarkup(r Request) (any, error) {
+	return s.edit(r, func(*tgbotapi.Message) bool { return false })
+}
+
+// edit applies an edit to the message of a request. update changes the
+// message and reports whether its content changed. The reply markup of the
+// request replaces the markup of the message.
+func (s *Server) edit(r Request, update func(message *tgbotapi.Message) bool) (any, error) {
+	if r.Params.Get("inline_message_id") != "" {
+		return nil, badRequest("inline messages are not supported by tgbotapitest")
+	}
+	markup, err := replyMarkup(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message, err := s.requestMessageLocked(r)
+	if err != nil {
+		return nil, err
+	}
+	if message.From == nil || message.From.ID != s.Bot.ID {
+		return nil, badRequest("message can't be edited")
+	}
+
+	edited := *message
+	changed := update(&edited)
+	if r.Method == "editMessageText" && !changed && edited.Text == "" {
+		return nil, badRequest("there is no text in the message to edit")
+	}
+	if !changed && markupJSON(markup) == markupJSON(message.ReplyMarkup) {
+		return nil, badRequest("message is not modified: specified new message content and reply markup are exactly the same as a current content and reply markup of the message")
+	}
+
+	edited.ReplyMarkup = markup
+	edited.EditDate = time.Now().Unix()
+	*message = edited
+	s.notifyLocked()
+
+	return edited, nil
+}
+
+func (s *Server) deleteMessage(r Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.requestChatLocked(r)
+	if err != nil {
+		return nil, err
+	}
+	messageID, _ := strconv.Atoi(r.Params.Get("message_id"))
+	if !s.deleteMessageLocked(state, messageID) {
+		return nil, badRequest("message to delete not found")
+	}
+
+	return true, nil
+}
+
+func (s *Server) deleteMessages(r Request) (any, error) {
+	var messageIDs []int
+	if err := decodeParam(r, "message_ids", &messageIDs); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.requestChatLocked(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, messageID := range messageIDs {
+		s.deleteMessageLocked(state, messageID)
+	}
+
+	return true, nil
+}
+
+func (s *Server) deleteMessageLocked(state *chatState, messageID int) bool {
+	for i, message := range state.messages {
+		if message.MessageID == messageID {
+			state.messages = append(state.messages[:i], state.messages[i+1:]...)
+			s.notifyLocked()
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) answerCallbackQuery(r Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.Params.Get("callback_query_id")
+	if !s.callbacks[id] {
+		return nil, badRequest("query is too old and response timeout expired or query ID is invalid")
+	}
+	delete(s.callbacks, id)
+
+	showAlert, _ := strconv.ParseBool(r.Params.Get("show_alert"))
+	s.callbackAnswers = append(s.callbackAnswers, CallbackAnswer{
+		CallbackQueryID: id,
+		Text:            r.Params.Get("text"),
+		ShowAlert:       showAlert,
+		URL:             r.Params.Get("url"),
+	})
+	s.notifyLocked()
+
+	return true, nil
+}
+
+func (s *Server) sendChatAction(r Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.requestChatLocked(r); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func (s *Server) getChat(r Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.requestChatLocked(r)
+	if err != nil {
+		return nil, err
+	}
+	return tgbotapi.ChatFullInfo{Chat: state.chat}, nil
+}
+
+func (s *Server) getFile(r Request) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, ok := s.files[r.Params.Get("file_id")]
+	if !ok {
+		return nil, badRequest("invalid file_id")
+	}
+	return file.file, nil
+}
+
+// requestChatLocked returns the chat of the chat_id of a request.
+func (s *Server) requestChatLocked(r Request) (*chatState, error) {
+	chatID, err := strconv.ParseInt(r.Params.Get("chat_id"), 10, 64)
+	if err != nil {
+		return nil, badRequest("chat not found")
+	}
+	state, ok := s.chats[chatID]
+	if !ok {
+		return nil, badRequest("chat not found")
+	}
+	return state, nil
+}
+
+// requestMessageLocked returns the message of the chat_id and message_id of
+// a request.
+func (s *Server) requestMessageLocked(r Request) (*tgbotapi.Message, error) {
+	state, err := s.requestChatLocked(r)
+	if err != nil {
+		return nil, err
+	}
+	messageID, _ := strconv.Atoi(r.Params.Get("message_id"))
+	message := s.messageLocked(state.chat.ID, messageID)
+	if message == nil {
+		return nil, badRequest("message to edit not found")
+	}
+	return message, nil
+}
+
+// requestFileLocked returns the file of a field of a request, either
+// uploaded or referenced by file ID or URL.
+func (s *Server) requestFileLocked(r Request, field string) (*storedFile, error) {
+	if upload, ok := r.Files[field]; ok {
+		return s.storeFileLocked(upload.Name, upload.Content, upload.ContentType), nil
+	}
+
+	value := r.Params.Get(field)
+	if file, ok := s.files[value]; ok {
+		return file, nil
+	}
+	if u, err := url.Parse(value); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return s.storeFileLocked(path.Base(u.Path), nil, ""), nil
+	}
+
+	return nil, badRequest("wrong file identifier/HTTP URL specified")
+}
+
+// attachMedia sets the media of a message sent with a file in field.
+func attachMedia(message *tgbotapi.Message, field string, file *storedFile) {
+	id, uniqueID := file.file.FileID, file.file.FileUniqueID
+	size := file.file.FileSize
+
+	switch field {
+	case "photo":
+		photo := tgbotapi.PhotoSize{FileID: id, FileUniqueID: uniqueID, FileSize: int(size)}
+		if config, _, err := image.DecodeConfig(bytes.NewReader(file.content)); err == nil {
+			photo.Width, photo.Height = config.Width, config.Height
+		}
+		message.Photo = []tgbotapi.PhotoSize{photo}
+	case "document":
+		message.Document = &tgbotapi.Document{FileID: id, FileUniqueID: uniqueID, FileName: file.name, MimeType: file.mimeType, FileSize: size}
+	case "audio":
+		message.Audio = &tgbotapi.Audio{FileID: id, FileUniqueID: uniqueID, FileName: file.name, MimeType: file.mimeType, FileSize: size}
+	case "video":
+		message.Video = &tgbotapi.Video{FileID: id, FileUniqueID: uniqueID, FileName: file.name, MimeType: file.mimeType, FileSize: size}
+	case "voice":
+		message.Voice = &tgbotapi.Voice{FileID: id, FileUniqueID: uniqueID, MimeType: file.mimeType, FileSize: size}
+	case "animation":
+		message.Animation = &tgbotapi.Animation{FileID: id, FileUniqueID: uniqueID, FileName: file.name, MimeType: file.mimeType, FileSize: size}
+	case "sticker":
+		message.Sticker = &tgbotapi.Sticker{FileID: id, FileUniqueID: uniqueID, Type: "regular", FileSize: int(size)}
+	}
+}
+
+// replyMarkup returns the inline keyboard of the reply_markup of a request.
+// Other keyboards are not attached to messages and are ignored.
+func replyMarkup(r Request) (*tgbotapi.InlineKeyboardMarkup, error) {
+	var markup tgbotapi.InlineKeyboardMarkup
+	if err := decodeParam(r, "reply_markup", &markup); err != nil {
+		return nil, err
+	}
+	if len(markup.InlineKeyboard) == 0 {
+		return nil, nil
+	}
+	return &markup, nil
+}
+
+func markupJSON(markup *tgbotapi.InlineKeyboardMarkup) string {
+	if markup == nil {
+		return ""
+	}
+	data, _ := json.Marshal(markup)
+	return string(data)
+}
+
+// decodeParam decodes a JSON encoded parameter of a request into v, if it
+// is set.
+func decodeParam(r Request, name string, v any) error {
+	value := r.Params.Get(name)
+	if value == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return badRequest("can't parse " + name + " JSON object")
+	}
+	return nil
+}
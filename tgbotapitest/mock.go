@@ -0,0 +1,139 @@
+package tgbotapitest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// MockCall is a request recorded by a MockBot.
+type MockCall struct {
+	// Name is the name of the called Bot method, such as "Send".
+	Name string
+	// Method is the Bot API method of the request, such as "sendMessage".
+	Method string
+	Params tgbotapi.Params
+	Files  []tgbotapi.RequestFile
+	// Chattable is the config the request was made with.
+	Chattable tgbotapi.Chattable
+}
+
+// MockBot is a tgbotapi.Bot recording every request instead of sending it.
+//
+// Requests succeed with the result set by SetResult for their method, or an
+// empty result decoded as the zero value. Respond can return any response
+// instead, including errors.
+type MockBot struct {
+	// Respond returns the response to a call. Returning a nil response and
+	// error falls back to the result set by SetResult.
+	Respond func(call MockCall) (*tgbotapi.APIResponse, error)
+
+	mu      sync.Mutex
+	calls   []MockCall
+	results map[string]any
+}
+
+// NewMockBot creates a new MockBot.
+func NewMockBot() *MockBot {
+	return &MockBot{results: make(map[string]any)}
+}
+
+// SetResult sets the result returned for requests of a Bot API method.
+func (m *MockBot) SetResult(method string, result any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.results[method] = result
+}
+
+// Calls returns the recorded calls in order.
+func (m *MockBot) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]MockCall(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls of a Bot API method in order.
+func (m *MockBot) CallsTo(method string) []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var calls []MockCall
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls.
+func (m *MockBot) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+// call records a request and returns its response.
+func (m *MockBot) call(ctx context.Context, name string, c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	request, err := tgbotapi.Encode(c)
+	if err != nil {
+		return nil, err
+	}
+	call := MockCall{Name: name, Method: request.Method, Params: request.Params, Files: request.Files, Chattable: c}
+
+	m.mu.Lock()
+	m.calls = append(m.calls, call)
+	result, hasResult := m.results[call.Method]
+	respond := m.Respond
+	m.mu.Unlock()
+
+	if respond != nil {
+		resp, err := respond(call)
+		if err != nil {
+			return resp, err
+		}
+		if resp != nil {
+			if !resp.Ok {
+				return resp, mockResponseError(resp)
+			}
+			return resp, nil
+		}
+	}
+
+	resp := &tgbotapi.APIResponse{Ok: true}
+	if hasResult {
+		if resp.Result, err = json.Marshal(result); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// do records a request and decodes its result into v.
+func (m *MockBot) do(ctx context.Context, name string, c tgbotapi.Chattable, v any) error {
+	resp, err := m.call(ctx, name, c)
+	if err != nil {
+		return err
+	}
+	if len(resp.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, v)
+}
+
+func mockResponseError(resp *tgbotapi.APIResponse) error {
+	err := &tgbotapi.Error{Code: resp.ErrorCode, Message: resp.Description}
+	if resp.Parameters != nil {
+		err.ResponseParameters = *resp.Parameters
+	}
+	return err
+}
@@ -0,0 +1,658 @@
+// Code generated by mockgen. DO NOT EDIT.
+
+package tgbotapitest
+
+import (
+	"context"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+var _ tgbotapi.Bot = (*MockBot)(nil)
+
+// GetMe records the request.
+func (m *MockBot) GetMe() (tgbotapi.User, error) {
+	var result tgbotapi.User
+	err := m.do(context.Background(), "GetMe", tgbotapi.NewRawRequest("getMe", nil), &result)
+	return result, err
+}
+
+// GetMeWithContext records the request.
+func (m *MockBot) GetMeWithContext(ctx context.Context) (tgbotapi.User, error) {
+	var result tgbotapi.User
+	err := m.do(ctx, "GetMeWithContext", tgbotapi.NewRawRequest("getMe", nil), &result)
+	return result, err
+}
+
+// Request records the request.
+func (m *MockBot) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return m.call(context.Background(), "Request", c)
+}
+
+// RequestWithContext records the request.
+func (m *MockBot) RequestWithContext(ctx context.Context, c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return m.call(ctx, "RequestWithContext", c)
+}
+
+// Send records the request.
+func (m *MockBot) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var result tgbotapi.Message
+	err := m.do(context.Background(), "Send", c, &result)
+	return result, err
+}
+
+// SendWithContext records the request.
+func (m *MockBot) SendWithContext(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var result tgbotapi.Message
+	err := m.do(ctx, "SendWithContext", c, &result)
+	return result, err
+}
+
+// SendLivePhoto records the request.
+func (m *MockBot) SendLivePhoto(config tgbotapi.SendLivePhotoConfig) (tgbotapi.Message, error) {
+	var result tgbotapi.Message
+	err := m.do(context.Background(), "SendLivePhoto", config, &result)
+	return result, err
+}
+
+// SendLivePhotoWithContext records the request.
+func (m *MockBot) SendLivePhotoWithContext(ctx context.Context, config tgbotapi.SendLivePhotoConfig) (tgbotapi.Message, error) {
+	var result tgbotapi.Message
+	err := m.do(ctx, "SendLivePhotoWithContext", config, &result)
+	return result, err
+}
+
+// SendRichMessage records the request.
+func (m *MockBot) SendRichMessage(config tgbotapi.SendRichMessageConfig) (tgbotapi.Message, error) {
+	var result tgbotapi.Message
+	err := m.do(context.Background(), "SendRichMessage", config, &result)
+	return result, err
+}
+
+// SendRichMessageWithContext records the request.
+func (m *MockBot) SendRichMessageWithContext(ctx context.Context, config tgbotapi.SendRichMessageConfig) (tgbotapi.Message, error) {
+	var result tgbotapi.Message
+	err := m.do(ctx, "SendRichMessageWithContext", config, &result)
+	return result, err
+}
+
+// SendRichMessageDraft records the request.
+func (m *MockBot) SendRichMessageDraft(config tgbotapi.SendRichMessageDraftConfig) (bool, error) {
+	var result bool
+	err := m.do(context.Background(), "SendRichMessageDraft", config, &result)
+	return result, err
+}
+
+// SendRichMessageDraftWithContext records the request.
+func (m *MockBot) SendRichMessageDraftWithContext(ctx context.Context, config tgbotapi.SendRichMessageDraftConfig) (bool, error) {
+	var result bool
+	err := m.do(ctx, "SendRichMessageDraftWithContext", config, &result)
+	return result, err
+}
+
+// SendMediaGroup records the request.
+func (m *MockBot) SendMediaGroup(config tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error) {
+	var result []tgbotapi.Message
+	err := m.do(context.Background(), "SendMediaGroup", config, &result)
+	return result, err
+}
+
+// SendMediaGroupWithContext records the request.
+func (m *MockBot) SendMediaGroupWithContext(ctx context.Context, config tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error) {
+	var result []tgbotapi.Message
+	err := m.do(ctx, "SendMediaGroupWithContext", config, &result)
+	return result, err
+}
+
+// CopyMessage records the request.
+func (m *MockBot) CopyMessage(config tgbotapi.CopyMessageConfig) (tgbotapi.MessageID, error) {
+	var result tgbotapi.MessageID
+	err := m.do(context.Background(), "CopyMessage", config, &result)
+	return result, err
+}
+
+// CopyMessageWithContext records the request.
+func (m *MockBot) CopyMessageWithContext(ctx context.Context, config tgbotapi.CopyMessageConfig) (tgbotapi.MessageID, error) {
+	var result tgbotapi.MessageID
+	err := m.do(ctx, "CopyMessageWithContext", config, &result)
+	return result, err
+}
+
+// StopPoll records the request.
+func (m *MockBot) StopPoll(config tgbotapi.StopPollConfig) (tgbotapi.Poll, error) {
+	var result tgbotapi.Poll
+	err := m.do(context.Background(), "StopPoll", config, &result)
+	return result, err
+}
+
+// StopPollWithContext records the request.
+func (m *MockBot) StopPollWithContext(ctx context.Context, config tgbotapi.StopPollConfig) (tgbotapi.Poll, error) {
+	var result tgbotapi.Poll
+	err := m.do(ctx, "StopPollWithContext", config, &result)
+	return result, err
+}
+
+// PostStory records the request.
+func (m *MockBot) PostStory(config tgbotapi.PostStoryConfig) (tgbotapi.Story, error) {
+	var result tgbotapi.Story
+	err := m.do(context.Background(), "PostStory", config, &result)
+	return result, err
+}
+
+// PostStoryWithContext records the request.
+func (m *MockBot) PostStoryWithContext(ctx context.Context, config tgbotapi.PostStoryConfig) (tgbotapi.Story, error) {
+	var result tgbotapi.Story
+	err := m.do(ctx, "PostStoryWithContext", config, &result)
+	return result, err
+}
+
+// EditStory records the request.
+func (m *MockBot) EditStory(config tgbotapi.EditStoryConfig) (tgbotapi.Story, error) {
+	var result tgbotapi.Story
+	err := m.do(context.Background(), "EditStory", config, &result)
+	return result, err
+}
+
+// EditStoryWithContext records the request.
+func (m *MockBot) EditStoryWithContext(ctx context.Context, config tgbotapi.EditStoryConfig) (tgbotapi.Story, error) {
+	var result tgbotapi.Story
+	err := m.do(ctx, "EditStoryWithContext", config, &result)
+	return result, err
+}
+
+// RepostStory records the request.
+func (m *MockBot) RepostStory(config tgbotapi.RepostStoryConfig) (tgbotapi.Story, error) {
+	var result tgbotapi.Story
+	err := m.do(context.Background(), "RepostStory", config, &result)
+	return result, err
+}
+
+// RepostStoryWithContext records the request.
+func (m *MockBot) RepostStoryWithContext(ctx context.Context, config tgbotapi.RepostStoryConfig) (tgbotapi.Story, error) {
+	var result tgbotapi.Story
+	err := m.do(ctx, "RepostStoryWithContext", config, &result)
+	return result, err
+}
+
+// GetUpdates records the request.
+func (m *MockBot) GetUpdates(config tgbotapi.UpdateConfig) ([]tgbotapi.Update, error) {
+	var result []tgbotapi.Update
+	err := m.do(context.Background(), "GetUpdates", config, &result)
+	return result, err
+}
+
+// GetUpdatesWithContext records the request.
+func (m *MockBot) GetUpdatesWithContext(ctx context.Context, config tgbotapi.UpdateConfig) ([]tgbotapi.Update, error) {
+	var result []tgbotapi.Update
+	err := m.do(ctx, "GetUpdatesWithContext", config, &result)
+	return result, err
+}
+
+// GetWebhookInfo records the request.
+func (m *MockBot) GetWebhookInfo() (tgbotapi.WebhookInfo, error) {
+	var result tgbotapi.WebhookInfo
+	err := m.do(context.Background(), "GetWebhookInfo", tgbotapi.NewRawRequest("getWebhookInfo", nil), &result)
+	return result, err
+}
+
+// GetWebhookInfoWithContext records the request.
+func (m *MockBot) GetWebhookInfoWithContext(ctx context.Context) (tgbotapi.WebhookInfo, error) {
+	var result tgbotapi.WebhookInfo
+	err := m.do(ctx, "GetWebhookInfoWithContext", tgbotapi.NewRawRequest("getWebhookInfo", nil), &result)
+	return result, err
+}
+
+// GetFile records the request.
+func (m *MockBot) GetFile(config tgbotapi.FileConfig) (tgbotapi.File, error) {
+	var result tgbotapi.File
+	err := m.do(context.Background(), "GetFile", config, &result)
+	return result, err
+}
+
+// GetFileWithContext records the request.
+func (m *MockBot) GetFileWithContext(ctx context.Context, config tgbotapi.FileConfig) (tgbotapi.File, error) {
+	var result tgbotapi.File
+	err := m.do(ctx, "GetFileWithContext", config, &result)
+	return result, err
+}
+
+// GetUserProfilePhotos records the request.
+func (m *MockBot) GetUserProfilePhotos(config tgbotapi.UserProfilePhotosConfig) (tgbotapi.UserProfilePhotos, error) {
+	var result tgbotapi.UserProfilePhotos
+	err := m.do(context.Background(), "GetUserProfilePhotos", config, &result)
+	return result, err
+}
+
+// GetUserProfilePhotosWithContext records the request.
+func (m *MockBot) GetUserProfilePhotosWithContext(ctx context.Context, config tgbotapi.UserProfilePhotosConfig) (tgbotapi.UserProfilePhotos, error) {
+	var result tgbotapi.UserProfilePhotos
+	err := m.do(ctx, "GetUserProfilePhotosWithContext", config, &result)
+	return result, err
+}
+
+// GetUserProfileAudios records the request.
+func (m *MockBot) GetUserProfileAudios(config tgbotapi.UserProfileAudiosConfig) (tgbotapi.UserProfileAudios, error) {
+	var result tgbotapi.UserProfileAudios
+	err := m.do(context.Background(), "GetUserProfileAudios", config, &result)
+	return result, err
+}
+
+// GetUserProfileAudiosWithContext records the request.
+func (m *MockBot) GetUserProfileAudiosWithContext(ctx context.Context, config tgbotapi.UserProfileAudiosConfig) (tgbotapi.UserProfileAudios, error) {
+	var result tgbotapi.UserProfileAudios
+	err := m.do(ctx, "GetUserProfileAudiosWithContext", config, &result)
+	return result, err
+}
+
+// GetUserPersonalChatMessages records the request.
+func (m *MockBot) GetUserPersonalChatMessages(config tgbotapi.UserPersonalChatMessagesConfig) ([]tgbotapi.Message, error) {
+	var result []tgbotapi.Message
+	err := m.do(context.Background(), "GetUserPersonalChatMessages", config, &result)
+	return result, err
+}
+
+// GetUserPersonalChatMessagesWithContext records the request.
+func (m *MockBot) GetUserPersonalChatMessagesWithContext(ctx context.Context, config tgbotapi.UserPersonalChatMessagesConfig) ([]tgbotapi.Message, error) {
+	var result []tgbotapi.Message
+	err := m.do(ctx, "GetUserPersonalChatMessagesWithContext", config, &result)
+	return result, err
+}
+
+// GetChat records the request.
+func (m *MockBot) GetChat(config tgbotapi.ChatInfoConfig) (tgbotapi.ChatFullInfo, error) {
+	var result tgbotapi.ChatFullInfo
+	err := m.do(context.Background(), "GetChat", config, &result)
+	return result, err
+}
+
+// GetChatWithContext records the request.
+func (m *MockBot) GetChatWithContext(ctx context.Context, config tgbotapi.ChatInfoConfig) (tgbotapi.ChatFullInfo, error) {
+	var result tgbotapi.ChatFullInfo
+	err := m.do(ctx, "GetChatWithContext", config, &result)
+	return result, err
+}
+
+// GetChatAdministrators records the request.
+func (m *MockBot) GetChatAdministrators(config tgbotapi.ChatAdministratorsConfig) ([]tgbotapi.ChatMember, error) {
+	var result []tgbotapi.ChatMember
+	err := m.do(context.Background(), "GetChatAdministrators", config, &result)
+	return result, err
+}
+
+// GetChatAdministratorsWithContext records the request.
+func (m *MockBot) GetChatAdministratorsWithContext(ctx context.Context, config tgbotapi.ChatAdministratorsConfig) ([]tgbotapi.ChatMember, error) {
+	var result []tgbotapi.ChatMember
+	err := m.do(ctx, "GetChatAdministratorsWithContext", config, &result)
+	return result, err
+}
+
+// GetChatMemberCount records the request.
+func (m *MockBot) GetChatMemberCount(config tgbotapi.ChatMemberCountConfig) (int, error) {
+	var result int
+	err := m.do(context.Background(), "GetChatMemberCount", config, &result)
+	return result, err
+}
+
+// GetChatMemberCountWithContext records the request.
+func (m *MockBot) GetChatMemberCountWithContext(ctx context.Context, config tgbotapi.ChatMemberCountConfig) (int, error) {
+	var result int
+	err := m.do(ctx, "GetChatMemberCountWithContext", config, &result)
+	return result, err
+}
+
+// GetChatMember records the request.
+func (m *MockBot) GetChatMember(config tgbotapi.GetChatMemberConfig) (tgbotapi.ChatMember, error) {
+	var result tgbotapi.ChatMember
+	err := m.do(context.Background(), "GetChatMember", config, &result)
+	return result, err
+}
+
+// GetChatMemberWithContext records the request.
+func (m *MockBot) GetChatMemberWithContext(ctx context.Context, config tgbotapi.GetChatMemberConfig) (tgbotapi.ChatMember, error) {
+	var result tgbotapi.ChatMember
+	err := m.do(ctx, "GetChatMemberWithContext", config, &result)
+	return result, err
+}
+
+// GetInviteLink records the request.
+func (m *MockBot) GetInviteLink(config tgbotapi.ChatInviteLinkConfig) (string, error) {
+	var result string
+	err := m.do(context.Background(), "GetInviteLink", config, &result)
+	return result, err
+}
+
+// GetInviteLinkWithContext records the request.
+func (m *MockBot) GetInviteLinkWithContext(ctx context.Context, config tgbotapi.ChatInviteLinkConfig) (string, error) {
+	var result string
+	err := m.do(ctx, "GetInviteLinkWithContext", config, &result)
+	return result, err
+}
+
+// CreateForumTopic records the request.
+func (m *MockBot) CreateForumTopic(config tgbotapi.CreateForumTopicConfig) (tgbotapi.ForumTopic, error) {
+	var result tgbotapi.ForumTopic
+	err := m.do(context.Background(), "CreateForumTopic", config, &result)
+	return result, err
+}
+
+// CreateForumTopicWithContext records the request.
+func (m *MockBot) CreateForumTopicWithContext(ctx context.Context, config tgbotapi.CreateForumTopicConfig) (tgbotapi.ForumTopic, error) {
+	var result tgbotapi.ForumTopic
+	err := m.do(ctx, "CreateForumTopicWithContext", config, &result)
+	return result, err
+}
+
+// DeleteMessageReaction records the request.
+func (m *MockBot) DeleteMessageReaction(config tgbotapi.DeleteMessageReactionConfig) (bool, error) {
+	var result bool
+	err := m.do(context.Background(), "DeleteMessageReaction", config, &result)
+	return result, err
+}
+
+// DeleteMessageReactionWithContext records the request.
+func (m *MockBot) DeleteMessageReactionWithContext(ctx context.Context, config tgbotapi.DeleteMessageReactionConfig) (bool, error) {
+	var result bool
+	err := m.do(ctx, "DeleteMessageReactionWithContext", config, &result)
+	return result, err
+}
+
+// DeleteAllMessageReactions records the request.
+func (m *MockBot) DeleteAllMessageReactions(config tgbotapi.DeleteAllMessageReactionsConfig) (bool, error) {
+	var result bool
+	err := m.do(context.Background(), "DeleteAllMessageReactions", config, &result)
+	return result, err
+}
+
+// DeleteAllMessageReactionsWithContext records the request.
+func (m *MockBot) DeleteAllMessageReactionsWithContext(ctx context.Context, config tgbotapi.DeleteAllMessageReactionsConfig) (bool, error) {
+	var result bool
+	err := m.do(ctx, "DeleteAllMessageReactionsWithContext", config, &result)
+	return result, err
+}
+
+// GetGameHighScores records the request.
+func (m *MockBot) GetGameHighScores(config tgbotapi.GetGameHighScoresConfig) ([]tgbotapi.GameHighScore, error) {
+	var result []tgbotapi.GameHighScore
+	err := m.do(context.Background(), "GetGameHighScores", config, &result)
+	return result, err
+}
+
+// GetGameHighScoresWithContext records the request.
+func (m *MockBot) GetGameHighScoresWithContext(ctx context.Context, config tgbotapi.GetGameHighScoresConfig) ([]tgbotapi.GameHighScore, error) {
+	var result []tgbotapi.GameHighScore
+	err := m.do(ctx, "GetGameHighScoresWithContext", config, &result)
+	return result, err
+}
+
+// GetStickerSet records the request.
+func (m *MockBot) GetStickerSet(config tgbotapi.GetStickerSetConfig) (tgbotapi.StickerSet, error) {
+	var result tgbotapi.StickerSet
+	err := m.do(context.Background(), "GetStickerSet", config, &result)
+	return result, err
+}
+
+// GetStickerSetWithContext records the request.
+func (m *MockBot) GetStickerSetWithContext(ctx context.Context, config tgbotapi.GetStickerSetConfig) (tgbotapi.StickerSet, error) {
+	var result tgbotapi.StickerSet
+	err := m.do(ctx, "GetStickerSetWithContext", config, &result)
+	return result, err
+}
+
+// GetCustomEmojiStickers records the request.
+func (m *MockBot) GetCustomEmojiStickers(config tgbotapi.GetCustomEmojiStickersConfig) ([]tgbotapi.Sticker, error) {
+	var result []tgbotapi.Sticker
+	err := m.do(context.Background(), "GetCustomEmojiStickers", config, &result)
+	return result, err
+}
+
+// GetCustomEmojiStickersWithContext records the request.
+func (m *MockBot) GetCustomEmojiStickersWithContext(ctx context.Context, config tgbotapi.GetCustomEmojiStickersConfig) ([]tgbotapi.Sticker, error) {
+	var result []tgbotapi.Sticker
+	err := m.do(ctx, "GetCustomEmojiStickersWithContext", config, &result)
+	return result, err
+}
+
+// GetMyCommands records the request.
+func (m *MockBot) GetMyCommands() ([]tgbotapi.BotCommand, error) {
+	var result []tgbotapi.BotCommand
+	err := m.do(context.Background(), "GetMyCommands", tgbotapi.GetMyCommandsConfig{}, &result)
+	return result, err
+}
+
+// GetMyCommandsWithConfig records the request.
+func (m *MockBot) GetMyCommandsWithConfig(config tgbotapi.GetMyCommandsConfig) ([]tgbotapi.BotCommand, error) {
+	var result []tgbotapi.BotCommand
+	err := m.do(context.Background(), "GetMyCommandsWithConfig", config, &result)
+	return result, err
+}
+
+// GetMyCommandsWithContext records the request.
+func (m *MockBot) GetMyCommandsWithContext(ctx context.Context, config tgbotapi.GetMyCommandsConfig) ([]tgbotapi.BotCommand, error) {
+	var result []tgbotapi.BotCommand
+	err := m.do(ctx, "GetMyCommandsWithContext", config, &result)
+	return result, err
+}
+
+// GetMyDefaultAdministratorRights records the request.
+func (m *MockBot) GetMyDefaultAdministratorRights(config tgbotapi.GetMyDefaultAdministratorRightsConfig) (tgbotapi.ChatAdministratorRights, error) {
+	var result tgbotapi.ChatAdministratorRights
+	err := m.do(context.Background(), "GetMyDefaultAdministratorRights", config, &result)
+	return result, err
+}
+
+// GetMyDefaultAdministratorRightsWithContext records the request.
+func (m *MockBot) GetMyDefaultAdministratorRightsWithContext(ctx context.Context, config tgbotapi.GetMyDefaultAdministratorRightsConfig) (tgbotapi.ChatAdministratorRights, error) {
+	var result tgbotapi.ChatAdministratorRights
+	err := m.do(ctx, "GetMyDefaultAdministratorRightsWithContext", config, &result)
+	return result, err
+}
+
+// GetManagedBotToken records the request.
+func (m *MockBot) GetManagedBotToken(config tgbotapi.GetManagedBotTokenConfig) (string, error) {
+	var result string
+	err := m.do(context.Background(), "GetManagedBotToken", config, &result)
+	return result, err
+}
+
+// GetManagedBotTokenWithContext records the request.
+func (m *MockBot) GetManagedBotTokenWithContext(ctx context.Context, config tgbotapi.GetManagedBotTokenConfig) (string, error) {
+	var result string
+	err := m.do(ctx, "GetManagedBotTokenWithContext", config, &result)
+	return result, err
+}
+
+// ReplaceManagedBotToken records the request.
+func (m *MockBot) ReplaceManagedBotToken(config tgbotapi.ReplaceManagedBotTokenConfig) (string, error) {
+	var result string
+	err := m.do(context.Background(), "ReplaceManagedBotToken", config, &result)
+	return result, err
+}
+
+// ReplaceManagedBotTokenWithContext records the request.
+func (m *MockBot) ReplaceManagedBotTokenWithContext(ctx context.Context, config tgbotapi.ReplaceManagedBotTokenConfig) (string, error) {
+	var result string
+	err := m.do(ctx, "ReplaceManagedBotTokenWithContext", config, &result)
+	return result, err
+}
+
+// GetManagedBotAccessSettings records the request.
+func (m *MockBot) GetManagedBotAccessSettings(config tgbotapi.GetManagedBotAccessSettingsConfig) (tgbotapi.BotAccessSettings, error) {
+	var result tgbotapi.BotAccessSettings
+	err := m.do(context.Background(), "GetManagedBotAccessSettings", config, &result)
+	return result, err
+}
+
+// GetManagedBotAccessSettingsWithContext records the request.
+func (m *MockBot) GetManagedBotAccessSettingsWithContext(ctx context.Context, config tgbotapi.GetManagedBotAccessSettingsConfig) (tgbotapi.BotAccessSettings, error) {
+	var result tgbotapi.BotAccessSettings
+	err := m.do(ctx, "GetManagedBotAccessSettingsWithContext", config, &result)
+	return result, err
+}
+
+// SetManagedBotAccessSettings records the request.
+func (m *MockBot) SetManagedBotAccessSettings(config tgbotapi.SetManagedBotAccessSettingsConfig) (bool, error) {
+	var result bool
+	err := m.do(context.Background(), "SetManagedBotAccessSettings", config, &result)
+	return result, err
+}
+
+// SetManagedBotAccessSettingsWithContext records the request.
+func (m *MockBot) SetManagedBotAccessSettingsWithContext(ctx context.Context, config tgbotapi.SetManagedBotAccessSettingsConfig) (bool, error) {
+	var result bool
+	err := m.do(ctx, "SetManagedBotAccessSettingsWithContext", config, &result)
+	return result, err
+}
+
+// GetMyStarBalance records the request.
+func (m *MockBot) GetMyStarBalance(config tgbotapi.GetMyStarBalanceConfig) (tgbotapi.StarAmount, error) {
+	var result tgbotapi.StarAmount
+	err := m.do(context.Background(), "GetMyStarBalance", config, &result)
+	return result, err
+}
+
+// GetMyStarBalanceWithContext records the request.
+func (m *MockBot) GetMyStarBalanceWithContext(ctx context.Context, config tgbotapi.GetMyStarBalanceConfig) (tgbotapi.StarAmount, error) {
+	var result tgbotapi.StarAmount
+	err := m.do(ctx, "GetMyStarBalanceWithContext", config, &result)
+	return result, err
+}
+
+// GetBusinessAccountStarBalance records the request.
+func (m *MockBot) GetBusinessAccountStarBalance(config tgbotapi.GetBusinessAccountStarBalanceConfig) (tgbotapi.StarAmount, error) {
+	var result tgbotapi.StarAmount
+	err := m.do(context.Background(), "GetBusinessAccountStarBalance", config, &result)
+	return result, err
+}
+
+// GetBusinessAccountStarBalanceWithContext records the request.
+func (m *MockBot) GetBusinessAccountStarBalanceWithContext(ctx context.Context, config tgbotapi.GetBusinessAccountStarBalanceConfig) (tgbotapi.StarAmount, error) {
+	var result tgbotapi.StarAmount
+	err := m.do(ctx, "GetBusinessAccountStarBalanceWithContext", config, &result)
+	return result, err
+}
+
+// GetBusinessAccountGifts records the request.
+func (m *MockBot) GetBusinessAccountGifts(config tgbotapi.GetBusinessAccountGiftsConfig) (tgbotapi.OwnedGifts, error) {
+	var result tgbotapi.OwnedGifts
+	err := m.do(context.Background(), "GetBusinessAccountGifts", config, &result)
+	return result, err
+}
+
+// GetBusinessAccountGiftsWithContext records the request.
+func (m *MockBot) GetBusinessAccountGiftsWithContext(ctx context.Context, config tgbotapi.GetBusinessAccountGiftsConfig) (tgbotapi.OwnedGifts, error) {
+	var result tgbotapi.OwnedGifts
+	err := m.do(ctx, "GetBusinessAccountGiftsWithContext", config, &result)
+	return result, err
+}
+
+// GetUserGifts records the request.
+func (m *MockBot) GetUserGifts(config tgbotapi.GetUserGiftsConfig) (tgbotapi.OwnedGifts, error) {
+	var result tgbotapi.OwnedGifts
+	err := m.do(context.Background(), "GetUserGifts", config, &result)
+	return result, err
+}
+
+// GetUserGiftsWithContext records the request.
+func (m *MockBot) GetUserGiftsWithContext(ctx context.Context, config tgbotapi.GetUserGiftsConfig) (tgbotapi.OwnedGifts, error) {
+	var result tgbotapi.OwnedGifts
+	err := m.do(ctx, "GetUserGiftsWithContext", config, &result)
+	return result, err
+}
+
+// GetChatGifts records the request.
+func (m *MockBot) GetChatGifts(config tgbotapi.GetChatGiftsConfig) (tgbotapi.OwnedGifts, error) {
+	var result tgbotapi.OwnedGifts
+	err := m.do(context.Background(), "GetChatGifts", config, &result)
+	return result, err
+}
+
+// GetChatGiftsWithContext records the request.
+func (m *MockBot) GetChatGiftsWithContext(ctx context.Context, config tgbotapi.GetChatGiftsConfig) (tgbotapi.OwnedGifts, error) {
+	var result tgbotapi.OwnedGifts
+	err := m.do(ctx, "GetChatGiftsWithContext", config, &result)
+	return result, err
+}
+
+// CreateInvoiceLink records the request.
+func (m *MockBot) CreateInvoiceLink(config tgbotapi.InvoiceLinkConfig) (string, error) {
+	var result string
+	err := m.do(context.Background(), "CreateInvoiceLink", config, &result)
+	return result, err
+}
+
+// CreateInvoiceLinkWithContext records the request.
+func (m *MockBot) CreateInvoiceLinkWithContext(ctx context.Context, config tgbotapi.InvoiceLinkConfig) (string, error) {
+	var result string
+	err := m.do(ctx, "CreateInvoiceLinkWithContext", config, &result)
+	return result, err
+}
+
+// AnswerWebAppQuery records the request.
+func (m *MockBot) AnswerWebAppQuery(config tgbotapi.AnswerWebAppQueryConfig) (tgbotapi.SentWebAppMessage, error) {
+	var result tgbotapi.SentWebAppMessage
+	err := m.do(context.Background(), "AnswerWebAppQuery", config, &result)
+	return result, err
+}
+
+// AnswerWebAppQueryWithContext records the request.
+func (m *MockBot) AnswerWebAppQueryWithContext(ctx context.Context, config tgbotapi.AnswerWebAppQueryConfig) (tgbotapi.SentWebAppMessage, error) {
+	var result tgbotapi.SentWebAppMessage
+	err := m.do(ctx, "AnswerWebAppQueryWithContext", config, &result)
+	return result, err
+}
+
+// AnswerGuestQuery records the request.
+func (m *MockBot) AnswerGuestQuery(config tgbotapi.AnswerGuestQueryConfig) (tgbotapi.SentGuestMessage, error) {
+	var result tgbotapi.SentGuestMessage
+	err := m.do(context.Background(), "AnswerGuestQuery", config, &result)
+	return result, err
+}
+
+// AnswerGuestQueryWithContext records the request.
+func (m *MockBot) AnswerGuestQueryWithContext(ctx context.Context, config tgbotapi.AnswerGuestQueryConfig) (tgbotapi.SentGuestMessage, error) {
+	var result tgbotapi.SentGuestMessage
+	err := m.do(ctx, "AnswerGuestQueryWithContext", config, &result)
+	return result, err
+}
+
+// AnswerChatJoinRequestQuery records the request.
+func (m *MockBot) AnswerChatJoinRequestQuery(config tgbotapi.AnswerChatJoinRequestQueryConfig) (bool, error) {
+	var result bool
+	err := m.do(context.Background(), "AnswerChatJoinRequestQuery", config, &result)
+	return result, err
+}
+
+// AnswerChatJoinRequestQueryWithContext records the request.
+func (m *MockBot) AnswerChatJoinRequestQueryWithContext(ctx context.Context, config tgbotapi.AnswerChatJoinRequestQueryConfig) (bool, error) {
+	var result bool
+	err := m.do(ctx, "AnswerChatJoinRequestQueryWithContext", config, &result)
+	return result, err
+}
+
+// SendChatJoinRequestWebApp records the request.
+func (m *MockBot) SendChatJoinRequestWebApp(config tgbotapi.SendChatJoinRequestWebAppConfig) (bool, error) {
+	var result bool
+	err := m.do(context.Background(), "SendChatJoinRequestWebApp", config, &result)
+	return result, err
+}
+
+// SendChatJoinRequestWebAppWithContext records the request.
+func (m *MockBot) SendChatJoinRequestWebAppWithContext(ctx context.Context, config tgbotapi.SendChatJoinRequestWebAppConfig) (bool, error) {
+	var result bool
+	err := m.do(ctx, "SendChatJoinRequestWebAppWithContext", config, &result)
+	return result, err
+}
+
+// SavePreparedKeyboardButton records the request.
+func (m *MockBot) SavePreparedKeyboardButton(config tgbotapi.SavePreparedKeyboardButtonConfig) (tgbotapi.PreparedKeyboardButton, error) {
+	var result tgbotapi.PreparedKeyboardButton
+	err := m.do(context.Background(), "SavePreparedKeyboardButton", config, &result)
+	return result, err
+}
+
+// SavePreparedKeyboardButtonWithContext records the request.
+func (m *MockBot) SavePreparedKeyboardButtonWithContext(ctx context.Context, config tgbotapi.SavePreparedKeyboardButtonConfig) (tgbotapi.PreparedKeyboardButton, error) {
+	var result tgbotapi.PreparedKeyboardButton
+	err := m.do(ctx, "SavePreparedKeyboardButtonWithContext", config, &result)
+	return result, err
+}
@@ -0,0 +1,83 @@
+package tgbotapitest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// greet is a handler written against the Bot interface.
+func greet(bot tgbotapi.Bot, chatID int64) error {
+	chat, err := bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: chatID}})
+	if err != nil {
+		return err
+	}
+	_, err = bot.Send(tgbotapi.NewMessage(chatID, "Hello, "+chat.FirstName))
+	return err
+}
+
+func TestMockBotRecordsCalls(t *testing.T) {
+	bot := NewMockBot()
+	bot.SetResult("getChat", tgbotapi.ChatFullInfo{Chat: tgbotapi.Chat{ID: 7, FirstName: "Bob"}})
+
+	if err := greet(bot, 7); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := bot.Calls()
+	if len(calls) != 2 || calls[0].Name != "GetChat" || calls[1].Method != "sendMessage" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+	sent := bot.CallsTo("sendMessage")
+	if len(sent) != 1 || sent[0].Params["text"] != "Hello, Bob" || sent[0].Params["chat_id"] != "7" {
+		t.Fatalf("unexpected sendMessage params %v", sent[0].Params)
+	}
+
+	photo := tgbotapi.NewPhoto(7, tgbotapi.FileBytes{Name: "a.jpg", Bytes: []byte("a")})
+	if _, err := bot.Send(photo); err != nil {
+		t.Fatal(err)
+	}
+	if files := bot.CallsTo("sendPhoto")[0].Files; len(files) != 1 || files[0].Name != "photo" {
+		t.Fatalf("unexpected files %+v", files)
+	}
+
+	bot.Reset()
+	if len(bot.Calls()) != 0 {
+		t.Fatal("expected no calls after Reset")
+	}
+}
+
+func TestMockBotRespond(t *testing.T) {
+	bot := NewMockBot()
+	bot.Respond = func(call MockCall) (*tgbotapi.APIResponse, error) {
+		if call.Method == "sendMessage" {
+			return &tgbotapi.APIResponse{Ok: false, ErrorCode: 403, Description: "Forbidden: bot was blocked by the user"}, nil
+		}
+		return nil, nil
+	}
+
+	_, err := bot.Send(tgbotapi.NewMessage(7, "hi"))
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != 403 {
+		t.Fatalf("expected a forbidden error, got %v", err)
+	}
+
+	if ok, err := bot.DeleteMessageReaction(tgbotapi.DeleteMessageReactionConfig{}); err != nil || ok {
+		t.Fatalf("expected a zero result, got %v, %v", ok, err)
+	}
+}
+
+func TestCallMockBot(t *testing.T) {
+	bot := NewMockBot()
+	bot.SetResult("getChatMemberCount", 3)
+
+	count, err := tgbotapi.Call(context.Background(), bot, tgbotapi.ChatMemberCountConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: 1}})
+	if err != nil || count != 3 {
+		t.Fatalf("expected 3 members, got %d, %v", count, err)
+	}
+	if calls := bot.CallsTo("getChatMemberCount"); len(calls) != 1 || calls[0].Name != "RequestWithContext" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+}
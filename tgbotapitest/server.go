@@ -0,0 +1,557 @@
+// Package tgbotapitest provides an in-process fake Telegram Bot API server
+// for testing bots end to end.
+//
+// The server keeps chats, messages, files and pending updates in memory.
+// Tests inject updates as users would cause them, let the bot under test
+// handle them with a regular BotAPI, and assert on the messages it sent:
+//
+//	server := tgbotapitest.NewServer()
+//	defer server.Close()
+//
+//	bot, err := server.NewBot()
+//	...
+//	user := tgbotapitest.NewUser(42, "Alice")
+//	server.SendText(user, tgbotapitest.PrivateChat(user), "/start")
+//	...
+//	sent, err := server.WaitSent(ctx, 1)
+package tgbotapitest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// DefaultToken is the token of the bot served by a Server.
+const DefaultToken = "1000:TEST-TOKEN"
+
+// HandlerFunc handles a Bot API method. It returns the result of the method,
+// or an error which is returned to the bot. An *tgbotapi.Error sets the error
+// code and description of the response.
+type HandlerFunc func(request Request) (any, error)
+
+// Request is a request made by the bot to a Server.
+type Request struct {
+	Method string
+	Params url.Values
+	// Files are the files uploaded with the request, by form field.
+	Files map[string]UploadedFile
+}
+
+// UploadedFile is a file uploaded by the bot.
+type UploadedFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// CallbackAnswer is an answer of the bot to a callback query.
+type CallbackAnswer struct {
+	CallbackQueryID string
+	Text            string
+	ShowAlert       bool
+	URL             string
+}
+
+// Server is a fake Telegram Bot API server.
+type Server struct {
+	*httptest.Server
+
+	// Token is the token of the bot. Requests with another token are
+	// rejected as unauthorized.
+	Token string
+	// Bot is the user of the bot returned by getMe.
+	Bot tgbotapi.User
+
+	mu              sync.Mutex
+	changed         chan struct{}
+	handlers        map[string]HandlerFunc
+	chats           map[int64]*chatState
+	files           map[string]*storedFile
+	updates         []tgbotapi.Update
+	nextUpdateID    int
+	nextFileID      int
+	nextCallbackID  int
+	callbacks       map[string]bool
+	sent            []tgbotapi.Message
+	callbackAnswers []CallbackAnswer
+	requests        []Request
+}
+
+type chatState struct {
+	chat          tgbotapi.Chat
+	messages      []*tgbotapi.Message
+	nextMessageID int
+}
+
+type storedFile struct {
+	file     tgbotapi.File
+	name     string
+	content  []byte
+	mimeType string
+}
+
+// NewServer starts a new Server. It must be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		Token:        DefaultToken,
+		Bot:          TestBot,
+		changed:      make(chan struct{}),
+		handlers:     make(map[string]HandlerFunc),
+		chats:        make(map[int64]*chatState),
+		files:        make(map[string]*storedFile),
+		callbacks:    make(map[string]bool),
+		nextUpdateID: 1,
+	}
+	s.registerMethods()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// APIEndpoint returns the API endpoint of the server for WithAPIEndpoint.
+func (s *Server) APIEndpoint() string {
+	return s.URL + "/bot%s/%s"
+}
+
+// FileEndpoint returns the file endpoint of the server for WithFileEndpoint.
+func (s *Server) FileEndpoint() string {
+	return s.URL + "/file/bot%s/%s"
+}
+
+// Options returns the options for a BotAPI to use the server.
+func (s *Server) Options() []tgbotapi.BotAPIOption {
+	return []tgbotapi.BotAPIOption{
+		tgbotapi.WithAPIEndpoint(s.APIEndpoint()),
+		tgbotapi.WithFileEndpoint(s.FileEndpoint()),
+		tgbotapi.WithHTTPClient(s.Client()),
+	}
+}
+
+// NewBot creates a BotAPI using the server.
+func (s *Server) NewBot(options ...tgbotapi.BotAPIOption) (*tgbotapi.BotAPI, error) {
+	return tgbotapi.NewBotAPIWithOptions(s.Token, append(s.Options(), options...)...)
+}
+
+// Handle sets the handler of a method, replacing the built-in one if any.
+func (s *Server) Handle(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[method] = handler
+}
+
+// NewUser returns a user with an ID and a first name.
+func NewUser(id int64, firstName string) tgbotapi.User {
+	return tgbotapi.User{ID: id, FirstName: firstName}
+}
+
+// PrivateChat returns the private chat of the bot with a user.
+func PrivateChat(user tgbotapi.User) tgbotapi.Chat {
+	return tgbotapi.Chat{
+		ID:        user.ID,
+		Type:      "private",
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		UserName:  user.UserName,
+	}
+}
+
+// GroupChat returns a group chat with an ID and a title.
+func GroupChat(id int64, title string) tgbotapi.Chat {
+	return tgbotapi.Chat{ID: id, Type: "group", Title: title}
+}
+
+// AddChat makes a chat known to the server, so the bot can send messages
+// to it. Chats of injected messages are added automatically.
+func (s *Server) AddChat(chat tgbotapi.Chat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chatLocked(chat)
+}
+
+// AddUpdate queues an update for getUpdates and returns it with its ID set.
+func (s *Server) AddUpdate(update tgbotapi.Update) tgbotapi.Update {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addUpdateLocked(update)
+}
+
+// SendText injects a text message from a user in a chat. Entities are set
+// as returned by TextEntities.
+func (s *Server) SendText(from tgbotapi.User, chat tgbotapi.Chat, text string) tgbotapi.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message := tgbotapi.Message{
+		From:     &from,
+		Text:     text,
+		Entities: TextEntities(text),
+	}
+
+	stored := *s.addMessageLocked(s.chatLocked(chat), message)
+	s.addUpdateLocked(tgbotapi.Update{Message: &stored})
+
+	return stored
+}
+
+// PressButton injects a callback query from a user pressing an inline
+// keyboard button with data on a message.
+func (s *Server) PressButton(from tgbotapi.User, message tgbotapi.Message, data string) tgbotapi.CallbackQuery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextCallbackID++
+	query := tgbotapi.CallbackQuery{
+		ID:           strconv.Itoa(s.nextCallbackID),
+		From:         &from,
+		Message:      &message,
+		ChatInstance: strconv.FormatInt(message.Chat.ID, 10),
+		Data:         data,
+	}
+	if current := s.messageLocked(message.Chat.ID, message.MessageID); current != nil {
+		copied := *current
+		query.Message = &copied
+	}
+	s.callbacks[query.ID] = true
+	s.addUpdateLocked(tgbotapi.Update{CallbackQuery: &query})
+
+	return query
+}
+
+// Sent returns the messages sent by the bot, in order, as they were sent.
+func (s *Server) Sent() []tgbotapi.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]tgbotapi.Message(nil), s.sent...)
+}
+
+// WaitSent waits until the bot sent at least n messages and returns all of
+// them.
+func (s *Server) WaitSent(ctx context.Context, n int) ([]tgbotapi.Message, error) {
+	for {
+		s.mu.Lock()
+		sent := append([]tgbotapi.Message(nil), s.sent...)
+		changed := s.changed
+		s.mu.Unlock()
+
+		if len(sent) >= n {
+			return sent, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return sent, fmt.Errorf("waiting for %d sent messages, got %d: %w", n, len(sent), ctx.Err())
+		}
+	}
+}
+
+// Messages returns the current messages of a chat, including edits and
+// without deleted messages.
+func (s *Server) Messages(chatID int64) []tgbotapi.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.chats[chatID]
+	if !ok {
+		return nil
+	}
+
+	messages := make([]tgbotapi.Message, 0, len(state.messages))
+	for _, message := range state.messages {
+		messages = append(messages, *message)
+	}
+	return messages
+}
+
+// CallbackAnswers returns the answers of the bot to callback queries.
+func (s *Server) CallbackAnswers() []CallbackAnswer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]CallbackAnswer(nil), s.callbackAnswers...)
+}
+
+// Requests returns all requests made by the bot.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// AddFile stores a file on the server, so the bot can download it with
+// getFile, and returns its file ID.
+func (s *Server) AddFile(name string, content []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storeFileLocked(name, content, "").file.FileID
+}
+
+// FileContent returns the content of a file uploaded by the bot.
+func (s *Server) FileContent(fileID string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, ok := s.files[fileID]
+	if !ok {
+		return nil, false
+	}
+	return file.content, true
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if token, path, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/file/bot"), "/"); ok && strings.HasPrefix(r.URL.Path, "/file/bot") {
+		s.serveFile(w, token, path)
+		return
+	}
+
+	token, method, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	if !ok || !strings.HasPrefix(r.URL.Path, "/bot") {
+		http.NotFound(w, r)
+		return
+	}
+	if token != s.Token {
+		writeError(w, &tgbotapi.Error{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+		return
+	}
+
+	if err := r.ParseMultipartForm(64 << 20); err != nil && err != http.ErrNotMultipart {
+		writeError(w, &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: " + err.Error()})
+		return
+	}
+	files, err := formFiles(r)
+	if err != nil {
+		writeError(w, &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: " + err.Error()})
+		return
+	}
+	request := Request{Method: method, Params: r.Form, Files: files}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	handler, ok := s.handlers[method]
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, &tgbotapi.Error{Code: http.StatusNotFound, Message: "Not Found: method not found"})
+		return
+	}
+
+	if method == "getUpdates" {
+		s.waitForUpdates(r.Context(), request.Params)
+	}
+
+	result, err := handler(request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: true, Result: data})
+}
+
+func (s *Server) serveFile(w http.ResponseWriter, token, path string) {
+	if token != s.Token {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mu.Lock()
+	var content []byte
+	found := false
+	for _, file := range s.files {
+		if file.file.FilePath == path {
+			content, found = file.content, true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		http.NotFound(w, nil)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	_, _ = w.Write(content)
+}
+
+// waitForUpdates blocks a getUpdates request with a timeout until updates
+// are available.
+func (s *Server) waitForUpdates(ctx context.Context, params url.Values) {
+	timeout, _ := strconv.Atoi(params.Get("timeout"))
+	if timeout <= 0 {
+		return
+	}
+	offset, _ := strconv.Atoi(params.Get("offset"))
+
+	timer := time.NewTimer(time.Duration(timeout) * time.Second)
+	defer timer.Stop()
+
+	for {
+		s.mu.Lock()
+		pending := false
+		for _, update := range s.updates {
+			if update.UpdateID >= offset {
+				pending = true
+				break
+			}
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		if pending {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// notifyLocked wakes up everyone waiting for a change.
+func (s *Server) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *Server) addUpdateLocked(update tgbotapi.Update) tgbotapi.Update {
+	update.UpdateID = s.nextUpdateID
+	s.nextUpdateID++
+	s.updates = append(s.updates, update)
+	s.notifyLocked()
+
+	return update
+}
+
+func (s *Server) chatLocked(chat tgbotapi.Chat) *chatState {
+	state, ok := s.chats[chat.ID]
+	if !ok {
+		state = &chatState{chat: chat, nextMessageID: 1}
+		s.chats[chat.ID] = state
+	}
+	return state
+}
+
+func (s *Server) addMessageLocked(state *chatState, message tgbotapi.Message) *tgbotapi.Message {
+	message.MessageID = state.nextMessageID
+	state.nextMessageID++
+	message.Chat = state.chat
+	if message.Date == 0 {
+		message.Date = time.Now().Unix()
+	}
+
+	stored := &message
+	state.messages = append(state.messages, stored)
+	s.notifyLocked()
+
+	return stored
+}
+
+func (s *Server) messageLocked(chatID int64, messageID int) *tgbotapi.Message {
+	state, ok := s.chats[chatID]
+	if !ok {
+		return nil
+	}
+	for _, message := range state.messages {
+		if message.MessageID == messageID {
+			return message
+		}
+	}
+	return nil
+}
+
+func (s *Server) storeFileLocked(name string, content []byte, mimeType string) *storedFile {
+	s.nextFileID++
+	id := strconv.Itoa(s.nextFileID)
+
+	file := &storedFile{
+		file: tgbotapi.File{
+			FileID:       "file-" + id,
+			FileUniqueID: "unique-" + id,
+			FileSize:     int64(len(content)),
+			FilePath:     "files/" + id + "-" + url.PathEscape(name),
+		},
+		name:     name,
+		content:  content,
+		mimeType: mimeType,
+	}
+	s.files[file.file.FileID] = file
+
+	return file
+}
+
+func formFiles(r *http.Request) (map[string]UploadedFile, error) {
+	files := make(map[string]UploadedFile)
+	if r.MultipartForm == nil {
+		return files, nil
+	}
+
+	for field, headers := range r.MultipartForm.File {
+		if len(headers) == 0 {
+			continue
+		}
+		f, err := headers[0].Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[field] = UploadedFile{
+			Name:        headers[0].Filename,
+			ContentType: headers[0].Header.Get("Content-Type"),
+			Content:     content,
+		}
+	}
+
+	return files, nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*tgbotapi.Error)
+	if !ok {
+		apiErr = &tgbotapi.Error{Code: http.StatusBadRequest, Message: "Bad Request: " + err.Error()}
+	}
+
+	var parameters *tgbotapi.ResponseParameters
+	if apiErr.ResponseParameters != (tgbotapi.ResponseParameters{}) {
+		parameters = &apiErr.ResponseParameters
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(apiErr.Code)
+	_ = json.NewEncoder(w).Encode(tgbotapi.APIResponse{
+		Ok:          false,
+		ErrorCode:   apiErr.Code,
+		Description: apiErr.Message,
+		Parameters:  parameters,
+	})
+}
@@ -0,0 +1,177 @@
+package tgbotapitest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// runEchoBot handles updates like a simple bot: it answers /start with a
+// keyboard, edits the message when a button is pressed and sends documents
+// back.
+func runEchoBot(t *testing.T, bot *tgbotapi.BotAPI) {
+	updateConfig := tgbotapi.NewUpdate(0)
+	updateConfig.Timeout = 10
+
+	for update := range bot.GetUpdatesChan(updateConfig) {
+		switch {
+		case update.Message != nil && update.Message.IsCommand():
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Pick one")
+			msg.ReplyParameters.MessageID = update.Message.MessageID
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("A", "a"),
+			))
+			if _, err := bot.Send(msg); err != nil {
+				t.Errorf("send: %v", err)
+			}
+		case update.Message != nil:
+			doc := tgbotapi.NewDocument(update.Message.Chat.ID, tgbotapi.FileBytes{Name: "echo.txt", Bytes: []byte(update.Message.Text)})
+			if _, err := bot.Send(doc); err != nil {
+				t.Errorf("send document: %v", err)
+			}
+		case update.CallbackQuery != nil:
+			query := update.CallbackQuery
+			if _, err := bot.Request(tgbotapi.NewCallback(query.ID, "Picked "+query.Data)); err != nil {
+				t.Errorf("answer callback: %v", err)
+			}
+			edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, "Picked "+query.Data)
+			if _, err := bot.Send(edit); err != nil {
+				t.Errorf("edit: %v", err)
+			}
+		}
+	}
+}
+
+func TestServerEndToEnd(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	bot, err := server.NewBot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Self.UserName != "test_bot" {
+		t.Fatalf("unexpected bot user %+v", bot.Self)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runEchoBot(t, bot)
+	}()
+	defer func() {
+		bot.StopReceivingUpdates()
+		<-done
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user := NewUser(42, "Alice")
+	chat := PrivateChat(user)
+
+	command := server.SendText(user, chat, "/start")
+	sent, err := server.WaitSent(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := sent[0]
+	if reply.Text != "Pick one" || reply.ReplyToMessage == nil || reply.ReplyToMessage.MessageID != command.MessageID {
+		t.Fatalf("unexpected reply %+v", reply)
+	}
+	if reply.ReplyMarkup == nil || reply.ReplyMarkup.InlineKeyboard[0][0].Text != "A" {
+		t.Fatalf("expected the keyboard on the reply, got %+v", reply.ReplyMarkup)
+	}
+
+	server.PressButton(user, reply, "a")
+	for len(server.CallbackAnswers()) == 0 || server.Messages(chat.ID)[1].EditDate == 0 {
+		select {
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the callback to be handled")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if answer := server.CallbackAnswers()[0]; answer.Text != "Picked a" {
+		t.Fatalf("unexpected callback answer %+v", answer)
+	}
+	if edited := server.Messages(chat.ID)[1]; edited.Text != "Picked a" || edited.ReplyMarkup != nil {
+		t.Fatalf("unexpected edited message %+v", edited)
+	}
+
+	server.SendText(user, chat, "hello")
+	sent, err = server.WaitSent(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	document := sent[1].Document
+	if document == nil || document.FileName != "echo.txt" || document.FileSize != 5 {
+		t.Fatalf("unexpected document %+v", document)
+	}
+
+	var buf bytes.Buffer
+	if _, err := bot.DownloadFileWithContext(ctx, document.FileID, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "hello" {
+		t.Fatalf("downloaded %q", buf.String())
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	bot, err := server.NewBot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var apiErr *tgbotapi.Error
+
+	_, err = bot.Send(tgbotapi.NewMessage(7, "hi"))
+	if !errors.As(err, &apiErr) || apiErr.Message != "Bad Request: chat not found" {
+		t.Fatalf("expected chat not found, got %v", err)
+	}
+
+	user := NewUser(7, "Bob")
+	server.AddChat(PrivateChat(user))
+	msg, err := bot.Send(tgbotapi.NewMessage(7, "hi"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = bot.Send(tgbotapi.NewEditMessageText(7, msg.MessageID, "hi"))
+	if !errors.As(err, &apiErr) || apiErr.Code != 400 {
+		t.Fatalf("expected message is not modified, got %v", err)
+	}
+
+	if _, err := bot.Request(tgbotapi.NewDeleteMessage(7, msg.MessageID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Request(tgbotapi.NewDeleteMessage(7, msg.MessageID)); err == nil {
+		t.Fatal("expected an error deleting a deleted message")
+	}
+	if messages := server.Messages(7); len(messages) != 0 {
+		t.Fatalf("expected no messages, got %d", len(messages))
+	}
+
+	if _, err := bot.Request(tgbotapi.NewCallback("unknown", "")); err == nil {
+		t.Fatal("expected an error answering an unknown callback query")
+	}
+
+	server.Handle("sendMessage", func(Request) (any, error) {
+		return nil, &tgbotapi.Error{Code: 429, Message: "Too Many Requests", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 3}}
+	})
+	_, err = bot.Send(tgbotapi.NewMessage(7, "hi"))
+	if !errors.As(err, &apiErr) || apiErr.RetryAfter != 3 {
+		t.Fatalf("expected a flood error, got %v", err)
+	}
+
+	if _, err := tgbotapi.NewBotAPIWithOptions("wrong", server.Options()...); err == nil {
+		t.Fatal("expected a wrong token to be rejected")
+	}
+}
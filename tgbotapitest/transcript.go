@@ -0,0 +1,379 @@
+package tgbotapitest
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+var updateTranscripts = flag.Bool("update-transcripts", false, "rewrite the transcript files of RunTranscript")
+
+// UpdateHandler handles an update, like the update loop of a bot.
+type UpdateHandler func(bot tgbotapi.Bot, update tgbotapi.Update)
+
+// Conversation is a chat between a user and a bot, recorded as a transcript.
+// Updates are handled synchronously by an UpdateHandler with a MockBot.
+//
+// Requests of the bot are rendered as lines of the transcript, such as
+// "bot: Welcome! [Sign up] [Help]" for a message with an inline keyboard,
+// "bot edits: ..." for an edited message text or "bot answers: ..." for an
+// answered callback query. Lines of multiline texts after the first, of the
+// user or of the bot, are indented by two spaces. Buttons in different rows are separated by "/".
+type Conversation struct {
+	// Bot is the bot passed to the handler. Results of methods other than
+	// sending, editing and deleting messages and answering callback queries
+	// can be set with SetResult; its Respond must not be changed.
+	Bot *MockBot
+	// BotUser is the sender of the messages of the bot. It is TestBot by
+	// default.
+	BotUser tgbotapi.User
+
+	handler UpdateHandler
+	options []FixtureOption
+	chat    tgbotapi.Chat
+
+	mu       sync.Mutex
+	lines    []string
+	messages []*tgbotapi.Message
+}
+
+// NewConversation creates a Conversation of the user and chat set by the
+// options, TestUser in a private chat by default.
+func NewConversation(handler UpdateHandler, options ...FixtureOption) *Conversation {
+	f := newFixture(options)
+
+	c := &Conversation{
+		Bot:     NewMockBot(),
+		BotUser: TestBot,
+		handler: handler,
+		options: options,
+		chat:    f.chatOr(PrivateChat(f.from)),
+	}
+	c.Bot.Respond = c.respond
+
+	return c
+}
+
+// Say sends a text message from the user.
+func (c *Conversation) Say(text string) {
+	c.addLine(renderLine("user", text, nil))
+	c.handle(NewTestMessage(text, c.fixtureOptions()...))
+}
+
+// SendPhoto sends a photo with a caption from the user.
+func (c *Conversation) SendPhoto(caption string) {
+	c.addLine(renderLine("user sends photo", caption, nil))
+	c.handle(NewTestPhotoMessage(caption, c.fixtureOptions()...))
+}
+
+// Click presses the callback button with a text on the latest message of
+// the bot having it.
+func (c *Conversation) Click(button string) error {
+	message, data, ok := c.findButton(button)
+	if !ok {
+		return fmt.Errorf("no message has a callback button %q", button)
+	}
+
+	c.addLine("user clicks: " + button)
+	c.handle(NewTestCallback(data, message, c.fixtureOptions()...))
+	return nil
+}
+
+// Transcript returns the transcript of the conversation. Every input of the
+// user but the first is preceded by an empty line.
+func (c *Conversation) Transcript() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var b strings.Builder
+	for _, line := range c.lines {
+		if strings.HasPrefix(line, "user") && b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Play plays an input line of a transcript: "user: <text>",
+// "user sends photo: <caption>" or "user clicks: <button>". Other lines are
+// ignored, as they are the output of the bot. The line may continue on
+// following lines indented by two spaces, as in the transcript.
+func (c *Conversation) Play(line string) error {
+	input, arg, _ := strings.Cut(line, ":")
+	arg = strings.ReplaceAll(strings.TrimPrefix(arg, " "), "\n  ", "\n")
+
+	switch input {
+	case "user":
+		c.Say(arg)
+	case "user sends photo":
+		c.SendPhoto(arg)
+	case "user clicks":
+		return c.Click(arg)
+	default:
+		if strings.HasPrefix(line, "user") {
+			return fmt.Errorf("unknown input %q", line)
+		}
+	}
+	return nil
+}
+
+// RunTranscript plays the inputs of the user in the transcript file at path
+// and compares the transcript of the conversation with the file.
+//
+// Running the tests with -update-transcripts rewrites the file instead, so
+// a new transcript can be written as only the inputs of the user.
+func RunTranscript(t testing.TB, path string, handler UpdateHandler, options ...FixtureOption) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !(*updateTranscripts && errors.Is(err, fs.ErrNotExist)) {
+		t.Fatal(err)
+	}
+	want := string(data)
+
+	c := NewConversation(handler, options...)
+	lines := strings.Split(want, "\n")
+	for i := 0; i < len(lines); {
+		start := i
+		for i++; i < len(lines) && strings.HasPrefix(lines[i], "  "); i++ {
+		}
+		if err := c.Play(strings.Join(lines[start:i], "\n")); err != nil {
+			t.Fatalf("%s:%d: %v", path, start+1, err)
+		}
+	}
+
+	got := c.Transcript()
+	if got == want {
+		return
+	}
+
+	if *updateTranscripts {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	line := 0
+	for line < len(wantLines) && line < len(gotLines) && wantLines[line] == gotLines[line] {
+		line++
+	}
+	t.Errorf("%s:%d: transcript differs, run with -update-transcripts to update it\nwant: %q\ngot:  %q\n\ntranscript:\n%s",
+		path, line+1, lineAt(wantLines, line), lineAt(gotLines, line), got)
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
+func (c *Conversation) fixtureOptions() []FixtureOption {
+	return append([]FixtureOption{WithChat(c.chat)}, c.options...)
+}
+
+func (c *Conversation) handle(update tgbotapi.Update) {
+	c.handler(c.Bot, update)
+}
+
+func (c *Conversation) addLine(line string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lines = append(c.lines, line)
+}
+
+func (c *Conversation) findButton(text string) (tgbotapi.Message, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		message := c.messages[i]
+		if message.ReplyMarkup == nil {
+			continue
+		}
+		for _, row := range message.ReplyMarkup.InlineKeyboard {
+			for _, button := range row {
+				if button.Text == text && button.CallbackData != nil {
+					return *message, *button.CallbackData, true
+				}
+			}
+		}
+	}
+	return tgbotapi.Message{}, "", false
+}
+
+// respond renders a request of the bot and returns its result.
+func (c *Conversation) respond(call MockCall) (*tgbotapi.APIResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	params := call.Params
+	markup := inlineKeyboardParam(params)
+
+	var result any = true
+	switch method := call.Method; {
+	case method == "sendChatAction":
+	case method == "sendMessage":
+		message := c.sendLocked(params, markup)
+		c.lines = append(c.lines, renderLine("bot", message.Text, markup))
+		result = message
+	case strings.HasPrefix(method, "send") && method != "sendMediaGroup":
+		message := c.sendLocked(params, markup)
+		kind := strings.ToLower(strings.TrimPrefix(method, "send"))
+		text := firstNonEmpty(params["caption"], params["question"], params["title"])
+		c.lines = append(c.lines, renderLine("bot sends "+kind, text, markup))
+		result = message
+	case method == "editMessageText":
+		message := c.editLocked(params)
+		message.Text = params["text"]
+		message.ReplyMarkup = markup
+		c.lines = append(c.lines, renderLine("bot edits", message.Text, markup))
+		result = *message
+	case method == "editMessageCaption":
+		message := c.editLocked(params)
+		message.Caption = params["caption"]
+		message.ReplyMarkup = markup
+		c.lines = append(c.lines, renderLine("bot edits caption", message.Caption, markup))
+		result = *message
+	case method == "editMessageReplyMarkup":
+		message := c.editLocked(params)
+		message.ReplyMarkup = markup
+		if markup == nil {
+			c.lines = append(c.lines, "bot removes keyboard")
+		} else {
+			c.lines = append(c.lines, renderLine("bot edits keyboard", "", markup))
+		}
+		result = *message
+	case method == "answerCallbackQuery":
+		prefix := "bot answers"
+		if params["show_alert"] == "true" {
+			prefix = "bot alerts"
+		}
+		c.lines = append(c.lines, renderLine(prefix, params["text"], nil))
+	case method == "deleteMessage":
+		text := ""
+		if message := c.messageLocked(params["message_id"]); message != nil {
+			text, _, _ = strings.Cut(firstNonEmpty(message.Text, message.Caption), "\n")
+		}
+		c.lines = append(c.lines, renderLine("bot deletes", text, nil))
+	default:
+		c.lines = append(c.lines, "bot calls "+method)
+		return nil, nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return &tgbotapi.APIResponse{Ok: true, Result: data}, nil
+}
+
+func (c *Conversation) sendLocked(params tgbotapi.Params, markup *tgbotapi.InlineKeyboardMarkup) tgbotapi.Message {
+	from := c.BotUser
+	message := tgbotapi.Message{
+		MessageID:   int(lastMessageID.Add(1)),
+		From:        &from,
+		Chat:        c.chatOf(params["chat_id"]),
+		Text:        params["text"],
+		Caption:     params["caption"],
+		ReplyMarkup: markup,
+	}
+	c.messages = append(c.messages, &message)
+	return message
+}
+
+// editLocked returns the edited message, which is added if the bot did not
+// send it in the conversation.
+func (c *Conversation) editLocked(params tgbotapi.Params) *tgbotapi.Message {
+	if message := c.messageLocked(params["message_id"]); message != nil {
+		return message
+	}
+
+	from := c.BotUser
+	message := &tgbotapi.Message{From: &from, Chat: c.chatOf(params["chat_id"])}
+	message.MessageID, _ = strconv.Atoi(params["message_id"])
+	c.messages = append(c.messages, message)
+	return message
+}
+
+func (c *Conversation) messageLocked(messageID string) *tgbotapi.Message {
+	id, err := strconv.Atoi(messageID)
+	if err != nil {
+		return nil
+	}
+	for _, message := range c.messages {
+		if message.MessageID == id {
+			return message
+		}
+	}
+	return nil
+}
+
+func (c *Conversation) chatOf(chatID string) tgbotapi.Chat {
+	if id, err := strconv.ParseInt(chatID, 10, 64); err == nil && id != c.chat.ID {
+		return tgbotapi.Chat{ID: id}
+	}
+	return c.chat
+}
+
+func inlineKeyboardParam(params tgbotapi.Params) *tgbotapi.InlineKeyboardMarkup {
+	var markup tgbotapi.InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(params["reply_markup"]), &markup); err != nil || len(markup.InlineKeyboard) == 0 {
+		return nil
+	}
+	return &markup
+}
+
+// renderLine renders a line of a transcript such as
+// "bot: Welcome! [Sign up] [Help]".
+func renderLine(prefix, text string, markup *tgbotapi.InlineKeyboardMarkup) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	if text != "" {
+		b.WriteString(": ")
+		b.WriteString(strings.ReplaceAll(text, "\n", "\n  "))
+	}
+
+	if markup != nil {
+		if text == "" {
+			b.WriteString(":")
+		}
+		for i, row := range markup.InlineKeyboard {
+			if i > 0 {
+				b.WriteString(" /")
+			}
+			for _, button := range row {
+				b.WriteString(" [" + button.Text + "]")
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,141 @@
+package tgbotapitest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/OvyFlash/telegram-bot-api"
+)
+
+// signupBot returns a handler offering to sign up with an inline keyboard.
+func signupBot(t *testing.T) UpdateHandler {
+	request := func(bot tgbotapi.Bot, c tgbotapi.Chattable) {
+		if _, err := bot.Request(c); err != nil {
+			t.Errorf("request: %v", err)
+		}
+	}
+
+	return func(bot tgbotapi.Bot, update tgbotapi.Update) {
+		switch {
+		case update.Message != nil && update.Message.Command() == "start":
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Welcome!")
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("Sign up", "signup"),
+				tgbotapi.NewInlineKeyboardButtonData("Help", "help"),
+			))
+			request(bot, msg)
+		case update.CallbackQuery != nil:
+			query := update.CallbackQuery
+			chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+
+			switch query.Data {
+			case "help":
+				request(bot, tgbotapi.NewCallback(query.ID, ""))
+				request(bot, tgbotapi.NewMessage(chatID, "Sign up to get started.\nSend /start to see the menu again."))
+			case "signup":
+				request(bot, tgbotapi.NewCallbackWithAlert(query.ID, "Signed up"))
+				request(bot, tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, "You are signed up.", tgbotapi.NewInlineKeyboardMarkup(
+					tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Profile", "profile")),
+					tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("Sign out", "signout")),
+				)))
+			case "signout":
+				request(bot, tgbotapi.NewCallback(query.ID, "Signed out"))
+				request(bot, tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, tgbotapi.InlineKeyboardMarkup{}))
+				request(bot, tgbotapi.NewDeleteMessage(chatID, messageID))
+			}
+		}
+	}
+}
+
+func TestRunTranscript(t *testing.T) {
+	RunTranscript(t, filepath.Join("testdata", "signup.transcript"), signupBot(t))
+}
+
+// recordingTB records the errors of a test instead of failing it.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Helper() {}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRunTranscriptDiffersAndUpdates(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "signup.transcript"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "signup.transcript")
+
+	changed := strings.Replace(string(golden), "bot: Welcome!", "bot: Hello!", 1)
+	if err := os.WriteFile(path, []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tb := &recordingTB{TB: t}
+	RunTranscript(tb, path, signupBot(t))
+	if len(tb.errors) != 1 || !strings.Contains(tb.errors[0], path+":2: transcript differs") {
+		t.Fatalf("expected the changed line to be reported, got %q", tb.errors)
+	}
+
+	// Only the inputs of the user are needed to write a new transcript.
+	var inputs []string
+	for _, line := range strings.Split(string(golden), "\n") {
+		if strings.HasPrefix(line, "user") {
+			inputs = append(inputs, line)
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(inputs, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	*updateTranscripts = true
+	defer func() { *updateTranscripts = false }()
+	RunTranscript(t, path, signupBot(t))
+
+	updated, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(updated) != string(golden) {
+		t.Fatalf("expected the transcript to be updated to\n%s\ngot\n%s", golden, updated)
+	}
+}
+
+func TestConversationClickUnknownButton(t *testing.T) {
+	c := NewConversation(signupBot(t))
+	c.Say("/start")
+
+	if err := c.Click("Sign out"); err == nil {
+		t.Fatal("expected an error clicking a missing button")
+	}
+	if err := c.Play("user waves: hi"); err == nil {
+		t.Fatal("expected an error playing an unknown input")
+	}
+}
+
+func TestRunTranscriptMultilineInput(t *testing.T) {
+	echo := func(bot tgbotapi.Bot, update tgbotapi.Update) {
+		if _, err := bot.Request(tgbotapi.NewMessage(update.Message.Chat.ID, update.Message.Text)); err != nil {
+			t.Errorf("request: %v", err)
+		}
+	}
+
+	c := NewConversation(echo)
+	c.Say("first line\nsecond line")
+	want := "user: first line\n  second line\nbot: first line\n  second line\n"
+	if got := c.Transcript(); got != want {
+		t.Fatalf("expected transcript\n%s\ngot\n%s", want, got)
+	}
+
+	path := filepath.Join(t.TempDir(), "echo.transcript")
+	if err := os.WriteFile(path, []byte(want), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	RunTranscript(t, path, echo)
+}
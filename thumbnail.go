@@ -0,0 +1,157 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"path"
+	"strings"
+)
+
+// NewThumbnail creates a thumbnail meeting the requirements of Telegram from
+// an image in JPEG, PNG or GIF format.
+//
+// The image is scaled down to fit in 320x320 pixels, transparent areas are
+// filled with white and it is encoded as a JPEG smaller than 200 kB. The
+// result can be used as the Thumb of any config or InputMedia.
+func NewThumbnail(file RequestFileData) (FileBytes, error) {
+	if file == nil || !file.NeedsUpload() {
+		return FileBytes{}, errors.New("thumbnail: source image must be uploaded")
+	}
+
+	name, reader, err := file.UploadData()
+	if err != nil {
+		return FileBytes{}, fmt.Errorf("thumbnail: open source image: %w", err)
+	}
+	src, _, err := image.Decode(reader)
+	if closeErr := closeUploadReader(reader); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return FileBytes{}, fmt.Errorf("thumbnail: decode source image: %w", err)
+	}
+
+	thumb := scaleImage(src, maxThumbnailDimension)
+
+	var buf bytes.Buffer
+	for quality := 90; quality > 0; quality -= 15 {
+		buf.Reset()
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: quality}); err != nil {
+			return FileBytes{}, fmt.Errorf("thumbnail: encode: %w", err)
+		}
+		if buf.Len() <= maxThumbnailSize {
+			return FileBytes{Name: thumbnailName(name), Bytes: buf.Bytes()}, nil
+		}
+	}
+
+	return FileBytes{}, fmt.Errorf("thumbnail: %w: %d bytes, the limit is %d", ErrFileTooBig, buf.Len(), maxThumbnailSize)
+}
+
+// WithGeneratedThumbnail creates a thumbnail from source with NewThumbnail
+// and sets it as the Thumb of media, which must be a pointer to a document,
+// audio, video, animation or video note, as an InputMedia, a paid video or a
+// config. The thumbnail is uploaded next to the file, attached with attach://
+// in media groups.
+func WithGeneratedThumbnail(media any, source RequestFileData) error {
+	var thumb *RequestFileData
+	switch media := media.(type) {
+	case *InputMediaVideo:
+		thumb = &media.Thumb
+	case *InputMediaAnimation:
+		thumb = &media.Thumb
+	case *InputMediaAudio:
+		thumb = &media.Thumb
+	case *InputMediaDocument:
+		thumb = &media.Thumb
+	case *InputPaidMedia:
+		if media.Type != "video" {
+			return fmt.Errorf("thumbnail: paid %s has no thumbnail", media.Type)
+		}
+		thumb = &media.Thumb
+	case *AudioConfig:
+		thumb = &media.Thumb
+	case *DocumentConfig:
+		thumb = &media.Thumb
+	case *VideoConfig:
+		thumb = &media.Thumb
+	case *AnimationConfig:
+		thumb = &media.Thumb
+	case *VideoNoteConfig:
+		thumb = &media.Thumb
+	default:
+		return fmt.Errorf("thumbnail: %T has no thumbnail", media)
+	}
+
+	generated, err := NewThumbnail(source)
+	if err != nil {
+		return err
+	}
+	*thumb = generated
+
+	return nil
+}
+
+// maxThumbnailSamples is the largest number of source pixels averaged per
+// thumbnail pixel along each axis.
+const maxThumbnailSamples = 4
+
+// scaleImage draws src on a white background, scaled down so neither side
+// exceeds limit. Each pixel averages a grid of source pixels sampled
+// directly from src.
+func scaleImage(src image.Image, limit int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	width, height := srcWidth, srcHeight
+	if width > limit || height > limit {
+		if width >= height {
+			width, height = limit, max(1, height*limit/width)
+		} else {
+			width, height = max(1, width*limit/height), limit
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, max((y+1)*srcHeight/height, y*srcHeight/height+1)
+		yStep := (y1 - y0 + maxThumbnailSamples - 1) / maxThumbnailSamples
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, max((x+1)*srcWidth/width, x*srcWidth/width+1)
+			xStep := (x1 - x0 + maxThumbnailSamples - 1) / maxThumbnailSamples
+
+			var sum [3]uint32
+			count := uint32(0)
+			for sy := y0; sy < y1; sy += yStep {
+				for sx := x0; sx < x1; sx += xStep {
+					r, g, b, a := src.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					// Colors are premultiplied, so blending over white adds
+					// the transparent part.
+					sum[0] += r + 0xffff - a
+					sum[1] += g + 0xffff - a
+					sum[2] += b + 0xffff - a
+					count++
+				}
+			}
+
+			offset := y*dst.Stride + x*4
+			dst.Pix[offset] = uint8(sum[0] / count >> 8)
+			dst.Pix[offset+1] = uint8(sum[1] / count >> 8)
+			dst.Pix[offset+2] = uint8(sum[2] / count >> 8)
+			dst.Pix[offset+3] = 0xff
+		}
+	}
+
+	return dst
+}
+
+// thumbnailName returns the name of the thumbnail of a file.
+func thumbnailName(name string) string {
+	base := path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if base == "." || base == "/" {
+		return "thumbnail.jpg"
+	}
+
+	return strings.TrimSuffix(base, path.Ext(base)) + "-thumb.jpg"
+}
@@ -0,0 +1,172 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"testing"
+)
+
+func TestNewThumbnailScalesAndFlattens(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1600, 900))
+	for y := 0; y < 900; y++ {
+		for x := 0; x < 800; x++ {
+			src.Set(x, y, color.NRGBA{R: 0xff, A: 0xff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	thumb, err := NewThumbnail(FileBytes{Name: "dir/cover.png", Bytes: buf.Bytes()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thumb.Name != "cover-thumb.jpg" || len(thumb.Bytes) > maxThumbnailSize {
+		t.Fatalf("unexpected thumbnail %q of %d bytes", thumb.Name, len(thumb.Bytes))
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(thumb.Bytes))
+	if err != nil {
+		t.Fatalf("expected a JPEG thumbnail: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 320 || size.Y != 180 {
+		t.Fatalf("expected 320x180, got %v", size)
+	}
+
+	// The transparent right half is filled with white.
+	r, g, b, _ := img.At(300, 90).RGBA()
+	if r>>8 < 0xf0 || g>>8 < 0xf0 || b>>8 < 0xf0 {
+		t.Fatalf("expected a white background, got %d %d %d", r>>8, g>>8, b>>8)
+	}
+	r, g, _, _ = img.At(20, 90).RGBA()
+	if r>>8 < 0xf0 || g>>8 > 0x20 {
+		t.Fatalf("expected the red half to be kept, got %d %d", r>>8, g>>8)
+	}
+}
+
+func TestNewThumbnailAttachesToInputMedia(t *testing.T) {
+	thumb, err := NewThumbnail(FilePath("tests/image.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	video := NewInputMediaVideo(FilePath("tests/video.mp4"))
+	video.Thumb = thumb
+	media := []InputMedia{&video}
+
+	prepared := prepareInputMediaForParams(media)
+	if got := prepared[0].getThumb(); got != fileAttach("attach://file-0-thumb") {
+		t.Fatalf("expected the thumbnail to be attached, got %v", got)
+	}
+	files := prepareInputMediaForFiles(media)
+	if len(files) != 2 || files[1].Name != "file-0-thumb" {
+		t.Fatalf("expected the thumbnail to be uploaded, got %v", files)
+	}
+	if err := newFakeBot(fakeHTTPClient{}).ValidateUpload(NewMediaGroup(1, media)); err != nil {
+		t.Fatalf("expected a valid thumbnail, got %v", err)
+	}
+}
+
+func TestWithGeneratedThumbnailUploadsMultipart(t *testing.T) {
+	var source bytes.Buffer
+	if err := png.Encode(&source, image.NewNRGBA(image.Rect(0, 0, 640, 480))); err != nil {
+		t.Fatal(err)
+	}
+
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			_, attrs, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+			if err != nil {
+				t.Fatalf("parse content type: %v", err)
+			}
+
+			reader := multipart.NewReader(req.Body, attrs["boundary"])
+			parts := map[string][]byte{}
+			filenames := map[string]string{}
+			for {
+				part, err := reader.NextPart()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("read multipart part: %v", err)
+				}
+				data, err := io.ReadAll(part)
+				if err != nil {
+					t.Fatalf("read multipart data: %v", err)
+				}
+				parts[part.FormName()] = data
+				filenames[part.FormName()] = part.FileName()
+			}
+
+			var media []map[string]any
+			if err := json.Unmarshal(parts["media"], &media); err != nil {
+				t.Fatalf("decode media: %v", err)
+			}
+			if len(media) != 1 || media[0]["media"] != "attach://file-0" || media[0]["thumbnail"] != "attach://file-0-thumb" {
+				t.Fatalf("unexpected media %s", parts["media"])
+			}
+			if string(parts["file-0"]) != "video" {
+				t.Fatalf("unexpected video part %q", parts["file-0"])
+			}
+			if filenames["file-0-thumb"] != "cover-thumb.jpg" {
+				t.Fatalf("unexpected thumbnail name %q", filenames["file-0-thumb"])
+			}
+			img, err := jpeg.Decode(bytes.NewReader(parts["file-0-thumb"]))
+			if err != nil {
+				t.Fatalf("expected a JPEG thumbnail: %v", err)
+			}
+			if size := img.Bounds().Size(); size.X != 320 || size.Y != 240 {
+				t.Fatalf("expected 320x240, got %v", size)
+			}
+
+			return okAPIResponse(), nil
+		},
+	})
+
+	video := NewInputMediaVideo(FileBytes{Name: "video.mp4", Bytes: []byte("video")})
+	if err := WithGeneratedThumbnail(&video, FileBytes{Name: "cover.png", Bytes: source.Bytes()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bot.Request(NewMediaGroup(1, []InputMedia{&video})); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	config := NewDocument(1, FileBytes{Name: "report.pdf", Bytes: []byte("pdf")})
+	if err := WithGeneratedThumbnail(&config, FileBytes{Name: "cover.png", Bytes: source.Bytes()}); err != nil {
+		t.Fatal(err)
+	}
+	if thumb, ok := config.Thumb.(FileBytes); !ok || thumb.Name != "cover-thumb.jpg" {
+		t.Fatalf("expected a generated thumbnail, got %v", config.Thumb)
+	}
+
+	photo := NewInputMediaPhoto(FileBytes{Name: "photo.jpg", Bytes: []byte("photo")})
+	if err := WithGeneratedThumbnail(&photo, FileBytes{Name: "cover.png", Bytes: source.Bytes()}); err == nil {
+		t.Fatal("expected an error for media without a thumbnail")
+	}
+	for _, media := range []any{
+		&PhotoConfig{},
+		&VoiceConfig{},
+		&InputPaidMedia{Type: "photo"},
+	} {
+		if err := WithGeneratedThumbnail(media, FileBytes{Name: "cover.png", Bytes: source.Bytes()}); err == nil {
+			t.Fatalf("expected an error for %T without a thumbnail", media)
+		}
+	}
+}
+
+func TestNewThumbnailRejectsFileIDs(t *testing.T) {
+	if _, err := NewThumbnail(FileID("id")); err == nil {
+		t.Fatal("expected an error for a file ID")
+	}
+}
@@ -5014,6 +5014,29 @@ type InputSticker struct {
 	Keywords []string `json:"keywords"`
 }
 
+// MarshalJSON references the sticker by its file ID or URL, or as an
+// attachment named after the RequestFile if it needs to be uploaded.
+func (sticker InputSticker) MarshalJSON() ([]byte, error) {
+	type inputSticker InputSticker
+
+	var file string
+	if data := sticker.Sticker.Data; data != nil {
+		if data.NeedsUpload() {
+			file = "attach://" + sticker.Sticker.Name
+		} else {
+			file = data.SendData()
+		}
+	}
+
+	return json.Marshal(struct {
+		inputSticker
+		Sticker string `json:"sticker"`
+	}{
+		inputSticker: inputSticker(sticker),
+		Sticker:      file,
+	})
+}
+
 // Game represents a game. Use BotFather to create and edit games, their short
 // names will act as unique identifiers.
 type Game struct {
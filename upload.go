@@ -1,9 +1,12 @@
 package tgbotapi
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/textproto"
+	"path"
 	"strings"
 )
 
@@ -11,6 +14,8 @@ type requestPayload struct {
 	body        io.Reader
 	closer      io.Closer
 	contentType string
+	// contentLength is the size of body, or 0 if it is unknown.
+	contentLength int64
 }
 
 func (p requestPayload) close() {
@@ -119,12 +124,23 @@ func buildFormPayload(params Params) requestPayload {
 	}
 }
 
-func buildMultipartPayload(params Params, files []RequestFile) (requestPayload, error) {
+// buildMultipartPayload returns a multipart body with params and files.
+//
+// If the name and size of every upload are known, the body is read straight
+// from the files and its length is known. Otherwise it is written through a
+// pipe by a goroutine and sent with chunked encoding.
+func buildMultipartPayload(params Params, files []RequestFile, tracker *uploadTracker) (requestPayload, error) {
+	if payload, ok := buildSizedMultipartPayload(params, files, tracker); ok {
+		return payload, nil
+	}
+
 	reader, writer := io.Pipe()
 	multipartWriter := multipart.NewWriter(writer)
 
 	go func() {
-		if err := writeMultipartPayload(multipartWriter, params, files); err != nil {
+		defer tracker.finish()
+
+		if err := writeMultipartPayload(multipartWriter, params, files, tracker); err != nil {
 			_ = writer.CloseWithError(err)
 			return
 		}
@@ -142,7 +158,218 @@ func buildMultipartPayload(params Params, files []RequestFile) (requestPayload,
 	}, nil
 }
 
-func writeMultipartPayload(writer *multipart.Writer, params Params, files []RequestFile) error {
+// buildSizedMultipartPayload returns a multipart body of a known length, if
+// the name and size of every upload are known.
+func buildSizedMultipartPayload(params Params, files []RequestFile, tracker *uploadTracker) (requestPayload, bool) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	// Segments of the body between uploads are offsets in buf until it is
+	// complete.
+	type pendingUpload struct {
+		start int
+		multipartUpload
+	}
+	var uploads []pendingUpload
+
+	for field, value := range params {
+		if err := writer.WriteField(field, value); err != nil {
+			return requestPayload{}, false
+		}
+	}
+
+	var length int64
+	for _, file := range files {
+		if file.Data == nil {
+			return requestPayload{}, false
+		}
+		if !file.Data.NeedsUpload() {
+			if err := writer.WriteField(file.Name, file.Data.SendData()); err != nil {
+				return requestPayload{}, false
+			}
+			continue
+		}
+
+		name, ok := uploadName(file.Data)
+		if !ok {
+			return requestPayload{}, false
+		}
+		size, ok := uploadSize(file.Data)
+		if !ok {
+			return requestPayload{}, false
+		}
+		if _, err := createFormFile(writer, file.Name, name, uploadContentType(file.Data)); err != nil {
+			return requestPayload{}, false
+		}
+
+		uploads = append(uploads, pendingUpload{
+			start:           buf.Len(),
+			multipartUpload: multipartUpload{file: file, name: name, size: size},
+		})
+		length += size
+	}
+	if err := writer.Close(); err != nil {
+		return requestPayload{}, false
+	}
+
+	body := &multipartBody{tracker: tracker}
+	start := 0
+	for _, upload := range uploads {
+		body.segments = append(body.segments,
+			multipartUpload{data: buf.Bytes()[start:upload.start]},
+			upload.multipartUpload,
+		)
+		start = upload.start
+	}
+	body.segments = append(body.segments, multipartUpload{data: buf.Bytes()[start:]})
+
+	return requestPayload{
+		body:          body,
+		closer:        body,
+		contentType:   writer.FormDataContentType(),
+		contentLength: length + int64(buf.Len()),
+	}, true
+}
+
+// multipartUpload is a segment of a multipart body: either data, or a file
+// to upload when the body is read.
+type multipartUpload struct {
+	data []byte
+	file RequestFile
+	name string
+	size int64
+}
+
+// multipartBody reads the segments of a multipart body one after another,
+// opening each upload when it is reached.
+type multipartBody struct {
+	segments []multipartUpload
+	tracker  *uploadTracker
+	current  io.Reader
+	upload   io.Reader
+}
+
+func (b *multipartBody) Read(p []byte) (int, error) {
+	for {
+		if b.current == nil {
+			if len(b.segments) == 0 {
+				b.tracker.finish()
+				return 0, io.EOF
+			}
+			if err := b.next(); err != nil {
+				return 0, err
+			}
+		}
+
+		n, err := b.current.Read(p)
+		if err != io.EOF {
+			return n, err
+		}
+		if err := b.closeUpload(); err != nil {
+			return n, err
+		}
+		b.current = nil
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (b *multipartBody) next() error {
+	segment := b.segments[0]
+	b.segments = b.segments[1:]
+
+	if segment.file.Data == nil {
+		b.current = bytes.NewReader(segment.data)
+		return nil
+	}
+
+	_, reader, err := segment.file.Data.UploadData()
+	if err != nil {
+		return fmt.Errorf("open upload %q: %w", segment.file.Name, err)
+	}
+	if reader == nil {
+		return fmt.Errorf("open upload %q: nil reader", segment.file.Name)
+	}
+
+	b.upload = reader
+	b.current = b.tracker.reader(segment.file.Name, segment.name, &sizedReader{
+		field:     segment.file.Name,
+		reader:    reader,
+		remaining: segment.size,
+	})
+	return nil
+}
+
+func (b *multipartBody) closeUpload() error {
+	if b.upload == nil {
+		return nil
+	}
+
+	err := closeUploadReader(b.upload)
+	b.upload = nil
+	return err
+}
+
+// Close closes the upload being read.
+func (b *multipartBody) Close() error {
+	b.segments = nil
+	b.current = nil
+	return b.closeUpload()
+}
+
+// sizedReader reads an upload which must have exactly the size used for the
+// Content-Length of the request.
+type sizedReader struct {
+	field     string
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *sizedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		var extra [1]byte
+		if n, _ := io.ReadFull(r.reader, extra[:]); n > 0 {
+			return 0, fmt.Errorf("upload %q is larger than its size", r.field)
+		}
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if err == io.EOF && r.remaining > 0 {
+		return n, fmt.Errorf("upload %q is smaller than its size: %w", r.field, io.ErrUnexpectedEOF)
+	}
+	if err == io.EOF {
+		err = nil
+	}
+
+	return n, err
+}
+
+// uploadName returns the file name of the data to upload, if it is known
+// without opening it. It matches the name returned by UploadData.
+func uploadName(data RequestFileData) (string, bool) {
+	switch data := data.(type) {
+	case FileBytes:
+		return data.Name, true
+	case FileReader:
+		return data.Name, true
+	case FilePath:
+		return string(data), true
+	case FileFS:
+		return path.Base(data.Path), true
+	case FileOpener:
+		return data.Name, true
+	}
+
+	return "", false
+}
+
+func writeMultipartPayload(writer *multipart.Writer, params Params, files []RequestFile, tracker *uploadTracker) error {
 	for field, value := range params {
 		if err := writer.WriteField(field, value); err != nil {
 			return fmt.Errorf("write multipart field %q: %w", field, err)
@@ -155,7 +382,7 @@ func writeMultipartPayload(writer *multipart.Writer, params Params, files []Requ
 		}
 
 		if file.Data.NeedsUpload() {
-			if err := writeMultipartUpload(writer, file); err != nil {
+			if err := writeMultipartUpload(writer, file, tracker); err != nil {
 				return err
 			}
 			continue
@@ -169,7 +396,7 @@ func writeMultipartPayload(writer *multipart.Writer, params Params, files []Requ
 	return nil
 }
 
-func writeMultipartUpload(writer *multipart.Writer, file RequestFile) error {
+func writeMultipartUpload(writer *multipart.Writer, file RequestFile, tracker *uploadTracker) error {
 	name, reader, err := file.Data.UploadData()
 	if err != nil {
 		return fmt.Errorf("open upload %q: %w", file.Name, err)
@@ -178,7 +405,7 @@ func writeMultipartUpload(writer *multipart.Writer, file RequestFile) error {
 		return fmt.Errorf("open upload %q: nil reader", file.Name)
 	}
 
-	part, err := writer.CreateFormFile(file.Name, name)
+	part, err := createFormFile(writer, file.Name, name, uploadContentType(file.Data))
 	if err != nil {
 		if closeErr := closeUploadReader(reader); closeErr != nil {
 			return fmt.Errorf("create multipart file %q: %w; close upload: %w", file.Name, err, closeErr)
@@ -186,7 +413,7 @@ func writeMultipartUpload(writer *multipart.Writer, file RequestFile) error {
 		return fmt.Errorf("create multipart file %q: %w", file.Name, err)
 	}
 
-	_, copyErr := io.Copy(part, reader)
+	_, copyErr := io.Copy(part, tracker.reader(file.Name, name, reader))
 	closeErr := closeUploadReader(reader)
 	if copyErr != nil {
 		if closeErr != nil {
@@ -201,6 +428,35 @@ func writeMultipartUpload(writer *multipart.Writer, file RequestFile) error {
 	return nil
 }
 
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// createFormFile creates a form file like multipart.Writer.CreateFormFile,
+// with the content type of the file if it is known.
+func createFormFile(writer *multipart.Writer, field, name, contentType string) (io.Writer, error) {
+	if contentType == "" {
+		return writer.CreateFormFile(field, name)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(field), quoteEscaper.Replace(name)))
+	header.Set("Content-Type", contentType)
+	return writer.CreatePart(header)
+}
+
+// uploadContentType returns the MIME type of the data to upload, if it is
+// known.
+func uploadContentType(data RequestFileData) string {
+	switch data := data.(type) {
+	case FileFS:
+		return data.ContentType
+	case FileOpener:
+		return data.ContentType
+	}
+
+	return ""
+}
+
 func closeUploadReader(reader io.Reader) error {
 	if closer, ok := reader.(io.Closer); ok {
 		return closer.Close()
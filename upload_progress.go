@@ -0,0 +1,233 @@
+package tgbotapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrUploadStalled is returned when an upload is cancelled because no bytes
+// were sent for the stall timeout, see ContextWithUploadStallTimeout.
+var ErrUploadStalled = errors.New("upload stalled")
+
+// UploadProgress describes the progress of a multipart upload.
+type UploadProgress struct {
+	// Field is the form field of the file being uploaded.
+	Field string
+	// FileName is the name of the file being uploaded.
+	FileName string
+	// FileSent is the number of bytes of the file sent so far.
+	FileSent int64
+	// FileTotal is the size of the file, or -1 if it is unknown.
+	FileTotal int64
+	// Sent is the number of bytes of all files sent so far.
+	Sent int64
+	// Total is the size of all files, or -1 if any size is unknown.
+	Total int64
+	// Rate is the average upload rate in bytes per second.
+	Rate float64
+}
+
+// UploadProgressFunc is called as an upload makes progress.
+//
+// It is called from the goroutine writing the request body and should
+// return quickly.
+type UploadProgressFunc func(UploadProgress)
+
+type uploadProgressKey struct{}
+
+type uploadStallTimeoutKey struct{}
+
+// ContextWithUploadProgress returns a copy of ctx which makes uploads
+// requested with it report their progress to progress.
+func ContextWithUploadProgress(ctx context.Context, progress UploadProgressFunc) context.Context {
+	return context.WithValue(ctx, uploadProgressKey{}, progress)
+}
+
+// ContextWithUploadStallTimeout returns a copy of ctx which makes uploads
+// requested with it fail with ErrUploadStalled when no bytes are sent for
+// timeout. Waiting for the response once the upload is complete is not
+// limited.
+func ContextWithUploadStallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, uploadStallTimeoutKey{}, timeout)
+}
+
+// uploadTracker reports the progress of the files of a multipart request and
+// detects stalled uploads.
+type uploadTracker struct {
+	progress UploadProgressFunc
+	timeout  time.Duration
+	timer    *time.Timer
+	start    time.Time
+
+	mu    sync.Mutex
+	sizes map[string]int64
+	total int64
+	sent  int64
+}
+
+// newUploadTracker returns a tracker for uploading files with ctx, or nil if
+// ctx asks for neither progress nor stall detection. The returned context is
+// cancelled when the upload stalls and stop releases its resources.
+func newUploadTracker(ctx context.Context, files []RequestFile) (context.Context, *uploadTracker, func()) {
+	progress, _ := ctx.Value(uploadProgressKey{}).(UploadProgressFunc)
+	timeout, _ := ctx.Value(uploadStallTimeoutKey{}).(time.Duration)
+	if progress == nil && timeout <= 0 {
+		return ctx, nil, func() {}
+	}
+
+	tracker := &uploadTracker{
+		progress: progress,
+		timeout:  timeout,
+		start:    time.Now(),
+		sizes:    make(map[string]int64, len(files)),
+	}
+
+	for _, file := range files {
+		if file.Data == nil || !file.Data.NeedsUpload() {
+			continue
+		}
+
+		size, ok := uploadSize(file.Data)
+		if !ok {
+			size = -1
+		}
+		tracker.sizes[file.Name] = size
+
+		switch {
+		case size < 0:
+			tracker.total = -1
+		case tracker.total >= 0:
+			tracker.total += size
+		}
+	}
+
+	if timeout <= 0 {
+		return ctx, tracker, func() {}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	tracker.timer = time.AfterFunc(timeout, func() {
+		cancel(ErrUploadStalled)
+	})
+
+	return ctx, tracker, func() {
+		tracker.timer.Stop()
+		cancel(nil)
+	}
+}
+
+// reader wraps the reader of an uploaded file to track its progress.
+func (t *uploadTracker) reader(field, name string, reader io.Reader) io.Reader {
+	if t == nil {
+		return reader
+	}
+
+	return &progressReader{
+		reader:  reader,
+		tracker: t,
+		progress: UploadProgress{
+			Field:     field,
+			FileName:  name,
+			FileTotal: t.sizes[field],
+		},
+	}
+}
+
+// finish stops stall detection once the whole request body was written.
+func (t *uploadTracker) finish() {
+	if t != nil && t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+func (t *uploadTracker) advance(progress *UploadProgress, n int64) {
+	if t.timer != nil {
+		t.timer.Reset(t.timeout)
+	}
+
+	t.mu.Lock()
+	t.sent += n
+	progress.FileSent += n
+	progress.Sent = t.sent
+	progress.Total = t.total
+	t.mu.Unlock()
+
+	if t.progress == nil {
+		return
+	}
+	if elapsed := time.Since(t.start).Seconds(); elapsed > 0 {
+		progress.Rate = float64(progress.Sent) / elapsed
+	}
+	t.progress(*progress)
+}
+
+type progressReader struct {
+	reader   io.Reader
+	tracker  *uploadTracker
+	progress UploadProgress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.tracker.advance(&r.progress, int64(n))
+	}
+
+	return n, err
+}
+
+// uploadSize returns the size of the data to upload, if it is known without
+// reading it.
+func uploadSize(data RequestFileData) (int64, bool) {
+	switch data := data.(type) {
+	case FileBytes:
+		return int64(len(data.Bytes)), true
+	case FilePath:
+		info, err := os.Stat(string(data))
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		return info.Size(), true
+	case FileReader:
+		return readerSize(data.Reader)
+	case FileFS:
+		info, err := fs.Stat(data.FS, data.Path)
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		return info.Size(), true
+	case FileOpener:
+		return data.Size, data.Size > 0
+	}
+
+	return 0, false
+}
+
+// readerSize returns the number of bytes left in reader, if it is known.
+func readerSize(reader io.Reader) (int64, bool) {
+	switch reader := reader.(type) {
+	case interface{ Len() int }:
+		return int64(reader.Len()), true
+	case interface {
+		Stat() (fs.FileInfo, error)
+		io.Seeker
+	}:
+		// Files of the OS and of most fs.FS implementations.
+		info, err := reader.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := reader.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return info.Size() - offset, true
+	}
+
+	return 0, false
+}
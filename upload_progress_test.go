@@ -0,0 +1,84 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadProgressReportsFilesAndTotal(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			if _, err := io.Copy(io.Discard, req.Body); err != nil {
+				t.Fatalf("read request body: %v", err)
+			}
+			return okAPIResponse(), nil
+		},
+	})
+
+	var updates []UploadProgress
+	ctx := ContextWithUploadProgress(context.Background(), func(progress UploadProgress) {
+		updates = append(updates, progress)
+	})
+
+	files := []RequestFile{
+		{Name: "document", Data: FileBytes{Name: "a.txt", Bytes: bytes.Repeat([]byte("a"), 100<<10)}},
+		{Name: "thumbnail", Data: FileReader{Name: "b.jpg", Reader: strings.NewReader("thumb")}},
+	}
+	if _, err := bot.UploadFilesWithContext(ctx, "sendDocument", Params{"chat_id": "1"}, files); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(updates) < 2 {
+		t.Fatalf("expected several progress updates, got %d", len(updates))
+	}
+	last := updates[len(updates)-1]
+	total := int64(100<<10 + 5)
+	if last.Field != "thumbnail" || last.FileName != "b.jpg" || last.FileSent != 5 || last.FileTotal != 5 {
+		t.Fatalf("unexpected last file progress: %+v", last)
+	}
+	if last.Sent != total || last.Total != total || last.Rate <= 0 {
+		t.Fatalf("unexpected overall progress: %+v", last)
+	}
+	for _, update := range updates {
+		if update.Field == "document" && update.FileTotal != 100<<10 {
+			t.Fatalf("unexpected document total: %+v", update)
+		}
+	}
+}
+
+func TestUploadSizeUnknownReader(t *testing.T) {
+	size, ok := uploadSize(FileReader{Name: "pipe", Reader: io.MultiReader(strings.NewReader("x"))})
+	if ok || size != 0 {
+		t.Fatalf("expected unknown size, got %d", size)
+	}
+}
+
+func TestUploadStallTimeoutCancelsRequest(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			// Read the form fields but never the file content.
+			buf := make([]byte, 64)
+			_, _ = req.Body.Read(buf)
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		},
+	})
+
+	ctx := ContextWithUploadStallTimeout(context.Background(), 50*time.Millisecond)
+	files := []RequestFile{{Name: "video", Data: FileBytes{Name: "v.mp4", Bytes: make([]byte, 1<<20)}}}
+
+	start := time.Now()
+	_, err := bot.UploadFilesWithContext(ctx, "sendVideo", Params{"chat_id": "1"}, files)
+	if !errors.Is(err, ErrUploadStalled) {
+		t.Fatalf("expected ErrUploadStalled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("stalled upload took %s to cancel", elapsed)
+	}
+}
@@ -0,0 +1,159 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func readMultipartPayload(t testing.TB, payload requestPayload) map[string]string {
+	t.Helper()
+	defer payload.close()
+
+	_, attrs, err := mime.ParseMediaType(payload.contentType)
+	if err != nil {
+		t.Fatalf("parse content type: %v", err)
+	}
+	body, err := io.ReadAll(payload.body)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	if payload.contentLength > 0 && int64(len(body)) != payload.contentLength {
+		t.Fatalf("expected %d bytes, read %d", payload.contentLength, len(body))
+	}
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(bytes.NewReader(body), attrs["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part data: %v", err)
+		}
+		parts[part.FormName()] = part.FileName() + ":" + string(data)
+	}
+}
+
+func TestMultipartPayloadKnownLength(t *testing.T) {
+	files := []RequestFile{
+		{Name: "photo", Data: FileBytes{Name: "a.jpg", Bytes: []byte("photo")}},
+		{Name: "thumbnail", Data: FileReader{Name: "b.jpg", Reader: strings.NewReader("thumb")}},
+		{Name: "document", Data: FilePath("tests/image.jpg")},
+		{Name: "cover", Data: FileID("cover-id")},
+	}
+
+	payload, err := buildMultipartPayload(Params{"chat_id": "1"}, files, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.contentLength == 0 {
+		t.Fatal("expected a known content length")
+	}
+	if _, ok := payload.body.(*io.PipeReader); ok {
+		t.Fatal("expected the body not to be written through a pipe")
+	}
+
+	parts := readMultipartPayload(t, payload)
+	if parts["chat_id"] != ":1" || parts["photo"] != "a.jpg:photo" || parts["thumbnail"] != "b.jpg:thumb" || parts["cover"] != ":cover-id" {
+		t.Fatalf("unexpected parts: %v", parts)
+	}
+	if !strings.HasPrefix(parts["document"], "image.jpg:\xff\xd8") {
+		t.Fatalf("unexpected document part: %.20q", parts["document"])
+	}
+}
+
+func TestMultipartPayloadUnknownLengthUsesPipe(t *testing.T) {
+	files := []RequestFile{
+		{Name: "document", Data: FileReader{Name: "a.txt", Reader: io.MultiReader(strings.NewReader("streamed"))}},
+	}
+
+	payload, err := buildMultipartPayload(Params{"chat_id": "1"}, files, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.contentLength != 0 {
+		t.Fatalf("expected an unknown content length, got %d", payload.contentLength)
+	}
+	if parts := readMultipartPayload(t, payload); parts["document"] != "a.txt:streamed" {
+		t.Fatalf("unexpected parts: %v", parts)
+	}
+}
+
+func TestMultipartPayloadSizeMismatch(t *testing.T) {
+	file := FileOpener{
+		Name: "a.txt",
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("short")), nil
+		},
+		Size: 10,
+	}
+
+	payload, err := buildMultipartPayload(nil, []RequestFile{{Name: "document", Data: file}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer payload.close()
+	if _, err := io.ReadAll(payload.body); err == nil || !strings.Contains(err.Error(), "smaller than its size") {
+		t.Fatalf("expected a size mismatch error, got %v", err)
+	}
+}
+
+func TestUploadFilesSendsContentLength(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{
+		do: func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			if req.ContentLength <= 0 || req.ContentLength != int64(len(body)) {
+				t.Fatalf("expected Content-Length %d, got %d", len(body), req.ContentLength)
+			}
+			return okAPIResponse(), nil
+		},
+	})
+
+	files := []RequestFile{{Name: "document", Data: FileBytes{Name: "a.txt", Bytes: []byte("content")}}}
+	if _, err := bot.UploadFiles("sendDocument", Params{"chat_id": "1"}, files); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func benchmarkMultipartPayload(b *testing.B, file func(content []byte) RequestFileData) {
+	content := bytes.Repeat([]byte("x"), 1<<20)
+	params := Params{"chat_id": "1", "caption": "benchmark"}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	for i := 0; i < b.N; i++ {
+		payload, err := buildMultipartPayload(params, []RequestFile{{Name: "document", Data: file(content)}}, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, payload.body); err != nil {
+			b.Fatal(err)
+		}
+		payload.close()
+	}
+}
+
+func BenchmarkMultipartPayloadKnownLength(b *testing.B) {
+	benchmarkMultipartPayload(b, func(content []byte) RequestFileData {
+		return FileReader{Name: "a.bin", Reader: bytes.NewReader(content)}
+	})
+}
+
+func BenchmarkMultipartPayloadPipe(b *testing.B) {
+	benchmarkMultipartPayload(b, func(content []byte) RequestFileData {
+		return FileReader{Name: "a.bin", Reader: io.MultiReader(bytes.NewReader(content))}
+	})
+}
@@ -0,0 +1,534 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidDimensions is returned when an uploaded image or video does
+	// not have the dimensions required by Telegram.
+	ErrInvalidDimensions = errors.New("invalid dimensions")
+
+	// ErrUnsupportedFormat is returned when an uploaded file is not in a format
+	// accepted by Telegram.
+	ErrUnsupportedFormat = errors.New("unsupported format")
+)
+
+// Limits of uploaded media documented by the Bot API.
+const (
+	maxPhotoUploadSize     = 10 << 20
+	maxPhotoDimensionsSum  = 10000
+	maxPhotoAspectRatio    = 20
+	maxThumbnailSize       = 200 << 10
+	maxThumbnailDimension  = 320
+	maxStaticStickerSize   = 512 << 10
+	maxAnimatedStickerSize = 64 << 10
+	maxVideoStickerSize    = 256 << 10
+	stickerDimension       = 512
+	customEmojiDimension   = 100
+)
+
+// Content types reported by http.DetectContentType for uploaded media.
+const (
+	contentTypeGzip = "application/x-gzip"
+	contentTypeJPEG = "image/jpeg"
+	contentTypePNG  = "image/png"
+	contentTypeWebP = "image/webp"
+	contentTypeWebM = "video/webm"
+	contentTypeMP4  = "video/mp4"
+)
+
+// UploadError describes a file of a request which does not meet the
+// constraints of Telegram.
+type UploadError struct {
+	// Field is the parameter holding the file, such as "photo" or
+	// "media[1].thumbnail". Files of InputMedia are named after the item,
+	// such as "media[1]", never after the multipart part they are sent in.
+	Field string
+	Err   error
+}
+
+func (e *UploadError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateUpload checks the files uploaded by config against the limits
+// documented by Telegram, without making any request.
+//
+// Every uploaded file is checked against MaxUploadSize. Photos, video notes,
+// stickers, thumbnails and InputMedia are also checked for their size,
+// format and dimensions. Only content which can be read without consuming it
+// is inspected: FileBytes, FilePath, and FileReader, FileFS and FileOpener
+// with a reader implementing io.ReaderAt and io.Seeker. File IDs and URLs are
+// not checked.
+//
+// All violations are returned joined, each as an *UploadError wrapping
+// ErrFileTooBig, ErrInvalidDimensions or ErrUnsupportedFormat.
+func (bot *BotAPI) ValidateUpload(config Chattable) error {
+	v := uploadValidator{}
+
+	switch config := config.(type) {
+	case PhotoConfig:
+		v.photo("photo", config.File)
+		v.thumbnail("thumbnail", config.Thumb)
+	case AudioConfig:
+		v.thumbnail("thumbnail", config.Thumb)
+	case DocumentConfig:
+		v.thumbnail("thumbnail", config.Thumb)
+	case VideoConfig:
+		v.thumbnail("thumbnail", config.Thumb)
+	case AnimationConfig:
+		v.thumbnail("thumbnail", config.Thumb)
+	case VideoNoteConfig:
+		v.videoNote("video_note", config.File)
+		v.thumbnail("thumbnail", config.Thumb)
+	case StickerConfig:
+		v.sticker("sticker", config.File)
+	case UploadStickerConfig:
+		v.stickerFile("sticker", config.Sticker.Data, config.StickerFormat)
+	case MediaGroupConfig:
+		for i, media := range config.Media {
+			v.inputMedia(fmt.Sprintf("media[%d]", i), media)
+		}
+	case EditMessageMediaConfig:
+		v.inputMedia("media", config.Media)
+	}
+
+	if fileable, ok := config.(Fileable); ok {
+		params, _ := config.params()
+		for _, file := range fileable.files() {
+			if file.Data == nil || !file.Data.NeedsUpload() {
+				continue
+			}
+			if size, ok := uploadSize(file.Data); ok && size > bot.MaxUploadSize() {
+				v.fail(uploadField(params, file.Name), ErrFileTooBig, "%d bytes, the limit is %d", size, bot.MaxUploadSize())
+			}
+		}
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// uploadField returns the parameter holding the uploaded file name, such as
+// "media[1].thumbnail" for a file attached to the second item of a media
+// group with attach://.
+func uploadField(params Params, name string) string {
+	attach := "attach://" + name
+	for key, param := range params {
+		if !strings.Contains(param, strconv.Quote(attach)) {
+			continue
+		}
+
+		var value any
+		if err := json.Unmarshal([]byte(param), &value); err != nil {
+			continue
+		}
+		if path, ok := attachPath(value, attach); ok {
+			return key + path
+		}
+	}
+
+	return name
+}
+
+// attachPath returns the path to the attach string within a decoded JSON
+// value. The "media" field holding the file of an InputMedia is left out.
+func attachPath(value any, attach string) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return "", value == attach
+	case []any:
+		for i, item := range value {
+			if path, ok := attachPath(item, attach); ok {
+				return fmt.Sprintf("[%d]%s", i, path), true
+			}
+		}
+	case map[string]any:
+		for key, item := range value {
+			path, ok := attachPath(item, attach)
+			if !ok {
+				continue
+			}
+			if key == "media" && path == "" {
+				return "", true
+			}
+			return "." + key + path, true
+		}
+	}
+
+	return "", false
+}
+
+type uploadValidator struct {
+	errs []error
+}
+
+func (v *uploadValidator) fail(field string, err error, format string, args ...any) {
+	v.errs = append(v.errs, &UploadError{
+		Field: field,
+		Err:   fmt.Errorf("%w: "+format, append([]any{err}, args...)...),
+	})
+}
+
+func (v *uploadValidator) inputMedia(field string, media InputMedia) {
+	if media == nil {
+		return
+	}
+
+	if media.getType() == "photo" {
+		v.photo(field, media.getMedia())
+	}
+	v.thumbnail(field+".thumbnail", media.getThumb())
+}
+
+func (v *uploadValidator) photo(field string, data RequestFileData) {
+	v.inspect(field, data, func(content *uploadContent) {
+		if content.size > maxPhotoUploadSize {
+			v.fail(field, ErrFileTooBig, "photo is %d bytes, the limit is %d", content.size, maxPhotoUploadSize)
+		}
+		if !content.isImage() {
+			v.fail(field, ErrUnsupportedFormat, "photo is %s", content.contentType)
+			return
+		}
+
+		width, height, ok := content.imageDimensions()
+		if !ok {
+			return
+		}
+		if width+height > maxPhotoDimensionsSum {
+			v.fail(field, ErrInvalidDimensions, "photo is %dx%d, width and height must not exceed %d in total", width, height, maxPhotoDimensionsSum)
+		}
+		if long, short := max(width, height), min(width, height); short == 0 || long > short*maxPhotoAspectRatio {
+			v.fail(field, ErrInvalidDimensions, "photo is %dx%d, the aspect ratio must be at most %d", width, height, maxPhotoAspectRatio)
+		}
+	})
+}
+
+func (v *uploadValidator) thumbnail(field string, data RequestFileData) {
+	v.inspect(field, data, func(content *uploadContent) {
+		if content.size > maxThumbnailSize {
+			v.fail(field, ErrFileTooBig, "thumbnail is %d bytes, the limit is %d", content.size, maxThumbnailSize)
+		}
+		if content.contentType != contentTypeJPEG {
+			v.fail(field, ErrUnsupportedFormat, "thumbnail is %s, it must be JPEG", content.contentType)
+			return
+		}
+
+		width, height, ok := content.imageDimensions()
+		if ok && (width > maxThumbnailDimension || height > maxThumbnailDimension) {
+			v.fail(field, ErrInvalidDimensions, "thumbnail is %dx%d, the limit is %dx%d", width, height, maxThumbnailDimension, maxThumbnailDimension)
+		}
+	})
+}
+
+func (v *uploadValidator) videoNote(field string, data RequestFileData) {
+	v.inspect(field, data, func(content *uploadContent) {
+		if content.contentType != contentTypeMP4 {
+			v.fail(field, ErrUnsupportedFormat, "video note is %s, it must be MP4", content.contentType)
+			return
+		}
+
+		width, height, ok := mp4Dimensions(content.reader)
+		if ok && width != height {
+			v.fail(field, ErrInvalidDimensions, "video note is %dx%d, it must be square", width, height)
+		}
+	})
+}
+
+func (v *uploadValidator) sticker(field string, data RequestFileData) {
+	v.inspect(field, data, func(content *uploadContent) {
+		switch content.contentType {
+		case contentTypeWebP, contentTypeGzip, contentTypeWebM:
+		default:
+			v.fail(field, ErrUnsupportedFormat, "sticker is %s, it must be WEBP, TGS or WEBM", content.contentType)
+		}
+	})
+}
+
+func (v *uploadValidator) stickerFile(field string, data RequestFileData, format string) {
+	v.inspect(field, data, func(content *uploadContent) {
+		switch format {
+		case "static":
+			if content.size > maxStaticStickerSize {
+				v.fail(field, ErrFileTooBig, "static sticker is %d bytes, the limit is %d", content.size, maxStaticStickerSize)
+			}
+			if content.contentType != contentTypePNG && content.contentType != contentTypeWebP {
+				v.fail(field, ErrUnsupportedFormat, "static sticker is %s, it must be PNG or WEBP", content.contentType)
+				return
+			}
+
+			width, height, ok := content.imageDimensions()
+			if !ok {
+				return
+			}
+			isSticker := max(width, height) == stickerDimension
+			isEmoji := width == customEmojiDimension && height == customEmojiDimension
+			if !isSticker && !isEmoji {
+				v.fail(field, ErrInvalidDimensions, "static sticker is %dx%d, one side must be %d pixels, or both %d for custom emoji", width, height, stickerDimension, customEmojiDimension)
+			}
+		case "animated":
+			if content.size > maxAnimatedStickerSize {
+				v.fail(field, ErrFileTooBig, "animated sticker is %d bytes, the limit is %d", content.size, maxAnimatedStickerSize)
+			}
+			if content.contentType != contentTypeGzip {
+				v.fail(field, ErrUnsupportedFormat, "animated sticker is %s, it must be TGS", content.contentType)
+			}
+		case "video":
+			if content.size > maxVideoStickerSize {
+				v.fail(field, ErrFileTooBig, "video sticker is %d bytes, the limit is %d", content.size, maxVideoStickerSize)
+			}
+			if content.contentType != contentTypeWebM {
+				v.fail(field, ErrUnsupportedFormat, "video sticker is %s, it must be WEBM", content.contentType)
+			}
+		}
+	})
+}
+
+// inspect calls check with the content of data, if it can be inspected.
+func (v *uploadValidator) inspect(field string, data RequestFileData, check func(*uploadContent)) {
+	if data == nil || !data.NeedsUpload() {
+		return
+	}
+
+	content, err := openUploadContent(data)
+	if err != nil {
+		v.errs = append(v.errs, &UploadError{Field: field, Err: err})
+		return
+	}
+	if content == nil {
+		return
+	}
+	defer content.close()
+
+	check(content)
+}
+
+// uploadContent gives random access to the content of an upload.
+type uploadContent struct {
+	reader      *io.SectionReader
+	size        int64
+	contentType string
+	close       func() error
+}
+
+// openUploadContent opens the content of data without consuming it, or
+// returns nil if that is not possible.
+func openUploadContent(data RequestFileData) (*uploadContent, error) {
+	content := &uploadContent{close: func() error { return nil }}
+
+	switch data := data.(type) {
+	case FileBytes:
+		content.reader = io.NewSectionReader(bytes.NewReader(data.Bytes), 0, int64(len(data.Bytes)))
+	case FilePath:
+		f, err := os.Open(string(data))
+		if err != nil {
+			return nil, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		content.reader = io.NewSectionReader(f, 0, info.Size())
+		content.close = f.Close
+	case FileReader:
+		reader, ok := data.Reader.(interface {
+			io.ReaderAt
+			io.Seeker
+		})
+		if !ok {
+			return nil, nil
+		}
+		size, ok := readerSize(data.Reader)
+		if !ok {
+			return nil, nil
+		}
+		offset, err := reader.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, nil
+		}
+		content.reader = io.NewSectionReader(reader, offset, size)
+	case FileFS, FileOpener:
+		_, reader, err := data.UploadData()
+		if err != nil {
+			return nil, err
+		}
+		content.close = func() error { return closeUploadReader(reader) }
+		size, ok := readerSize(reader)
+		seeker, seekable := reader.(interface {
+			io.ReaderAt
+			io.Seeker
+		})
+		if !ok || !seekable {
+			content.close()
+			return nil, nil
+		}
+		content.reader = io.NewSectionReader(seeker, 0, size)
+	default:
+		return nil, nil
+	}
+
+	content.size = content.reader.Size()
+
+	header := make([]byte, 512)
+	n, err := content.reader.ReadAt(header, 0)
+	if err != nil && err != io.EOF {
+		content.close()
+		return nil, err
+	}
+	content.contentType = http.DetectContentType(header[:n])
+
+	return content, nil
+}
+
+func (c *uploadContent) isImage() bool {
+	return strings.HasPrefix(c.contentType, "image/")
+}
+
+// imageDimensions returns the width and height of an image, if its format
+// is known.
+func (c *uploadContent) imageDimensions() (int, int, bool) {
+	if c.contentType == contentTypeWebP {
+		return webpDimensions(c.reader)
+	}
+
+	config, _, err := image.DecodeConfig(io.NewSectionReader(c.reader, 0, c.size))
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return config.Width, config.Height, true
+}
+
+// webpDimensions reads the dimensions of a WebP image from its header.
+func webpDimensions(r io.ReaderAt) (int, int, bool) {
+	header := make([]byte, 30)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return 0, 0, false
+	}
+
+	chunk := header[12:30]
+	switch string(chunk[:4]) {
+	case "VP8 ":
+		// Lossy: a 3 byte frame tag and a 3 byte start code precede the
+		// 14 bit dimensions.
+		width := binary.LittleEndian.Uint16(chunk[14:16]) & 0x3fff
+		height := binary.LittleEndian.Uint16(chunk[16:18]) & 0x3fff
+		return int(width), int(height), true
+	case "VP8L":
+		// Lossless: a signature byte precedes the 14 bit dimensions minus one.
+		bits := binary.LittleEndian.Uint32(chunk[9:13])
+		return int(bits&0x3fff) + 1, int(bits>>14&0x3fff) + 1, true
+	case "VP8X":
+		// Extended: the canvas dimensions minus one are 24 bit values.
+		width := uint32(chunk[12]) | uint32(chunk[13])<<8 | uint32(chunk[14])<<16
+		height := uint32(chunk[15]) | uint32(chunk[16])<<8 | uint32(chunk[17])<<16
+		return int(width) + 1, int(height) + 1, true
+	}
+
+	return 0, 0, false
+}
+
+// mp4Dimensions reads the dimensions of the first video track of an MP4
+// file from its track header box.
+func mp4Dimensions(r *io.SectionReader) (int, int, bool) {
+	moov, ok := mp4Box(r, 0, r.Size(), "moov")
+	if !ok {
+		return 0, 0, false
+	}
+
+	for offset := moov.start; offset < moov.end; {
+		trak, ok := mp4Box(r, offset, moov.end, "trak")
+		if !ok {
+			break
+		}
+		offset = trak.end
+
+		tkhd, ok := mp4Box(r, trak.start, trak.end, "tkhd")
+		if !ok {
+			continue
+		}
+
+		version := make([]byte, 1)
+		if _, err := r.ReadAt(version, tkhd.start); err != nil {
+			continue
+		}
+		dimensions := tkhd.start + 76
+		if version[0] == 1 {
+			dimensions = tkhd.start + 88
+		}
+
+		buf := make([]byte, 8)
+		if dimensions+8 > tkhd.end {
+			continue
+		}
+		if _, err := r.ReadAt(buf, dimensions); err != nil {
+			continue
+		}
+
+		// Dimensions are 16.16 fixed point numbers; audio tracks have none.
+		width := binary.BigEndian.Uint32(buf[:4]) >> 16
+		height := binary.BigEndian.Uint32(buf[4:]) >> 16
+		if width > 0 && height > 0 {
+			return int(width), int(height), true
+		}
+	}
+
+	return 0, 0, false
+}
+
+type mp4BoxRange struct {
+	start, end int64
+}
+
+// mp4Box finds the first box of a type between start and end and returns
+// the range of its content.
+func mp4Box(r io.ReaderAt, start, end int64, boxType string) (mp4BoxRange, bool) {
+	header := make([]byte, 16)
+
+	for offset := start; offset+8 <= end; {
+		if _, err := r.ReadAt(header[:8], offset); err != nil {
+			return mp4BoxRange{}, false
+		}
+
+		size := int64(binary.BigEndian.Uint32(header[:4]))
+		headerSize := int64(8)
+		switch size {
+		case 0:
+			size = end - offset
+		case 1:
+			if _, err := r.ReadAt(header[8:16], offset+8); err != nil {
+				return mp4BoxRange{}, false
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			headerSize = 16
+		}
+		if size < headerSize || offset+size > end {
+			return mp4BoxRange{}, false
+		}
+
+		if string(header[4:8]) == boxType {
+			return mp4BoxRange{start: offset + headerSize, end: offset + size}, true
+		}
+		offset += size
+	}
+
+	return mp4BoxRange{}, false
+}
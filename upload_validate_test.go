@@ -0,0 +1,186 @@
+package tgbotapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+func encodeTestImage(t *testing.T, width, height int, format string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	img := image.NewGray(image.Rect(0, 0, width, height))
+
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func testMP4Box(boxType string, content ...[]byte) []byte {
+	body := bytes.Join(content, nil)
+	box := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(box, boxType...), body...)
+}
+
+func encodeTestMP4(width, height int) []byte {
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], uint32(height)<<16)
+
+	return append(
+		testMP4Box("ftyp", []byte("isom\x00\x00\x02\x00isomiso2mp41")),
+		testMP4Box("moov", testMP4Box("trak", testMP4Box("tkhd", tkhd)))...,
+	)
+}
+
+func TestValidateUploadPhoto(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{})
+
+	ok := NewPhoto(1, FileBytes{Name: "ok.jpg", Bytes: encodeTestImage(t, 800, 600, "jpeg")})
+	if err := bot.ValidateUpload(ok); err != nil {
+		t.Fatal(err)
+	}
+
+	wide := NewPhoto(1, FileReader{Name: "wide.png", Reader: bytes.NewReader(encodeTestImage(t, 2100, 100, "png"))})
+	err := bot.ValidateUpload(wide)
+	if !errors.Is(err, ErrInvalidDimensions) {
+		t.Fatalf("expected ErrInvalidDimensions, got %v", err)
+	}
+	var uploadErr *UploadError
+	if !errors.As(err, &uploadErr) || uploadErr.Field != "photo" {
+		t.Fatalf("expected an UploadError for the photo, got %v", err)
+	}
+
+	text := NewPhoto(1, FileBytes{Name: "notes.txt", Bytes: []byte("not an image")})
+	if err := bot.ValidateUpload(text); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+
+	if err := bot.ValidateUpload(NewPhoto(1, FileID("id"))); err != nil {
+		t.Fatalf("expected file IDs not to be checked, got %v", err)
+	}
+}
+
+func TestValidateUploadThumbnailsAndMedia(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{})
+
+	document := NewDocument(1, FilePath("tests/image.jpg"))
+	document.Thumb = FileBytes{Name: "thumb.png", Bytes: encodeTestImage(t, 400, 400, "png")}
+	err := bot.ValidateUpload(document)
+	if !errors.Is(err, ErrUnsupportedFormat) || !strings.Contains(err.Error(), "thumbnail:") {
+		t.Fatalf("expected the PNG thumbnail to be rejected, got %v", err)
+	}
+
+	photo := NewInputMediaPhoto(FileBytes{Name: "ok.jpg", Bytes: encodeTestImage(t, 100, 100, "jpeg")})
+	video := NewInputMediaVideo(FileID("video"))
+	video.Thumb = FileBytes{Name: "thumb.jpg", Bytes: encodeTestImage(t, 640, 360, "jpeg")}
+	err = bot.ValidateUpload(NewMediaGroup(1, []InputMedia{&photo, &video}))
+	if !errors.Is(err, ErrInvalidDimensions) || !strings.Contains(err.Error(), "media[1].thumbnail:") {
+		t.Fatalf("expected the large video thumbnail to be rejected, got %v", err)
+	}
+}
+
+func TestValidateUploadVideoNoteAndStickers(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{})
+
+	if err := bot.ValidateUpload(NewVideoNote(1, 240, FileBytes{Name: "ok.mp4", Bytes: encodeTestMP4(240, 240)})); err != nil {
+		t.Fatal(err)
+	}
+	err := bot.ValidateUpload(NewVideoNote(1, 240, FileBytes{Name: "wide.mp4", Bytes: encodeTestMP4(320, 240)}))
+	if !errors.Is(err, ErrInvalidDimensions) {
+		t.Fatalf("expected a wide video note to be rejected, got %v", err)
+	}
+
+	sticker := UploadStickerConfig{
+		UserID:        1,
+		Sticker:       RequestFile{Name: "sticker", Data: FileBytes{Name: "s.png", Bytes: encodeTestImage(t, 512, 300, "png")}},
+		StickerFormat: "static",
+	}
+	if err := bot.ValidateUpload(sticker); err != nil {
+		t.Fatal(err)
+	}
+	sticker.Sticker.Data = FileBytes{Name: "s.png", Bytes: encodeTestImage(t, 256, 256, "png")}
+	if err := bot.ValidateUpload(sticker); !errors.Is(err, ErrInvalidDimensions) {
+		t.Fatalf("expected a small static sticker to be rejected, got %v", err)
+	}
+	sticker.StickerFormat = "animated"
+	if err := bot.ValidateUpload(sticker); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected a PNG animated sticker to be rejected, got %v", err)
+	}
+}
+
+func TestValidateUploadMaxUploadSize(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{})
+
+	document := NewDocument(1, FileBytes{Name: "big.bin", Bytes: make([]byte, MaxUploadFileSize+1)})
+	if err := bot.ValidateUpload(document); !errors.Is(err, ErrFileTooBig) {
+		t.Fatalf("expected ErrFileTooBig, got %v", err)
+	}
+
+	bot.localMode = true
+	if err := bot.ValidateUpload(document); err != nil {
+		t.Fatalf("expected the local server limit to allow the file, got %v", err)
+	}
+}
+
+func TestValidateUploadMediaGroupFields(t *testing.T) {
+	bot := newFakeBot(fakeHTTPClient{})
+
+	big := FileOpener{
+		Name: "big.bin",
+		Open: func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader("")), nil },
+		Size: MaxUploadFileSize + 1,
+	}
+	small := NewInputMediaDocument(FileBytes{Name: "small.txt", Bytes: []byte("small")})
+	large := NewInputMediaDocument(big)
+	group := NewMediaGroup(1, []InputMedia{&small, &large})
+
+	err := bot.ValidateUpload(group)
+	var uploadErr *UploadError
+	if !errors.As(err, &uploadErr) || !errors.Is(err, ErrFileTooBig) || uploadErr.Field != "media[1]" {
+		t.Fatalf("expected ErrFileTooBig for media[1], got %v", err)
+	}
+
+	document := NewInputMediaDocument(FileBytes{Name: "small.txt", Bytes: []byte("small")})
+	document.Thumb = big
+	group = NewMediaGroup(1, []InputMedia{&document})
+
+	err = bot.ValidateUpload(group)
+	if !errors.As(err, &uploadErr) || uploadErr.Field != "media[0].thumbnail" {
+		t.Fatalf("expected an UploadError for media[0].thumbnail, got %v", err)
+	}
+}
+
+func TestWebPDimensions(t *testing.T) {
+	riff := func(chunk string, data []byte) []byte {
+		header := append([]byte("RIFF\x00\x00\x00\x00WEBP"+chunk), 0, 0, 0, 0)
+		return append(header, data...)
+	}
+
+	tests := map[string][]byte{
+		"lossy":    riff("VP8 ", []byte{0, 0, 0, 0x9d, 0x01, 0x2a, 0x00, 0x02, 0x2c, 0x01}),
+		"lossless": riff("VP8L", []byte{0x2f, 0xff, 0xc1, 0x4a, 0x00, 0, 0, 0, 0, 0}),
+		"extended": riff("VP8X", []byte{0, 0, 0, 0, 0xff, 0x01, 0x00, 0x2b, 0x01, 0x00}),
+	}
+	for name, data := range tests {
+		width, height, ok := webpDimensions(bytes.NewReader(data))
+		if !ok || width != 512 || height != 300 {
+			t.Fatalf("%s: expected 512x300, got %dx%d", name, width, height)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package tgbotapi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidConfig is wrapped by every error returned by Validate.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Limits of request parameters documented by the Bot API.
+const (
+	maxInlineKeyboardButtons = 100
+	maxCallbackDataLength    = 64
+	maxCallbackTextLength    = 200
+	maxPollQuestionLength    = 300
+	maxPollOptionLength      = 100
+	minPollOptions           = 2
+	maxPollOptions           = 12
+	maxInvoiceTitleLength    = 32
+	maxInvoiceDescLength     = 255
+	maxInvoicePayloadLength  = 128
+)
+
+// Validator is implemented by configs which can be checked against the
+// constraints of Telegram before being sent.
+type Validator interface {
+	// Validate checks the config against the constraints of Telegram. All
+	// violations are returned joined, each as a *FieldError.
+	Validate() error
+}
+
+// FieldError describes a parameter of a request which does not meet the
+// constraints of Telegram.
+type FieldError struct {
+	// Field is the parameter, such as "text" or
+	// "reply_markup.inline_keyboard[0][1].callback_data".
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+func (e *FieldError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+type configValidator struct {
+	errs []error
+}
+
+func (v *configValidator) fail(field, format string, args ...any) {
+	v.errs = append(v.errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *configValidator) err() error {
+	return errors.Join(v.errs...)
+}
+
+func (v *configValidator) chat(field string, chat ChatConfig) {
+	if chat.ChatID == 0 && chat.ChannelUsername == "" && chat.SuperGroupUsername == "" {
+		v.fail(field, "is required")
+	}
+}
+
+func (v *configValidator) baseChat(chat BaseChat) {
+	v.chat("chat_id", chat.ChatConfig)
+
+	switch markup := chat.ReplyMarkup.(type) {
+	case InlineKeyboardMarkup:
+		v.inlineKeyboard("reply_markup", &markup)
+	case *InlineKeyboardMarkup:
+		v.inlineKeyboard("reply_markup", markup)
+	}
+}
+
+func (v *configValidator) baseEdit(edit BaseEdit) {
+	switch {
+	case edit.InlineMessageID != "":
+		if edit.ChatID != 0 || edit.ChannelUsername != "" || edit.SuperGroupUsername != "" || edit.MessageID != 0 {
+			v.fail("inline_message_id", "must not be set together with chat_id and message_id")
+		}
+	default:
+		v.chat("chat_id", edit.ChatConfig)
+		if edit.MessageID == 0 {
+			v.fail("message_id", "is required")
+		}
+	}
+
+	v.inlineKeyboard("reply_markup", edit.ReplyMarkup)
+}
+
+func (v *configValidator) inlineKeyboard(field string, markup *InlineKeyboardMarkup) {
+	if markup == nil {
+		return
+	}
+
+	buttons := 0
+	for i, row := range markup.InlineKeyboard {
+		for j, button := range row {
+			buttons++
+			field := fmt.Sprintf("%s.inline_keyboard[%d][%d]", field, i, j)
+			if button.Text == "" {
+				v.fail(field+".text", "is required")
+			}
+			if button.CallbackData != nil {
+				v.length(field+".callback_data", len(*button.CallbackData), 1, maxCallbackDataLength, "bytes")
+			}
+		}
+	}
+	if buttons > maxInlineKeyboardButtons {
+		v.fail(field+".inline_keyboard", "has %d buttons, the limit is %d", buttons, maxInlineKeyboardButtons)
+	}
+}
+
+// text checks the length of a text in characters. Markup of texts with a
+// parse mode does not count.
+func (v *configValidator) text(field, text, parseMode string, minLength, maxLength int) {
+	v.length(field, markupLength(text, parseMode), minLength, maxLength, "characters")
+}
+
+func (v *configValidator) length(field string, n, minLength, maxLength int, unit string) {
+	switch {
+	case n == 0 && minLength > 0:
+		v.fail(field, "is required")
+	case n < minLength:
+		v.fail(field, "is %d %s, the minimum is %d", n, unit, minLength)
+	case n > maxLength:
+		v.fail(field, "is %d %s, the limit is %d", n, unit, maxLength)
+	}
+}
+
+func (v *configValidator) invoice(title, description, payload, currency string, prices []LabeledPrice) {
+	v.length("title", utf16Length(title), 1, maxInvoiceTitleLength, "characters")
+	v.length("description", utf16Length(description), 1, maxInvoiceDescLength, "characters")
+	v.length("payload", len(payload), 1, maxInvoicePayloadLength, "bytes")
+	if currency == "" {
+		v.fail("currency", "is required")
+	}
+
+	switch {
+	case len(prices) == 0:
+		v.fail("prices", "is required")
+	case currency == "XTR" && len(prices) != 1:
+		v.fail("prices", "has %d items, payments in Telegram Stars must have exactly one", len(prices))
+	}
+}
+
+func (config MessageConfig) Validate() error {
+	v := configValidator{}
+	v.baseChat(config.BaseChat)
+	v.text("text", config.Text, config.ParseMode, 1, MaxMessageTextLength)
+	return v.err()
+}
+
+func (config ForwardConfig) Validate() error {
+	v := configValidator{}
+	v.baseChat(config.BaseChat)
+	v.chat("from_chat_id", config.FromChat)
+	if config.MessageID == 0 {
+		v.fail("message_id", "is required")
+	}
+	return v.err()
+}
+
+func (config CopyMessageConfig) Validate() error {
+	v := configValidator{}
+	v.baseChat(config.BaseChat)
+	v.chat("from_chat_id", config.FromChat)
+	if config.MessageID == 0 {
+		v.fail("message_id", "is required")
+	}
+	v.text("caption", config.Caption, config.ParseMode, 0, MaxCaptionLength)
+	return v.err()
+}
+
+func (config EditMessageTextConfig) Validate() error {
+	v := configValidator{}
+	v.baseEdit(config.BaseEdit)
+	v.text("text", config.Text, config.ParseMode, 1, MaxMessageTextLength)
+	return v.err()
+}
+
+func (config EditMessageCaptionConfig) Validate() error {
+	v := configValidator{}
+	v.baseEdit(config.BaseEdit)
+	v.text("caption", config.Caption, config.ParseMode, 0, MaxCaptionLength)
+	return v.err()
+}
+
+func (config EditMessageReplyMarkupConfig) Validate() error {
+	v := configValidator{}
+	v.baseEdit(config.BaseEdit)
+	return v.err()
+}
+
+func (config SendPollConfig) Validate() error {
+	v := configValidator{}
+	v.baseChat(config.BaseChat)
+	v.text("question", config.Question, config.QuestionParseMode, 1, maxPollQuestionLength)
+
+	if n := len(config.Options); n < minPollOptions || n > maxPollOptions {
+		v.fail("options", "has %d options, it must have %d-%d", n, minPollOptions, maxPollOptions)
+	}
+	for i, option := range config.Options {
+		v.text(fmt.Sprintf("options[%d].text", i), option.Text, option.TextParseMode, 1, maxPollOptionLength)
+	}
+	return v.err()
+}
+
+func (config InvoiceConfig) Validate() error {
+	v := configValidator{}
+	v.baseChat(config.BaseChat)
+	v.invoice(config.Title, config.Description, config.Payload, config.Currency, config.Prices)
+	return v.err()
+}
+
+func (config InvoiceLinkConfig) Validate() error {
+	v := configValidator{}
+	v.invoice(config.Title, config.Description, config.Payload, config.Currency, config.Prices)
+	return v.err()
+}
+
+func (config CallbackConfig) Validate() error {
+	v := configValidator{}
+	if config.CallbackQueryID == "" {
+		v.fail("callback_query_id", "is required")
+	}
+	v.length("text", utf16Length(config.Text), 0, maxCallbackTextLength, "characters")
+	return v.err()
+}
@@ -0,0 +1,119 @@
+package tgbotapi
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fieldErrors returns the fields of the *FieldError values joined in err.
+func fieldErrors(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+
+	var fields []string
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("expected a *FieldError, got %T", err)
+		}
+		fields = append(fields, fieldErr.Field)
+	}
+	return fields
+}
+
+func TestValidate(t *testing.T) {
+	buttons := make([]InlineKeyboardButton, 101)
+	for i := range buttons {
+		buttons[i] = NewInlineKeyboardButtonData("button", "data")
+	}
+	longData := NewInlineKeyboardButtonData("long", strings.Repeat("a", 65))
+
+	edit := NewEditMessageText(1, 2, "text")
+	edit.InlineMessageID = "inline"
+
+	quiz := NewPoll(1, "question", NewPollOption(""))
+
+	message := NewMessage(1, "text")
+	message.ReplyMarkup = NewInlineKeyboardMarkup(buttons[:1], []InlineKeyboardButton{longData})
+
+	markdown := NewMessage(1, "*"+strings.Repeat("a", MaxMessageTextLength)+"*")
+	markdown.ParseMode = ModeMarkdownV2
+	longHTML := NewMessage(1, "<b>"+strings.Repeat("a", MaxMessageTextLength+1)+"</b>")
+	longHTML.ParseMode = ModeHTML
+
+	stars := NewInvoice(1, "title", "description", "payload", "", "", "XTR", []LabeledPrice{{Label: "a", Amount: 1}, {Label: "b", Amount: 2}}, nil)
+
+	tests := []struct {
+		name   string
+		config Validator
+		fields []string
+	}{
+		{"valid message", NewMessage(1, "text"), nil},
+		{"channel message", NewMessageToChannel("@channel", "text"), nil},
+		{"missing chat and text", MessageConfig{}, []string{"chat_id", "text"}},
+		{"long text", NewMessage(1, strings.Repeat("a", MaxMessageTextLength+1)), []string{"text"}},
+		{"markup not counted", markdown, nil},
+		{"long text with parse mode", longHTML, []string{"text"}},
+		{"invalid buttons", message, []string{"reply_markup.inline_keyboard[1][0].callback_data"}},
+		{"too many buttons", NewEditMessageReplyMarkup(1, 2, NewInlineKeyboardMarkup(buttons)), []string{"reply_markup.inline_keyboard"}},
+		{"inline and chat edit", edit, []string{"inline_message_id"}},
+		{"edit without message", NewEditMessageCaption(1, 0, "caption"), []string{"message_id"}},
+		{"poll with one option", quiz, []string{"options", "options[0].text"}},
+		{"valid poll", NewPoll(1, "question", NewPollOption("yes"), NewPollOption("no")), nil},
+		{"invoice without prices", NewInvoice(1, "title", "description", "payload", "token", "", "USD", nil, nil), []string{"prices"}},
+		{"stars invoice with two prices", stars, []string{"prices"}},
+		{"empty invoice link", InvoiceLinkConfig{}, []string{"title", "description", "payload", "currency", "prices"}},
+		{"callback without query", NewCallback("", "text"), []string{"callback_query_id"}},
+		{"forward without source", ForwardConfig{BaseChat: BaseChat{ChatConfig: ChatConfig{ChatID: 1}}}, []string{"from_chat_id", "message_id"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fields := fieldErrors(t, test.config.Validate())
+			if !slices.Equal(fields, test.fields) {
+				t.Fatalf("expected errors for %q, got %q", test.fields, fields)
+			}
+		})
+	}
+}
+
+func TestWithRequestValidation(t *testing.T) {
+	requests := 0
+	client := fakeHTTPClient{do: func(*http.Request) (*http.Response, error) {
+		requests++
+		return okGetMeResponse(), nil
+	}}
+
+	bot, err := NewBotAPIWithOptions("token", WithHTTPClient(client), WithRequestValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = bot.Send(NewMessage(1, ""))
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	if err.Error() != "text: is required" {
+		t.Fatalf("unexpected error %q", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected only the getMe request, got %d requests", requests)
+	}
+
+	bot.validate = false
+	if _, err := bot.Request(NewMessage(1, "")); err != nil {
+		t.Fatalf("expected requests not to be validated without the option, got %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected the request to be sent, got %d requests", requests)
+	}
+}